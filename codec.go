@@ -0,0 +1,50 @@
+package strategy
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PlanSchemaVersion is the version EncodePlan writes and the newest one
+// DecodePlan reads. Bump it whenever the JSON layout changes incompatibly.
+const PlanSchemaVersion = 1
+
+// versionedPlan is the JSON form of a plan: its fields plus a schemaVersion
+type versionedPlan struct {
+	SchemaVersion int `json:"schemaVersion"`
+	*PositionPlan
+}
+
+// EncodePlan marshals plan to JSON tagged with PlanSchemaVersion
+func EncodePlan(plan *PositionPlan) ([]byte, error) {
+	if plan == nil {
+		return nil, fmt.Errorf("plan is nil")
+	}
+	return json.Marshal(versionedPlan{SchemaVersion: PlanSchemaVersion, PositionPlan: plan})
+}
+
+// DecodePlan unmarshals a plan written by EncodePlan. The schema version is
+// checked before the plan itself is decoded, so data from a newer (or
+// unversioned) writer is rejected instead of being silently misread.
+func DecodePlan(data []byte) (*PositionPlan, error) {
+	var header struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to decode plan: %w", err)
+	}
+	switch {
+	case header.SchemaVersion == 0:
+		return nil, fmt.Errorf("plan has no schema version")
+	case header.SchemaVersion > PlanSchemaVersion:
+		return nil, fmt.Errorf("unsupported plan schema version %d (newest supported is %d)", header.SchemaVersion, PlanSchemaVersion)
+	case header.SchemaVersion < 1:
+		return nil, fmt.Errorf("invalid plan schema version %d", header.SchemaVersion)
+	}
+
+	v := versionedPlan{PositionPlan: &PositionPlan{}}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode plan: %w", err)
+	}
+	return v.PositionPlan, nil
+}
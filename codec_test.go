@@ -0,0 +1,66 @@
+package strategy
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecodePlan(t *testing.T) {
+	plan := testPlan()
+
+	data, err := EncodePlan(plan)
+	if err != nil {
+		t.Fatalf("EncodePlan() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"schemaVersion":1`) {
+		t.Errorf("EncodePlan() = %s, want schemaVersion 1", data)
+	}
+
+	got, err := DecodePlan(data)
+	if err != nil {
+		t.Fatalf("DecodePlan() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, plan) {
+		t.Errorf("DecodePlan() = %+v, want %+v", got, plan)
+	}
+}
+
+func TestDecodePlan_Versions(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		wantErr string
+	}{
+		{name: "Future version", version: PlanSchemaVersion + 1, wantErr: "unsupported plan schema version 2"},
+		{name: "Missing version", version: 0, wantErr: "no schema version"},
+		{name: "Negative version", version: -1, wantErr: "invalid plan schema version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(versionedPlan{SchemaVersion: tt.version, PositionPlan: testPlan()})
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			plan, err := DecodePlan(data)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("DecodePlan() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			if plan != nil {
+				t.Errorf("DecodePlan() plan = %+v, want nil on error", plan)
+			}
+		})
+	}
+}
+
+func TestDecodePlan_Malformed(t *testing.T) {
+	if _, err := DecodePlan([]byte("{not json")); err == nil {
+		t.Error("DecodePlan() error = nil, want error for malformed JSON")
+	}
+	if _, err := EncodePlan(nil); err == nil {
+		t.Error("EncodePlan(nil) error = nil, want error")
+	}
+}
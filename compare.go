@@ -0,0 +1,73 @@
+package strategy
+
+// Preference records which of two compared plans wins on a criterion
+type Preference int
+
+const (
+	PreferNeither Preference = iota // Tie, or the criterion is unknown for a plan
+	PreferA
+	PreferB
+)
+
+// leveragePenalty is the composite-score cost of each leverage step above 1x,
+// so 20x leverage costs about 1R of reward
+const leveragePenalty = 0.05
+
+// PlanComparison ranks two plans for scanners choosing between setups
+type PlanComparison struct {
+	BetterRR      Preference // Higher implied RR
+	LowerLeverage Preference
+	SmallerStop   Preference // Smaller stop distance as % of entry
+
+	// Composite score: implied RR minus leveragePenalty per leverage step above 1x
+	ScoreA float64
+	ScoreB float64
+
+	Preferred Preference // Higher composite score
+}
+
+// ComparePlans compares a and b on RR, leverage and stop distance, and
+// combines RR and leverage into a composite score. A plan without a stop or
+// TP scores its leverage penalty only.
+func ComparePlans(a, b *PositionPlan) PlanComparison {
+	ra, rb := NewRiskReport(a), NewRiskReport(b)
+
+	c := PlanComparison{
+		BetterRR: prefer(ra.EffectiveRR, rb.EffectiveRR, true),
+		ScoreA:   compositeScore(a, ra),
+		ScoreB:   compositeScore(b, rb),
+	}
+	if a != nil && b != nil {
+		c.LowerLeverage = prefer(float64(a.Leverage), float64(b.Leverage), false)
+		if a.StopLoss != nil && b.StopLoss != nil {
+			c.SmallerStop = prefer(ra.DistanceToStopPercent, rb.DistanceToStopPercent, false)
+		}
+	}
+	c.Preferred = prefer(c.ScoreA, c.ScoreB, true)
+
+	return c
+}
+
+// compositeScore returns the plan's implied RR less its leverage penalty
+func compositeScore(plan *PositionPlan, report RiskReport) float64 {
+	if plan == nil {
+		return 0
+	}
+	score := report.EffectiveRR
+	if plan.Leverage > 1 {
+		score -= leveragePenalty * float64(plan.Leverage-1)
+	}
+	return score
+}
+
+// prefer picks the larger value when higherIsBetter, else the smaller one
+func prefer(a, b float64, higherIsBetter bool) Preference {
+	switch {
+	case a == b:
+		return PreferNeither
+	case (a > b) == higherIsBetter:
+		return PreferA
+	default:
+		return PreferB
+	}
+}
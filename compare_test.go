@@ -0,0 +1,69 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+)
+
+// comparePlan returns a LONG 45000 entry plan with the given stop distance, RR and leverage
+func comparePlan(stopDistance, rr float64, leverage int) *PositionPlan {
+	plan := testPlan()
+	plan.StopLoss.Price = plan.EntryPrice - stopDistance
+	plan.TakeProfits[0].Price = plan.EntryPrice + stopDistance*rr
+	plan.Leverage = leverage
+	return plan
+}
+
+func TestComparePlans(t *testing.T) {
+	tight := comparePlan(100, 2, 20) // Tight stop, high leverage
+	wide := comparePlan(1000, 2, 2)  // Wide stop, low leverage
+
+	c := ComparePlans(tight, wide)
+
+	if c.BetterRR != PreferNeither {
+		t.Errorf("BetterRR = %v, want PreferNeither for equal RR", c.BetterRR)
+	}
+	if c.LowerLeverage != PreferB {
+		t.Errorf("LowerLeverage = %v, want PreferB", c.LowerLeverage)
+	}
+	if c.SmallerStop != PreferA {
+		t.Errorf("SmallerStop = %v, want PreferA", c.SmallerStop)
+	}
+	if math.Abs(c.ScoreA-1.05) > 1e-9 || math.Abs(c.ScoreB-1.95) > 1e-9 {
+		t.Errorf("scores = %.4f, %.4f, want 1.05, 1.95", c.ScoreA, c.ScoreB)
+	}
+	if c.Preferred != PreferB {
+		t.Errorf("Preferred = %v, want PreferB (wide-stop low-leverage)", c.Preferred)
+	}
+
+	// Swapping the arguments swaps the preference
+	if swapped := ComparePlans(wide, tight); swapped.Preferred != PreferA {
+		t.Errorf("swapped Preferred = %v, want PreferA", swapped.Preferred)
+	}
+}
+
+func TestComparePlans_RROutweighsLeverage(t *testing.T) {
+	tight := comparePlan(100, 3, 20) // Score 3 - 0.95 = 2.05
+	wide := comparePlan(1000, 2, 2)  // Score 2 - 0.05 = 1.95
+
+	c := ComparePlans(tight, wide)
+	if c.BetterRR != PreferA {
+		t.Errorf("BetterRR = %v, want PreferA", c.BetterRR)
+	}
+	if c.Preferred != PreferA {
+		t.Errorf("Preferred = %v, want PreferA (scores %.2f vs %.2f)", c.Preferred, c.ScoreA, c.ScoreB)
+	}
+}
+
+func TestComparePlans_MissingStop(t *testing.T) {
+	noStop := testPlan()
+	noStop.StopLoss = nil
+
+	c := ComparePlans(noStop, testPlan())
+	if c.SmallerStop != PreferNeither {
+		t.Errorf("SmallerStop = %v, want PreferNeither without a stop", c.SmallerStop)
+	}
+	if c.Preferred != PreferB {
+		t.Errorf("Preferred = %v, want PreferB", c.Preferred)
+	}
+}
@@ -0,0 +1,109 @@
+package strategy
+
+import "math"
+
+// SymbolInfo is an exchange's trading constraints for one symbol. Zero
+// fields mean the exchange imposes no such constraint.
+type SymbolInfo struct {
+	TickSize    float64 // Minimum price increment
+	LotSize     float64 // Minimum size increment
+	MinNotional float64 // Minimum order value in quote currency
+}
+
+// ConstraintProvider supplies per-symbol exchange constraints, e.g. from a
+// live exchange-info feed. ok is false for symbols it knows nothing about.
+type ConstraintProvider interface {
+	Constraints(symbol string) (info SymbolInfo, ok bool)
+}
+
+// StaticConstraints is a fixed ConstraintProvider keyed by symbol
+type StaticConstraints map[string]SymbolInfo
+
+// Constraints returns the entry for symbol
+func (c StaticConstraints) Constraints(symbol string) (SymbolInfo, bool) {
+	info, ok := c[symbol]
+	return info, ok
+}
+
+// LeverageBracket is one notional tier of an exchange's leverage schedule:
+// positions up to NotionalCap (quote currency) may use up to MaxLeverage and
+// must keep MaintenanceRate of their notional as maintenance margin
+type LeverageBracket struct {
+	NotionalCap     float64
+	MaxLeverage     int
+	MaintenanceRate float64 // Fraction of notional, e.g. 0.004 = 0.4%
+}
+
+// LeverageBrackets is a tiered leverage schedule, e.g. Binance's, where max
+// leverage falls as notional grows. Order doesn't matter.
+type LeverageBrackets []LeverageBracket
+
+// MaxLeverage returns the max leverage of notional's tier. Returns 0 (no
+// cap) for an empty schedule.
+func (b LeverageBrackets) MaxLeverage(notional float64) int {
+	tier := b.tier(notional)
+	if tier == nil {
+		return 0
+	}
+	return tier.MaxLeverage
+}
+
+// MaintenanceMargin returns the maintenance margin an isolated position of
+// notional must keep under brackets: notional times its tier's
+// MaintenanceRate. It jumps at each tier boundary, since the whole notional
+// moves to the higher rate. Pass it to LiquidationPrice. Returns 0 for an
+// empty schedule.
+func MaintenanceMargin(notional float64, brackets LeverageBrackets) float64 {
+	tier := brackets.tier(notional)
+	if tier == nil {
+		return 0
+	}
+	return math.Abs(notional) * tier.MaintenanceRate
+}
+
+// tier returns the smallest-cap tier covering notional. Notional beyond
+// every tier gets the largest-cap tier, which has the most conservative
+// leverage and maintenance rate. Returns nil for an empty schedule.
+func (b LeverageBrackets) tier(notional float64) *LeverageBracket {
+	var best, last *LeverageBracket
+	for i := range b {
+		tier := &b[i]
+		if last == nil || tier.NotionalCap > last.NotionalCap {
+			last = tier
+		}
+		if math.Abs(notional) <= tier.NotionalCap && (best == nil || tier.NotionalCap < best.NotionalCap) {
+			best = tier
+		}
+	}
+	if best == nil {
+		return last
+	}
+	return best
+}
+
+// RoundDownToStep rounds value down to a multiple of step, so a size never
+// grows past what was requested. A non-positive step returns value unchanged.
+func RoundDownToStep(value, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	return stepMultiple(math.Floor(value/step+lotEpsilon), step)
+}
+
+// RoundToTick rounds price to the nearest multiple of tick. A non-positive
+// tick returns price unchanged.
+func RoundToTick(price, tick float64) float64 {
+	if tick <= 0 {
+		return price
+	}
+	return stepMultiple(math.Round(price/tick), tick)
+}
+
+// stepMultiple returns n * step, dividing by the inverse when step is 1/k
+// for an integer k, so 40 steps of 0.001 is exactly 0.04
+func stepMultiple(n, step float64) float64 {
+	if inv := math.Round(1 / step); inv > 1 && math.Abs(1/step-inv) < lotEpsilon*inv {
+		return n / inv
+	}
+	return n * step
+}
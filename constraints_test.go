@@ -0,0 +1,130 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRoundDownToStep(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float64
+		step  float64
+		want  float64
+	}{
+		{name: "Rounds down", value: 0.0667, step: 0.001, want: 0.066},
+		{name: "Exact multiple survives float noise", value: 0.3, step: 0.1, want: 0.3},
+		{name: "Whole-number step", value: 17.9, step: 5, want: 15},
+		{name: "Below one step", value: 0.0004, step: 0.001, want: 0},
+		{name: "No step", value: 0.0667, step: 0, want: 0.0667},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RoundDownToStep(tt.value, tt.step); got != tt.want {
+				t.Errorf("RoundDownToStep(%v, %v) = %.17g, want %v", tt.value, tt.step, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundToTick(t *testing.T) {
+	tests := []struct {
+		name  string
+		price float64
+		tick  float64
+		want  float64
+	}{
+		{name: "Rounds up to nearest", price: 45599.4, tick: 0.5, want: 45599.5},
+		{name: "Rounds down to nearest", price: 45599.2, tick: 0.5, want: 45599.0},
+		{name: "Sub-cent tick", price: 0.123456, tick: 0.0001, want: 0.1235},
+		{name: "No tick", price: 45599.4, tick: 0, want: 45599.4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RoundToTick(tt.price, tt.tick); got != tt.want {
+				t.Errorf("RoundToTick(%v, %v) = %.17g, want %v", tt.price, tt.tick, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStaticConstraints(t *testing.T) {
+	provider := StaticConstraints{"BTC-USDT": {TickSize: 0.1, LotSize: 0.001, MinNotional: 5}}
+
+	if info, ok := provider.Constraints("BTC-USDT"); !ok || info.LotSize != 0.001 {
+		t.Errorf("Constraints(BTC-USDT) = %+v, %v, want lot size 0.001", info, ok)
+	}
+	if _, ok := provider.Constraints("ETH-USDT"); ok {
+		t.Error("Constraints(ETH-USDT) ok = true, want false for an unknown symbol")
+	}
+}
+
+func TestLeverageBrackets(t *testing.T) {
+	// Listed out of order on purpose
+	brackets := LeverageBrackets{
+		{NotionalCap: 250000, MaxLeverage: 100},
+		{NotionalCap: 50000, MaxLeverage: 125},
+		{NotionalCap: 1000000, MaxLeverage: 50},
+	}
+
+	tests := []struct {
+		name     string
+		notional float64
+		want     int
+	}{
+		{name: "$5k in the first tier", notional: 5000, want: 125},
+		{name: "At a tier cap", notional: 50000, want: 125},
+		{name: "$200k in the second tier", notional: 200000, want: 100},
+		{name: "$1M in the last tier", notional: 1000000, want: 50},
+		{name: "Beyond every tier", notional: 5000000, want: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := brackets.MaxLeverage(tt.notional); got != tt.want {
+				t.Errorf("MaxLeverage(%v) = %d, want %d", tt.notional, got, tt.want)
+			}
+		})
+	}
+
+	if got := LeverageBrackets(nil).MaxLeverage(5000); got != 0 {
+		t.Errorf("empty MaxLeverage() = %d, want 0 (no cap)", got)
+	}
+}
+
+func TestMaintenanceMargin(t *testing.T) {
+	brackets := LeverageBrackets{
+		{NotionalCap: 50000, MaxLeverage: 125, MaintenanceRate: 0.004},
+		{NotionalCap: 250000, MaxLeverage: 100, MaintenanceRate: 0.005},
+	}
+
+	tests := []struct {
+		name     string
+		notional float64
+		want     float64
+	}{
+		{name: "First tier", notional: 10000, want: 40},
+		{name: "At the boundary", notional: 50000, want: 200},
+		{name: "Just past the boundary", notional: 50001, want: 250.005},
+		{name: "Second tier", notional: 200000, want: 1000},
+		{name: "Beyond every tier uses the last", notional: 500000, want: 2500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaintenanceMargin(tt.notional, brackets); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("MaintenanceMargin(%v) = %v, want %v", tt.notional, got, tt.want)
+			}
+		})
+	}
+
+	// Crossing the boundary jumps by far more than the extra dollar of notional
+	if jump := MaintenanceMargin(50001, brackets) - MaintenanceMargin(50000, brackets); jump < 50 {
+		t.Errorf("maintenance jump at the tier boundary = %v, want the 0.1%% rate step on the whole notional", jump)
+	}
+	if got := MaintenanceMargin(10000, nil); got != 0 {
+		t.Errorf("MaintenanceMargin() without brackets = %v, want 0", got)
+	}
+}
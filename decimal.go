@@ -0,0 +1,271 @@
+package strategy
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// DecimalCalculator mirrors the calculator-go sizing, TP and leverage math
+// using exact rational arithmetic, rounding each result once to a fixed
+// number of decimals. Inputs are read as their shortest decimal form, so
+// 0.3 - 0.1 is exactly 0.2 rather than 0.19999999999999998.
+type DecimalCalculator struct {
+	maxLeverage int
+	minLeverage int // Venue floor for leverage; 0 means 1x
+	precision   int // Decimal places kept in sizes and prices
+
+	entryFee         float64 // Fee rate on the entry leg, as a fraction of notional
+	exitFee          float64 // Fee rate on the stop-out leg
+	leverageRounding LeverageRounding
+	contractType     ContractType
+	minNotional      float64 // Sizes below this notional return 0
+	brackets         LeverageBrackets
+}
+
+// LeverageRounding is how CalculateLeverage turns the required notional to
+// balance ratio into a whole leverage
+type LeverageRounding string
+
+const (
+	LeverageRoundUp      LeverageRounding = "up"      // Ceil, so margin always covers the notional (default)
+	LeverageRoundNearest LeverageRounding = "nearest" // Round half up, for venues that top up margin themselves
+)
+
+// ContractType is how a contract's size and PnL are denominated
+type ContractType string
+
+const (
+	// ContractLinear contracts are sized in the base asset and settle in
+	// the quote currency, e.g. BTC-USDT (default)
+	ContractLinear ContractType = "linear"
+	// ContractInverse contracts are sized in quote-currency face value and
+	// settle in the base asset, e.g. BTC-USD coin-margined. Balance and
+	// risk are then in the base asset too.
+	ContractInverse ContractType = "inverse"
+)
+
+// DecimalOption configures a DecimalCalculator
+type DecimalOption func(*DecimalCalculator)
+
+// WithFees makes CalculateSize include round-trip fees in the loss at the
+// stop, as CalculateSizeWithFees does, so a stop-out costs exactly the risk
+// amount fees included
+func WithFees(entryFee, exitFee float64) DecimalOption {
+	return func(c *DecimalCalculator) {
+		c.entryFee = entryFee
+		c.exitFee = exitFee
+	}
+}
+
+// WithLeverageRounding sets how CalculateLeverage rounds the required
+// leverage
+func WithLeverageRounding(rounding LeverageRounding) DecimalOption {
+	return func(c *DecimalCalculator) {
+		c.leverageRounding = rounding
+	}
+}
+
+// WithContractType sets how sizes, PnL and leverage are denominated
+func WithContractType(contractType ContractType) DecimalOption {
+	return func(c *DecimalCalculator) {
+		c.contractType = contractType
+	}
+}
+
+// WithMinNotional makes CalculateSize return 0 when the risk-based size is
+// worth less than minNotional in quote currency, the exchange's minimum order
+func WithMinNotional(minNotional float64) DecimalOption {
+	return func(c *DecimalCalculator) {
+		c.minNotional = minNotional
+	}
+}
+
+// WithMinLeverage sets a floor on CalculateLeverage, for products a venue
+// won't open below a given leverage (e.g. 2x)
+func WithMinLeverage(minLeverage int) DecimalOption {
+	return func(c *DecimalCalculator) {
+		c.minLeverage = minLeverage
+	}
+}
+
+// WithLeverageBrackets makes CalculateLeverage cap leverage at the bracket
+// max for the position's notional, so a large position gets the exchange's
+// lower tier limit automatically
+func WithLeverageBrackets(brackets LeverageBrackets) DecimalOption {
+	return func(c *DecimalCalculator) {
+		c.brackets = brackets
+	}
+}
+
+// NewDecimalCalculator creates a decimal calculator capped at maxLeverage
+// that rounds results to precision decimal places
+func NewDecimalCalculator(maxLeverage, precision int, opts ...DecimalOption) *DecimalCalculator {
+	c := &DecimalCalculator{
+		maxLeverage: maxLeverage,
+		precision:   precision,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CalculateSize returns (balance * risk%) / |entry - stopLoss|, rounded down
+// to the calculator's precision so the position never risks more than
+// requested. For inverse contracts the per-contract loss is
+// |1/entry - 1/stopLoss| instead, and fees set WithFees are added to it.
+// Returns 0 for non-finite inputs, a stop equal to entry, or a size below
+// the minimum notional.
+func (c *DecimalCalculator) CalculateSize(balance, riskPercent, entry, stopLoss float64, side Side) float64 {
+	b, r, e, s := decimalRat(balance), decimalRat(riskPercent), decimalRat(entry), decimalRat(stopLoss)
+	entryFee, exitFee := decimalRat(c.entryFee), decimalRat(c.exitFee)
+	if b == nil || r == nil || e == nil || s == nil || entryFee == nil || exitFee == nil {
+		return 0
+	}
+	if c.contractType == ContractInverse && (e.Sign() <= 0 || s.Sign() <= 0) {
+		return 0
+	}
+
+	var loss, fees *big.Rat
+	if c.contractType == ContractInverse {
+		invE, invS := new(big.Rat).Inv(e), new(big.Rat).Inv(s)
+		loss = new(big.Rat).Sub(invE, invS)
+		fees = new(big.Rat).Add(new(big.Rat).Mul(invE, entryFee), new(big.Rat).Mul(invS, exitFee))
+	} else {
+		loss = new(big.Rat).Sub(e, s)
+		fees = new(big.Rat).Add(new(big.Rat).Mul(e, entryFee), new(big.Rat).Mul(s, exitFee))
+	}
+	loss.Abs(loss)
+	if loss.Sign() == 0 {
+		return 0
+	}
+	loss.Add(loss, fees)
+
+	risk := new(big.Rat).Mul(b, r)
+	risk.Quo(risk, big.NewRat(100, 1))
+	size := c.round(risk.Quo(risk, loss), true)
+
+	notional := size
+	if c.contractType != ContractInverse {
+		notional = size * entry
+	}
+	if notional < c.minNotional {
+		return 0
+	}
+	return size
+}
+
+// CalculateRRTakeProfit returns entry +/- |entry - stopLoss| * rrRatio for
+// LONG/SHORT, rounded to the calculator's precision
+func (c *DecimalCalculator) CalculateRRTakeProfit(entry, stopLoss, rrRatio float64, side Side) float64 {
+	e, s, rr := decimalRat(entry), decimalRat(stopLoss), decimalRat(rrRatio)
+	if e == nil || s == nil || rr == nil {
+		return 0
+	}
+
+	reward := new(big.Rat).Sub(e, s)
+	reward.Abs(reward)
+	reward.Mul(reward, rr)
+	if side == SideShort {
+		reward.Neg(reward)
+	}
+	return c.round(reward.Add(e, reward), false)
+}
+
+// CalculateLeverage returns ceil(size * price / balance), clamped into
+// [minLeverage, limit] where limit is the lower of maxLeverage and the
+// calculator's own cap. The floor is at least 1; if it exceeds the limit,
+// the limit wins so a cap is never broken. With WithLeverageBrackets the
+// limit is also the bracket max for the position's notional. Inverse
+// contracts use size / price / balance, and WithLeverageRounding can
+// replace the ceil.
+func (c *DecimalCalculator) CalculateLeverage(size, price, balance float64, maxLeverage int) int {
+	sz, p, b := decimalRat(size), decimalRat(price), decimalRat(balance)
+	if sz == nil || p == nil || b == nil || b.Sign() <= 0 {
+		return c.leverageFor(nil, maxLeverage)
+	}
+	if c.contractType == ContractInverse && p.Sign() <= 0 {
+		return c.leverageFor(nil, maxLeverage)
+	}
+
+	// Notional in quote currency: contracts are quote face value for inverse
+	notional := size * price
+	var ratio *big.Rat
+	if c.contractType == ContractInverse {
+		notional = size
+		ratio = new(big.Rat).Quo(sz, p)
+	} else {
+		ratio = new(big.Rat).Mul(sz, p)
+	}
+	maxLeverage = EffectiveMaxLeverage(maxLeverage, c.brackets.MaxLeverage(notional))
+	return c.leverageFor(ratio.Quo(ratio, b), maxLeverage)
+}
+
+// LeverageForMarginRatio returns the leverage that carries marginRatio
+// times equity in notional (3.0 means notional = 3 x equity): the ratio
+// rounded up, or per WithLeverageRounding, and clamped into the
+// calculator's [minLeverage, maxLeverage] bounds. Unlike CalculateLeverage
+// it starts from a target exposure rather than a risk-based size.
+func (c *DecimalCalculator) LeverageForMarginRatio(marginRatio float64) int {
+	return c.leverageFor(decimalRat(marginRatio), 0)
+}
+
+// leverageFor rounds a notional to equity ratio to whole leverage and
+// clamps it into [minLeverage, limit], where limit is the lower of
+// maxLeverage (when positive) and the calculator's own cap. A nil ratio
+// returns the floor.
+func (c *DecimalCalculator) leverageFor(ratio *big.Rat, maxLeverage int) int {
+	limit := c.maxLeverage
+	if maxLeverage > 0 && maxLeverage < limit {
+		limit = maxLeverage
+	}
+	floor := min(max(c.minLeverage, 1), limit)
+	if ratio == nil || ratio.Sign() <= 0 {
+		return floor
+	}
+
+	var q *big.Int
+	if c.leverageRounding == LeverageRoundNearest {
+		// floor(ratio + 1/2) for a non-negative rational
+		half := new(big.Rat).Add(ratio, big.NewRat(1, 2))
+		q = new(big.Int).Quo(half.Num(), half.Denom())
+	} else {
+		// ceil for a non-negative rational
+		var rem *big.Int
+		q, rem = new(big.Int).QuoRem(ratio.Num(), ratio.Denom(), new(big.Int))
+		if rem.Sign() != 0 {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	switch {
+	case !q.IsInt64() || q.Int64() > int64(limit):
+		return limit
+	case q.Int64() < int64(floor):
+		return floor
+	default:
+		return int(q.Int64())
+	}
+}
+
+// round converts r to float64 at the calculator's precision, rounding down
+// (toward zero) or to nearest
+func (c *DecimalCalculator) round(r *big.Rat, down bool) float64 {
+	if down {
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.precision)), nil)
+		units := new(big.Int).Mul(r.Num(), scale)
+		units.Quo(units, r.Denom())
+		r = new(big.Rat).SetFrac(units, scale)
+	}
+	f, _ := strconv.ParseFloat(r.FloatString(c.precision), 64)
+	return f
+}
+
+// decimalRat parses f's shortest decimal form, or returns nil for NaN/Inf
+func decimalRat(f float64) *big.Rat {
+	r, ok := new(big.Rat).SetString(formatFloat(f))
+	if !ok {
+		return nil
+	}
+	return r
+}
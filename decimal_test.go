@@ -0,0 +1,232 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/agatticelli/calculator-go"
+)
+
+func TestDecimalCalculator_Exact(t *testing.T) {
+	dec := NewDecimalCalculator(125, 8)
+	calc := calculator.New(125)
+
+	tests := []struct {
+		name        string
+		balance     float64
+		riskPercent float64
+		entry       float64
+		stopLoss    float64
+		rrRatio     float64
+		wantSize    float64
+		wantTP      float64
+	}{
+		// float64 gives 50.000000000000007 and 0.89999999999999991 here
+		{name: "Sub-dollar entry", balance: 1000, riskPercent: 1, entry: 0.3, stopLoss: 0.1, rrRatio: 3, wantSize: 50, wantTP: 0.9},
+		{name: "Fractional risk", balance: 100, riskPercent: 1.1, entry: 0.7, stopLoss: 0.6, rrRatio: 3, wantSize: 11, wantTP: 1.0},
+		{name: "Forex-style quote", balance: 1000, riskPercent: 1, entry: 1.15, stopLoss: 1.05, rrRatio: 3, wantSize: 100, wantTP: 1.45},
+		{name: "BTC", balance: 1000, riskPercent: 2, entry: 45000, stopLoss: 44500, rrRatio: 2, wantSize: 0.04, wantTP: 46000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size := dec.CalculateSize(tt.balance, tt.riskPercent, tt.entry, tt.stopLoss, SideLong)
+			if size != tt.wantSize {
+				t.Errorf("decimal CalculateSize() = %.17g, want exactly %v", size, tt.wantSize)
+			}
+			tp := dec.CalculateRRTakeProfit(tt.entry, tt.stopLoss, tt.rrRatio, SideLong)
+			if tp != tt.wantTP {
+				t.Errorf("decimal CalculateRRTakeProfit() = %.17g, want exactly %v", tp, tt.wantTP)
+			}
+
+			// The float path agrees to within rounding noise
+			floatSize := calc.CalculateSize(tt.balance, tt.riskPercent, tt.entry, tt.stopLoss, SideLong)
+			if math.Abs(floatSize-size) > 1e-9*math.Max(1, size) {
+				t.Errorf("float CalculateSize() = %.17g, too far from decimal %v", floatSize, size)
+			}
+			floatTP := calc.CalculateRRTakeProfit(tt.entry, tt.stopLoss, tt.rrRatio, SideLong)
+			if math.Abs(floatTP-tp) > 1e-9*math.Max(1, tp) {
+				t.Errorf("float CalculateRRTakeProfit() = %.17g, too far from decimal %v", floatTP, tp)
+			}
+		})
+	}
+}
+
+func TestDecimalCalculator_Rounding(t *testing.T) {
+	dec := NewDecimalCalculator(125, 4)
+
+	// 20 / 3 = 6.6666..., sizes round down so risk is never exceeded
+	if size := dec.CalculateSize(1000, 2, 45003, 45000, SideShort); size != 6.6666 {
+		t.Errorf("CalculateSize() = %v, want 6.6666", size)
+	}
+	// Prices round to nearest: 100 - 0.03333 * 2 = 99.93334
+	if tp := dec.CalculateRRTakeProfit(100, 100.03333, 2, SideShort); tp != 99.9333 {
+		t.Errorf("CalculateRRTakeProfit() = %v, want 99.9333", tp)
+	}
+	if size := dec.CalculateSize(1000, 2, 45000, 45000, SideLong); size != 0 {
+		t.Errorf("CalculateSize() with stop at entry = %v, want 0", size)
+	}
+	if size := dec.CalculateSize(math.NaN(), 2, 45000, 44500, SideLong); size != 0 {
+		t.Errorf("CalculateSize() with NaN balance = %v, want 0", size)
+	}
+}
+
+func TestDecimalCalculator_CalculateLeverage(t *testing.T) {
+	dec := NewDecimalCalculator(20, 8)
+
+	tests := []struct {
+		name        string
+		size        float64
+		price       float64
+		balance     float64
+		maxLeverage int
+		want        int
+	}{
+		{name: "Exact multiple", size: 0.2, price: 45000, balance: 1000, maxLeverage: 125, want: 9},
+		{name: "Rounds up", size: 0.21, price: 45000, balance: 1000, maxLeverage: 125, want: 10},
+		{name: "At least 1x", size: 0.01, price: 45000, balance: 1000, maxLeverage: 125, want: 1},
+		{name: "Params cap", size: 0.2, price: 45000, balance: 1000, maxLeverage: 5, want: 5},
+		{name: "Calculator cap", size: 1, price: 45000, balance: 1000, maxLeverage: 125, want: 20},
+		{name: "Huge notional", size: 1e30, price: 45000, balance: 1, maxLeverage: 125, want: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dec.CalculateLeverage(tt.size, tt.price, tt.balance, tt.maxLeverage); got != tt.want {
+				t.Errorf("CalculateLeverage() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalCalculator_MinLeverage(t *testing.T) {
+	tests := []struct {
+		name        string
+		minLeverage int
+		size        float64
+		maxLeverage int
+		want        int
+	}{
+		{name: "Required 1x raised to 2x floor", minLeverage: 2, size: 0.01, maxLeverage: 125, want: 2},
+		{name: "Required 9x above floor", minLeverage: 2, size: 0.2, maxLeverage: 125, want: 9},
+		{name: "Floor above params cap", minLeverage: 10, size: 0.01, maxLeverage: 5, want: 5},
+		{name: "Floor above calculator cap", minLeverage: 50, size: 0.01, maxLeverage: 125, want: 20},
+		{name: "Zero floor means 1x", minLeverage: 0, size: 0.01, maxLeverage: 125, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := NewDecimalCalculator(20, 8, WithMinLeverage(tt.minLeverage))
+			if got := dec.CalculateLeverage(tt.size, 45000, 1000, tt.maxLeverage); got != tt.want {
+				t.Errorf("CalculateLeverage() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if got := NewDecimalCalculator(20, 8, WithMinLeverage(3)).CalculateLeverage(0.01, 45000, 0, 125); got != 3 {
+		t.Errorf("CalculateLeverage() with zero balance = %d, want floor 3", got)
+	}
+}
+
+func TestDecimalCalculator_Options(t *testing.T) {
+	t.Run("WithFees matches CalculateSizeWithFees", func(t *testing.T) {
+		dec := NewDecimalCalculator(125, 8, WithFees(0.0002, 0.0005))
+		want, err := CalculateSizeWithFees(1000, 2, 45000, 44500, SideLong, 0.0002, 0.0005)
+		if err != nil {
+			t.Fatalf("CalculateSizeWithFees() error = %v", err)
+		}
+		got := dec.CalculateSize(1000, 2, 45000, 44500, SideLong)
+		if got > want || want-got > 1e-8 {
+			t.Errorf("CalculateSize() = %.10f, want %.10f rounded down to 8 decimals", got, want)
+		}
+	})
+
+	t.Run("WithLeverageRounding nearest", func(t *testing.T) {
+		// 0.21 * 45000 / 1000 = 9.45x
+		nearest := NewDecimalCalculator(125, 8, WithLeverageRounding(LeverageRoundNearest))
+		if got := nearest.CalculateLeverage(0.21, 45000, 1000, 125); got != 9 {
+			t.Errorf("nearest CalculateLeverage() = %d, want 9", got)
+		}
+		if got := NewDecimalCalculator(125, 8).CalculateLeverage(0.21, 45000, 1000, 125); got != 10 {
+			t.Errorf("default CalculateLeverage() = %d, want 10", got)
+		}
+	})
+
+	t.Run("WithContractType inverse", func(t *testing.T) {
+		// 0.1 BTC balance risking 2% = 0.002 BTC; each 1 USD contract loses
+		// 1/40000 - 1/50000 = 0.000005 BTC at the stop
+		dec := NewDecimalCalculator(125, 8, WithContractType(ContractInverse))
+		if got := dec.CalculateSize(0.1, 2, 50000, 40000, SideLong); got != 400 {
+			t.Errorf("CalculateSize() = %v, want 400 contracts", got)
+		}
+		// 20000 USD of contracts is 0.4 BTC against a 0.1 BTC balance
+		if got := dec.CalculateLeverage(20000, 50000, 0.1, 125); got != 4 {
+			t.Errorf("CalculateLeverage() = %d, want 4", got)
+		}
+	})
+
+	t.Run("WithMinNotional", func(t *testing.T) {
+		// 0.04 BTC at 45000 is 1800 notional
+		if got := NewDecimalCalculator(125, 8, WithMinNotional(2000)).CalculateSize(1000, 2, 45000, 44500, SideLong); got != 0 {
+			t.Errorf("CalculateSize() below min notional = %v, want 0", got)
+		}
+		if got := NewDecimalCalculator(125, 8, WithMinNotional(1000)).CalculateSize(1000, 2, 45000, 44500, SideLong); got != 0.04 {
+			t.Errorf("CalculateSize() above min notional = %v, want 0.04", got)
+		}
+	})
+}
+
+func TestDecimalCalculator_LeverageForMarginRatio(t *testing.T) {
+	tests := []struct {
+		name        string
+		calc        *DecimalCalculator
+		marginRatio float64
+		want        int
+	}{
+		{name: "3.0 target is 3x", calc: NewDecimalCalculator(20, 8), marginRatio: 3.0, want: 3},
+		{name: "2.5 rounds up", calc: NewDecimalCalculator(20, 8), marginRatio: 2.5, want: 3},
+		{name: "2.4 nearest", calc: NewDecimalCalculator(20, 8, WithLeverageRounding(LeverageRoundNearest)), marginRatio: 2.4, want: 2},
+		{name: "Clamped to max", calc: NewDecimalCalculator(20, 8), marginRatio: 50, want: 20},
+		{name: "Clamped to min", calc: NewDecimalCalculator(20, 8, WithMinLeverage(5)), marginRatio: 3.0, want: 5},
+		{name: "Below 1x is 1x", calc: NewDecimalCalculator(20, 8), marginRatio: 0.5, want: 1},
+		{name: "Non-positive ratio", calc: NewDecimalCalculator(20, 8), marginRatio: -3, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.calc.LeverageForMarginRatio(tt.marginRatio); got != tt.want {
+				t.Errorf("LeverageForMarginRatio(%v) = %d, want %d", tt.marginRatio, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalCalculator_LeverageBrackets(t *testing.T) {
+	dec := NewDecimalCalculator(125, 8, WithLeverageBrackets(LeverageBrackets{
+		{NotionalCap: 50000, MaxLeverage: 125},
+		{NotionalCap: 250000, MaxLeverage: 100},
+		{NotionalCap: 1000000, MaxLeverage: 50},
+	}))
+
+	tests := []struct {
+		name        string
+		size        float64
+		balance     float64
+		maxLeverage int
+		want        int
+	}{
+		// Both need more leverage than any tier allows
+		{name: "$5k notional needs 250x", size: 0.1, balance: 20, maxLeverage: 125, want: 125},
+		{name: "$200k notional needs 200x", size: 4, balance: 1000, maxLeverage: 125, want: 100},
+		{name: "Within the bracket", size: 4, balance: 10000, maxLeverage: 125, want: 20},
+		{name: "Params cap below bracket", size: 4, balance: 1000, maxLeverage: 75, want: 75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dec.CalculateLeverage(tt.size, 50000, tt.balance, tt.maxLeverage); got != tt.want {
+				t.Errorf("CalculateLeverage() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
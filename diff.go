@@ -0,0 +1,62 @@
+package strategy
+
+import "fmt"
+
+// FieldDiff is a plan field whose value changed between two plans
+type FieldDiff struct {
+	Field string
+	Old   float64
+	New   float64
+}
+
+// String formats the diff as "field: old -> new"
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %v -> %v", d.Field, d.Old, d.New)
+}
+
+// DiffPlans reports the fields that changed from before to after: entry
+// price, size, leverage, stop loss, each take-profit price and notional, in
+// that order. Values within DefaultPriceEpsilon (relative) of each other are
+// treated as unchanged, so float noise doesn't trigger order amendments. A
+// missing stop or take profit compares as 0. Returns nil when either plan is
+// nil or nothing changed.
+func DiffPlans(before, after *PositionPlan) []FieldDiff {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	var diffs []FieldDiff
+	add := func(field string, a, b float64) {
+		if !pricesEqual(a, b, DefaultPriceEpsilon) {
+			diffs = append(diffs, FieldDiff{Field: field, Old: a, New: b})
+		}
+	}
+
+	add("entry price", before.EntryPrice, after.EntryPrice)
+	add("size", before.Size, after.Size)
+	add("leverage", float64(before.Leverage), float64(after.Leverage))
+	add("stop loss", stopPrice(before), stopPrice(after))
+
+	for i := 0; i < max(len(before.TakeProfits), len(after.TakeProfits)); i++ {
+		add(fmt.Sprintf("take profit %d", i+1), tpPrice(before, i), tpPrice(after, i))
+	}
+
+	add("notional value", before.NotionalValue, after.NotionalValue)
+	return diffs
+}
+
+// stopPrice returns the plan's stop price, or 0 without a stop
+func stopPrice(plan *PositionPlan) float64 {
+	if plan.StopLoss == nil {
+		return 0
+	}
+	return plan.StopLoss.Price
+}
+
+// tpPrice returns the price of the plan's i-th take profit, or 0 when missing
+func tpPrice(plan *PositionPlan, i int) float64 {
+	if i >= len(plan.TakeProfits) || plan.TakeProfits[i] == nil {
+		return 0
+	}
+	return plan.TakeProfits[i].Price
+}
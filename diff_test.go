@@ -0,0 +1,72 @@
+package strategy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffPlans(t *testing.T) {
+	entryMoved := testPlan()
+	entryMoved.EntryPrice = 45100.0
+
+	resized := testPlan()
+	resized.Size = 0.08
+	resized.Leverage = 4
+	resized.NotionalValue = 3600.0
+
+	extraTP := testPlan()
+	extraTP.TakeProfits = append(extraTP.TakeProfits, &TakeProfitLevel{Price: 47000.0, Percentage: 0})
+
+	noise := testPlan()
+	noise.StopLoss.Price = 44500.0 * (1 + 1e-12)
+
+	tests := []struct {
+		name  string
+		after *PositionPlan
+		want  []FieldDiff
+	}{
+		{name: "Identical", after: testPlan(), want: nil},
+		{name: "Float noise ignored", after: noise, want: nil},
+		{
+			name:  "Only entry changed",
+			after: entryMoved,
+			want:  []FieldDiff{{Field: "entry price", Old: 45000.0, New: 45100.0}},
+		},
+		{
+			name:  "Size and leverage changed",
+			after: resized,
+			want: []FieldDiff{
+				{Field: "size", Old: 0.04, New: 0.08},
+				{Field: "leverage", Old: 2, New: 4},
+				{Field: "notional value", Old: 1800.0, New: 3600.0},
+			},
+		},
+		{
+			name:  "Take profit added",
+			after: extraTP,
+			want:  []FieldDiff{{Field: "take profit 2", Old: 0, New: 47000.0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffPlans(testPlan(), tt.after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffPlans() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffPlans_Nil(t *testing.T) {
+	if got := DiffPlans(nil, testPlan()); got != nil {
+		t.Errorf("DiffPlans(nil, plan) = %v, want nil", got)
+	}
+}
+
+func TestFieldDiff_String(t *testing.T) {
+	d := FieldDiff{Field: "leverage", Old: 2, New: 4}
+	if got, want := d.String(), "leverage: 2 -> 4"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,103 @@
+package strategy
+
+import (
+	"math"
+	"sort"
+)
+
+// DrawdownCurve is the shape used to scale risk down as drawdown deepens
+type DrawdownCurve string
+
+const (
+	DrawdownCurveLinear      DrawdownCurve = "linear"
+	DrawdownCurveStep        DrawdownCurve = "step"
+	DrawdownCurveExponential DrawdownCurve = "exponential"
+)
+
+// DrawdownStep applies Multiplier once drawdown reaches AtPercent
+type DrawdownStep struct {
+	AtPercent  float64
+	Multiplier float64
+}
+
+// DrawdownSizer maps the account's current drawdown to a risk multiplier, so
+// any strategy can de-risk during a losing streak:
+//
+//	params.RiskPercent *= sizer.Multiplier(drawdownPercent)
+type DrawdownSizer struct {
+	curve         DrawdownCurve
+	scalePercent  float64        // Linear: zero-risk drawdown; exponential: half-life
+	steps         []DrawdownStep // Step: sorted by AtPercent
+	minMultiplier float64        // Floor for linear and exponential curves
+}
+
+// NewLinearDrawdownSizer scales risk linearly from 1 at no drawdown to 0 at
+// maxDrawdownPercent, never going below minMultiplier
+func NewLinearDrawdownSizer(maxDrawdownPercent, minMultiplier float64) *DrawdownSizer {
+	return &DrawdownSizer{
+		curve:         DrawdownCurveLinear,
+		scalePercent:  maxDrawdownPercent,
+		minMultiplier: minMultiplier,
+	}
+}
+
+// NewStepDrawdownSizer applies the multiplier of the deepest step reached,
+// or 1 before the first step
+func NewStepDrawdownSizer(steps ...DrawdownStep) *DrawdownSizer {
+	sorted := append([]DrawdownStep(nil), steps...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].AtPercent < sorted[j].AtPercent })
+	return &DrawdownSizer{
+		curve: DrawdownCurveStep,
+		steps: sorted,
+	}
+}
+
+// NewExponentialDrawdownSizer halves risk every halfLifePercent of drawdown,
+// never going below minMultiplier
+func NewExponentialDrawdownSizer(halfLifePercent, minMultiplier float64) *DrawdownSizer {
+	return &DrawdownSizer{
+		curve:         DrawdownCurveExponential,
+		scalePercent:  halfLifePercent,
+		minMultiplier: minMultiplier,
+	}
+}
+
+// Curve returns the sizer's curve shape
+func (d *DrawdownSizer) Curve() DrawdownCurve {
+	return d.curve
+}
+
+// Multiplier returns the risk multiplier in [0, 1] for the current drawdown
+// (percent below the equity peak). Negative drawdown counts as none.
+func (d *DrawdownSizer) Multiplier(drawdownPercent float64) float64 {
+	if drawdownPercent <= 0 {
+		return 1
+	}
+
+	var m float64
+	switch d.curve {
+	case DrawdownCurveLinear:
+		if d.scalePercent <= 0 {
+			return 1
+		}
+		m = 1 - drawdownPercent/d.scalePercent
+	case DrawdownCurveExponential:
+		if d.scalePercent <= 0 {
+			return 1
+		}
+		m = math.Pow(0.5, drawdownPercent/d.scalePercent)
+	case DrawdownCurveStep:
+		m = 1
+		for _, step := range d.steps {
+			if drawdownPercent < step.AtPercent {
+				break
+			}
+			m = step.Multiplier
+		}
+		return math.Max(0, math.Min(1, m))
+	default:
+		return 1
+	}
+
+	return math.Max(d.minMultiplier, math.Max(0, m))
+}
@@ -0,0 +1,72 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDrawdownSizer(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizer *DrawdownSizer
+		curve DrawdownCurve
+		want  [3]float64 // At 0%, 10%, 25% drawdown
+	}{
+		{
+			name:  "Linear to zero at 50%",
+			sizer: NewLinearDrawdownSizer(50, 0),
+			curve: DrawdownCurveLinear,
+			want:  [3]float64{1, 0.8, 0.5},
+		},
+		{
+			name:  "Linear with floor",
+			sizer: NewLinearDrawdownSizer(20, 0.25),
+			curve: DrawdownCurveLinear,
+			want:  [3]float64{1, 0.5, 0.25},
+		},
+		{
+			name:  "Step",
+			sizer: NewStepDrawdownSizer(DrawdownStep{AtPercent: 20, Multiplier: 0.5}, DrawdownStep{AtPercent: 10, Multiplier: 0.75}),
+			curve: DrawdownCurveStep,
+			want:  [3]float64{1, 0.75, 0.5},
+		},
+		{
+			name:  "Exponential halving every 10%",
+			sizer: NewExponentialDrawdownSizer(10, 0),
+			curve: DrawdownCurveExponential,
+			want:  [3]float64{1, 0.5, math.Pow(0.5, 2.5)},
+		},
+		{
+			name:  "Exponential with floor",
+			sizer: NewExponentialDrawdownSizer(10, 0.3),
+			curve: DrawdownCurveExponential,
+			want:  [3]float64{1, 0.5, 0.3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.sizer.Curve() != tt.curve {
+				t.Errorf("Curve() = %v, want %v", tt.sizer.Curve(), tt.curve)
+			}
+			for i, dd := range []float64{0, 10, 25} {
+				if got := tt.sizer.Multiplier(dd); math.Abs(got-tt.want[i]) > 1e-9 {
+					t.Errorf("Multiplier(%.0f%%) = %.6f, want %.6f", dd, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDrawdownSizer_EdgeCases(t *testing.T) {
+	linear := NewLinearDrawdownSizer(50, 0)
+	if got := linear.Multiplier(-5); got != 1 {
+		t.Errorf("Multiplier(-5%%) = %v, want 1", got)
+	}
+	if got := linear.Multiplier(80); got != 0 {
+		t.Errorf("Multiplier(80%%) past max = %v, want 0", got)
+	}
+	if got := NewStepDrawdownSizer().Multiplier(25); got != 1 {
+		t.Errorf("Multiplier() with no steps = %v, want 1", got)
+	}
+}
@@ -48,24 +48,7 @@ func main() {
 
 	// Display results
 	fmt.Println("✅ Calculated Position Plan:")
-	fmt.Printf("  Position Size: %.4f %s\n", plan.Size, params.Symbol[:3])
-	fmt.Printf("  Leverage: %dx\n", plan.Leverage)
-	fmt.Printf("  Notional Value: $%.2f\n", plan.NotionalValue)
-	fmt.Printf("  Risk Amount: $%.2f (%.1f%% of balance)\n\n", plan.RiskAmount, plan.RiskPercent)
-
-	fmt.Println("📍 Stop Loss:")
-	if plan.StopLoss != nil {
-		fmt.Printf("  Price: $%.2f\n", plan.StopLoss.Price)
-		fmt.Printf("  Type: %s\n\n", plan.StopLoss.Type)
-	}
-
-	fmt.Println("🎯 Take Profit Levels:")
-	for i, tp := range plan.TakeProfits {
-		fmt.Printf("  TP #%d:\n", i+1)
-		fmt.Printf("    Price: $%.2f\n", tp.Price)
-		fmt.Printf("    Close: %.0f%% of position\n", tp.Percentage)
-		fmt.Printf("    Type: %s\n", tp.Type)
-	}
+	fmt.Println(strategy.PlanSummary(plan))
 
 	// Calculate expected profits
 	slDistance := params.EntryPrice - params.StopLoss
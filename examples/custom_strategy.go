@@ -10,6 +10,8 @@ import (
 
 // ConservativeStrategy is a custom strategy with conservative risk management
 type ConservativeStrategy struct {
+	strategy.NoParameters // No configurable parameters
+
 	calculator *calculator.Calculator
 	rrRatio    float64
 	maxRisk    float64
@@ -81,7 +83,7 @@ func (s *ConservativeStrategy) CalculatePosition(ctx context.Context, params str
 				Type:       strategy.TakeProfitTypeLimit,
 			},
 		},
-		RiskAmount:    params.AccountBalance * riskPercent / 100,
+		RiskAmount:    strategy.RiskAmount(params.AccountBalance, riskPercent),
 		RiskPercent:   riskPercent,
 		NotionalValue: size * params.EntryPrice,
 		StrategyName:  s.Name(),
@@ -0,0 +1,95 @@
+package strategy
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// planColumns is the stable column order used by WritePlansCSV and WritePlansTSV
+var planColumns = []string{
+	"symbol",
+	"side",
+	"size",
+	"leverage",
+	"entry",
+	"stop",
+	"first_tp",
+	"risk_amount",
+	"risk_percent",
+	"notional",
+	"strategy",
+	"timestamp",
+}
+
+// WritePlansCSV writes a header row and one comma-separated row per plan.
+// Plans without a stop loss or take profit leave those cells empty.
+func WritePlansCSV(w io.Writer, plans []*PositionPlan) error {
+	return writePlans(w, plans, ',')
+}
+
+// WritePlansTSV is WritePlansCSV with tab-separated columns
+func WritePlansTSV(w io.Writer, plans []*PositionPlan) error {
+	return writePlans(w, plans, '\t')
+}
+
+func writePlans(w io.Writer, plans []*PositionPlan, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	if err := cw.Write(planColumns); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for i, plan := range plans {
+		if plan == nil {
+			return fmt.Errorf("plan %d is nil", i)
+		}
+		if err := cw.Write(planRecord(plan)); err != nil {
+			return fmt.Errorf("failed to write plan %d: %w", i, err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// planRecord flattens a plan into planColumns order
+func planRecord(plan *PositionPlan) []string {
+	stop := ""
+	if plan.StopLoss != nil {
+		stop = formatFloat(plan.StopLoss.Price)
+	}
+
+	firstTP := ""
+	if len(plan.TakeProfits) > 0 && plan.TakeProfits[0] != nil {
+		firstTP = formatFloat(plan.TakeProfits[0].Price)
+	}
+
+	timestamp := ""
+	if !plan.Timestamp.IsZero() {
+		timestamp = plan.Timestamp.Format(time.RFC3339)
+	}
+
+	return []string{
+		plan.Symbol,
+		string(plan.Side),
+		formatFloat(plan.Size),
+		strconv.Itoa(plan.Leverage),
+		formatFloat(plan.EntryPrice),
+		stop,
+		firstTP,
+		formatFloat(plan.RiskAmount),
+		formatFloat(plan.RiskPercent),
+		formatFloat(plan.NotionalValue),
+		plan.StrategyName,
+		timestamp,
+	}
+}
+
+// formatFloat formats f with the fewest digits that round-trip
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
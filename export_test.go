@@ -0,0 +1,79 @@
+package strategy
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestWritePlansCSV(t *testing.T) {
+	short := &PositionPlan{
+		Symbol:        "ETH-USDT",
+		Side:          SideShort,
+		Size:          0.2,
+		EntryPrice:    3000.0,
+		Leverage:      1,
+		RiskAmount:    20.0,
+		RiskPercent:   2.0,
+		NotionalValue: 600.0,
+		StrategyName:  "custom",
+	}
+
+	var buf bytes.Buffer
+	if err := WritePlansCSV(&buf, []*PositionPlan{testPlan(), short}); err != nil {
+		t.Fatalf("WritePlansCSV() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("len(records) = %d, want 3 (header + 2 plans)", len(records))
+	}
+
+	wantHeader := "symbol,side,size,leverage,entry,stop,first_tp,risk_amount,risk_percent,notional,strategy,timestamp"
+	if got := strings.Join(records[0], ","); got != wantHeader {
+		t.Errorf("header = %q, want %q", got, wantHeader)
+	}
+
+	wantRows := [][]string{
+		{"BTC-USDT", "LONG", "0.04", "2", "45000", "44500", "46000", "20", "2", "1800", "risk-ratio", "2025-01-02T15:04:05Z"},
+		// No stop, TP or timestamp: those cells are left empty
+		{"ETH-USDT", "SHORT", "0.2", "1", "3000", "", "", "20", "2", "600", "custom", ""},
+	}
+	for i, want := range wantRows {
+		got := records[i+1]
+		if len(got) != len(want) {
+			t.Fatalf("row %d has %d columns, want %d", i+1, len(got), len(want))
+		}
+		for col := range want {
+			if got[col] != want[col] {
+				t.Errorf("row %d %s = %q, want %q", i+1, records[0][col], got[col], want[col])
+			}
+		}
+	}
+}
+
+func TestWritePlansTSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePlansTSV(&buf, []*PositionPlan{testPlan()}); err != nil {
+		t.Fatalf("WritePlansTSV() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("len(lines) = %d, want 2", len(lines))
+	}
+	if cols := strings.Split(lines[1], "\t"); len(cols) != 12 {
+		t.Errorf("len(columns) = %d, want 12", len(cols))
+	}
+}
+
+func TestWritePlansCSV_NilPlan(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePlansCSV(&buf, []*PositionPlan{testPlan(), nil}); err == nil {
+		t.Error("WritePlansCSV() error = nil, want error for nil plan")
+	}
+}
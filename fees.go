@@ -0,0 +1,71 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+)
+
+// Fee rates in this file are fractions of notional (0.0002 = 0.02%). Entry
+// and exit are charged separately because they are often different order
+// types, e.g. a maker limit entry and a taker stop exit.
+
+// RoundTripFees returns the fees for opening size at entry and closing it at
+// exit: size*entry*entryFee + size*exit*exitFee
+func RoundTripFees(size, entry, exit, entryFee, exitFee float64) float64 {
+	return math.Abs(size) * (entry*entryFee + exit*exitFee)
+}
+
+// CalculateSizeWithFees returns the risk-based size whose total loss at the
+// stop, fees on both legs included, equals balance * risk%. Each unit loses
+// the stop distance plus entry*entryFee plus stopLoss*exitFee, so fees shrink
+// the size compared to CalculateSize.
+func CalculateSizeWithFees(balance, riskPercent, entry, stopLoss float64, side Side, entryFee, exitFee float64) (float64, error) {
+	if balance <= 0 || riskPercent <= 0 || entry <= 0 || stopLoss <= 0 {
+		return 0, fmt.Errorf("balance, risk percent and prices must be positive")
+	}
+	if entryFee < 0 || exitFee < 0 {
+		return 0, fmt.Errorf("fee rates must not be negative, got entry %v and exit %v", entryFee, exitFee)
+	}
+	if err := ValidateStopLoss(side, entry, stopLoss, DefaultPriceEpsilon); err != nil {
+		return 0, err
+	}
+
+	lossPerUnit := math.Abs(entry-stopLoss) + entry*entryFee + stopLoss*exitFee
+	return RiskAmount(balance, riskPercent) / lossPerUnit, nil
+}
+
+// NetPnLAfterFees returns RealizedPnL for closing the whole plan at exitPrice
+// minus the entry and exit fees
+func NetPnLAfterFees(plan *PositionPlan, exitPrice, entryFee, exitFee float64) float64 {
+	if plan == nil {
+		return 0
+	}
+	return RealizedPnL(plan, exitPrice) - RoundTripFees(plan.Size, plan.EntryPrice, exitPrice, entryFee, exitFee)
+}
+
+// ApplyFees sets o.Fees from separate entry and exit fee rates
+func (o *TradeOutcome) ApplyFees(entryFee, exitFee float64) {
+	o.Fees = RoundTripFees(o.Size, o.EntryPrice, o.ExitPrice, entryFee, exitFee)
+}
+
+// NetRR returns the reward-to-risk of a setup once fees are paid on both
+// legs: the TP move minus round-trip fees at the TP, over the stop distance
+// plus round-trip fees at the stop. It is below the gross RR for any nonzero
+// fee and goes negative when fees exceed the reward. Returns 0 for
+// non-positive prices or a stop on the wrong side of entry.
+func NetRR(entry, stopLoss, takeProfit float64, side Side, entryFee, exitFee float64) float64 {
+	if entry <= 0 || stopLoss <= 0 || takeProfit <= 0 {
+		return 0
+	}
+	if ValidateStopLoss(side, entry, stopLoss, DefaultPriceEpsilon) != nil {
+		return 0
+	}
+
+	move := takeProfit - entry
+	if side == SideShort {
+		move = -move
+	}
+	reward := move - RoundTripFees(1, entry, takeProfit, entryFee, exitFee)
+	risk := math.Abs(entry-stopLoss) + RoundTripFees(1, entry, stopLoss, entryFee, exitFee)
+	return reward / risk
+}
@@ -0,0 +1,173 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+const (
+	makerFee = 0.0002 // 0.02%
+	takerFee = 0.0005 // 0.05%
+)
+
+func TestRoundTripFees(t *testing.T) {
+	tests := []struct {
+		name     string
+		entryFee float64
+		exitFee  float64
+		want     float64
+	}{
+		// 0.04 BTC in at 45000, out at 44500
+		{name: "Maker entry, taker exit", entryFee: makerFee, exitFee: takerFee, want: 0.36 + 0.89},
+		{name: "Taker entry, maker exit", entryFee: takerFee, exitFee: makerFee, want: 0.9 + 0.356},
+		{name: "Taker both legs", entryFee: takerFee, exitFee: takerFee, want: 0.9 + 0.89},
+		{name: "No fees", entryFee: 0, exitFee: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RoundTripFees(0.04, 45000.0, 44500.0, tt.entryFee, tt.exitFee)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("RoundTripFees() = %.6f, want %.6f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateSizeWithFees(t *testing.T) {
+	tests := []struct {
+		name     string
+		side     Side
+		entry    float64
+		stopLoss float64
+		entryFee float64
+		exitFee  float64
+		wantSize float64
+		wantErr  bool
+	}{
+		{
+			name: "No fees matches plain sizing", side: SideLong,
+			entry: 45000.0, stopLoss: 44500.0,
+			wantSize: 0.04,
+		},
+		{
+			// Loss per unit: 500 + 45000*0.0002 + 44500*0.0005 = 531.25
+			name: "LONG maker entry, taker exit", side: SideLong,
+			entry: 45000.0, stopLoss: 44500.0, entryFee: makerFee, exitFee: takerFee,
+			wantSize: 20.0 / 531.25,
+		},
+		{
+			// Loss per unit: 100 + 3000*0.0002 + 3100*0.0005 = 102.15
+			name: "SHORT maker entry, taker exit", side: SideShort,
+			entry: 3000.0, stopLoss: 3100.0, entryFee: makerFee, exitFee: takerFee,
+			wantSize: 20.0 / 102.15,
+		},
+		{
+			name: "Invalid: negative fee", side: SideLong,
+			entry: 45000.0, stopLoss: 44500.0, entryFee: -makerFee, exitFee: takerFee,
+			wantErr: true,
+		},
+		{
+			name: "Invalid: stop on wrong side", side: SideLong,
+			entry: 45000.0, stopLoss: 45500.0,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, err := CalculateSizeWithFees(1000.0, 2.0, tt.entry, tt.stopLoss, tt.side, tt.entryFee, tt.exitFee)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CalculateSizeWithFees() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if math.Abs(size-tt.wantSize) > 1e-12 {
+				t.Errorf("size = %.8f, want %.8f", size, tt.wantSize)
+			}
+
+			// A stop-out with fees loses exactly the risk amount
+			loss := size*math.Abs(tt.entry-tt.stopLoss) + RoundTripFees(size, tt.entry, tt.stopLoss, tt.entryFee, tt.exitFee)
+			if math.Abs(loss-20.0) > 1e-9 {
+				t.Errorf("stop-out loss = %.6f, want 20", loss)
+			}
+		})
+	}
+}
+
+func TestNetPnLAfterFees(t *testing.T) {
+	plan := testPlan()
+
+	// TP at 46000: 40 gross, fees 0.04*(45000*0.0002 + 46000*0.0005) = 1.28
+	if got := NetPnLAfterFees(plan, 46000.0, makerFee, takerFee); math.Abs(got-38.72) > 1e-9 {
+		t.Errorf("NetPnLAfterFees() = %.4f, want 38.72", got)
+	}
+	if got := NetPnLAfterFees(nil, 46000.0, makerFee, takerFee); got != 0 {
+		t.Errorf("NetPnLAfterFees(nil) = %v, want 0", got)
+	}
+
+	o := NewTradeOutcome(plan, 46000.0, "take profit", time.Now().UTC())
+	o.ApplyFees(makerFee, takerFee)
+	if math.Abs(o.Fees-1.28) > 1e-9 || math.Abs(o.NetPnL()-38.72) > 1e-9 {
+		t.Errorf("Fees = %.4f, NetPnL() = %.4f, want 1.28 and 38.72", o.Fees, o.NetPnL())
+	}
+}
+
+func TestNetRR(t *testing.T) {
+	tests := []struct {
+		name       string
+		side       Side
+		entry      float64
+		stopLoss   float64
+		takeProfit float64
+		entryFee   float64
+		exitFee    float64
+		want       float64
+	}{
+		{
+			name: "No fees is the gross RR", side: SideLong,
+			entry: 45000.0, stopLoss: 44500.0, takeProfit: 46000.0,
+			want: 2.0,
+		},
+		{
+			// (1000 - 9 - 23) / (500 + 9 + 22.25)
+			name: "LONG maker entry, taker exit", side: SideLong,
+			entry: 45000.0, stopLoss: 44500.0, takeProfit: 46000.0, entryFee: makerFee, exitFee: takerFee,
+			want: 968.0 / 531.25,
+		},
+		{
+			// (200 - 1.5 - 1.4) / (100 + 1.5 + 1.55)
+			name: "SHORT taker both legs", side: SideShort,
+			entry: 3000.0, stopLoss: 3100.0, takeProfit: 2800.0, entryFee: takerFee, exitFee: takerFee,
+			want: 197.1 / 103.05,
+		},
+		{
+			// A 5-tick scalp: 5 - 9 - 22.5025 of fees
+			name: "Fees exceed reward", side: SideLong,
+			entry: 45000.0, stopLoss: 44995.0, takeProfit: 45005.0, entryFee: makerFee, exitFee: takerFee,
+			want: (5.0 - 9.0 - 22.5025) / (5.0 + 9.0 + 22.4975),
+		},
+		{
+			name: "Invalid: stop on wrong side", side: SideLong,
+			entry: 45000.0, stopLoss: 45500.0, takeProfit: 46000.0, entryFee: makerFee, exitFee: takerFee,
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NetRR(tt.entry, tt.stopLoss, tt.takeProfit, tt.side, tt.entryFee, tt.exitFee)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("NetRR() = %.6f, want %.6f", got, tt.want)
+			}
+			if tt.entryFee+tt.exitFee > 0 && tt.want != 0 {
+				gross := NetRR(tt.entry, tt.stopLoss, tt.takeProfit, tt.side, 0, 0)
+				if got >= gross {
+					t.Errorf("NetRR() = %.6f, want below gross RR %.6f", got, gross)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package strategy
+
+import "math"
+
+// RecommendMaxHold returns how many funding periods plan can be held before
+// accumulated funding cost erodes its first-TP profit below 1R.
+//
+// fundingRate is the per-period rate as a fraction (0.0001 = 0.01%), positive
+// when longs pay shorts. Each period costs notional * rate for LONG and
+// notional * -rate for SHORT. Returns math.MaxInt when the position pays no
+// funding (the hold is unbounded), and 0 when the plan has no stop or TP or
+// its reward is already at or below 1R.
+func RecommendMaxHold(plan *PositionPlan, fundingRate float64) int {
+	if plan == nil || plan.StopLoss == nil || len(plan.TakeProfits) == 0 || plan.TakeProfits[0] == nil {
+		return 0
+	}
+
+	risk := plan.Size * math.Abs(plan.EntryPrice-plan.StopLoss.Price)
+	reward := plan.Size * math.Abs(plan.TakeProfits[0].Price-plan.EntryPrice)
+	if reward <= risk {
+		return 0
+	}
+
+	rate := fundingRate
+	if plan.Side == SideShort {
+		rate = -rate
+	}
+	costPerPeriod := plan.Size * plan.EntryPrice * rate
+	if costPerPeriod <= 0 {
+		return math.MaxInt
+	}
+
+	periods := (reward - risk) / costPerPeriod
+	if periods >= math.MaxInt {
+		return math.MaxInt
+	}
+	return int(periods)
+}
@@ -0,0 +1,44 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRecommendMaxHold(t *testing.T) {
+	// testPlan: 1R = $20, first TP = $40, notional $1800
+	short := testPlan()
+	short.Side = SideShort
+	short.StopLoss.Price = 45500.0
+	short.TakeProfits[0].Price = 44000.0
+
+	thin := testPlan()
+	thin.TakeProfits[0].Price = 45400.0 // 0.8R
+
+	noStop := testPlan()
+	noStop.StopLoss = nil
+
+	tests := []struct {
+		name        string
+		plan        *PositionPlan
+		fundingRate float64
+		want        int
+	}{
+		{name: "High funding rate", plan: testPlan(), fundingRate: 0.01, want: 1},        // $18 per period
+		{name: "Typical funding rate", plan: testPlan(), fundingRate: 0.0001, want: 111}, // $0.18 per period
+		{name: "Zero funding is unbounded", plan: testPlan(), fundingRate: 0, want: math.MaxInt},
+		{name: "LONG receiving funding", plan: testPlan(), fundingRate: -0.01, want: math.MaxInt},
+		{name: "SHORT paying funding", plan: short, fundingRate: -0.01, want: 1},
+		{name: "SHORT receiving funding", plan: short, fundingRate: 0.01, want: math.MaxInt},
+		{name: "Reward at or below 1R", plan: thin, fundingRate: 0.0001, want: 0},
+		{name: "No stop", plan: noStop, fundingRate: 0.0001, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RecommendMaxHold(tt.plan, tt.fundingRate); got != tt.want {
+				t.Errorf("RecommendMaxHold() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
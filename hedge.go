@@ -0,0 +1,69 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+)
+
+// CalculateHedgedPosition plans a market-neutral pair: a LONG leg and a SHORT
+// leg (typically on correlated symbols) that risk the same dollar amount.
+//
+// Both legs are planned with strat, then the leg risking more is scaled down
+// so its RiskAmount matches the other; size, notional and risk percent are
+// scaled with it. Leverage is left as planned, which still covers the smaller
+// notional. Use HedgedNotional for the pair's combined exposure.
+func CalculateHedgedPosition(ctx context.Context, strat Strategy, longParams, shortParams PositionParams) (*PositionPlan, *PositionPlan, error) {
+	if longParams.Side != SideLong {
+		return nil, nil, fmt.Errorf("validation failed: long leg side must be LONG, got %s", longParams.Side)
+	}
+	if shortParams.Side != SideShort {
+		return nil, nil, fmt.Errorf("validation failed: short leg side must be SHORT, got %s", shortParams.Side)
+	}
+
+	long, err := strat.CalculatePosition(ctx, longParams)
+	if err != nil {
+		return nil, nil, fmt.Errorf("long leg: %w", err)
+	}
+	short, err := strat.CalculatePosition(ctx, shortParams)
+	if err != nil {
+		return nil, nil, fmt.Errorf("short leg: %w", err)
+	}
+
+	if long.RiskAmount <= 0 || short.RiskAmount <= 0 {
+		return nil, nil, fmt.Errorf("calculation failed: both legs must risk a positive amount, got %.2f and %.2f",
+			long.RiskAmount, short.RiskAmount)
+	}
+
+	switch {
+	case long.RiskAmount > short.RiskAmount:
+		scaleLeg(long, short.RiskAmount/long.RiskAmount)
+	case short.RiskAmount > long.RiskAmount:
+		scaleLeg(short, long.RiskAmount/short.RiskAmount)
+	}
+
+	return long, short, nil
+}
+
+// HedgedNotional returns the combined notional of both legs of a hedge
+func HedgedNotional(long, short *PositionPlan) float64 {
+	var total float64
+	if long != nil {
+		total += long.NotionalValue
+	}
+	if short != nil {
+		total += short.NotionalValue
+	}
+	return total
+}
+
+// scaleLeg scales a plan's size and the values derived from it by factor.
+// factor is at most 1, so Leverage is intentionally kept: the planned
+// leverage still covers the smaller notional, and recomputing it from the
+// notional alone would drop any margin buffer or allowed-leverage snapping
+// the strategy applied.
+func scaleLeg(plan *PositionPlan, factor float64) {
+	plan.Size *= factor
+	plan.NotionalValue *= factor
+	plan.RiskAmount *= factor
+	plan.RiskPercent *= factor
+}
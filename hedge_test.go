@@ -0,0 +1,101 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// sizingStub plans plain risk-based sizes from the params
+type sizingStub struct {
+	stubStrategy
+}
+
+func (s *sizingStub) CalculatePosition(ctx context.Context, params PositionParams) (*PositionPlan, error) {
+	risk := params.AccountBalance * params.RiskPercent / 100
+	size := risk / math.Abs(params.EntryPrice-params.StopLoss)
+	return &PositionPlan{
+		Symbol:        params.Symbol,
+		Side:          params.Side,
+		Size:          size,
+		EntryPrice:    params.EntryPrice,
+		Leverage:      1,
+		StopLoss:      &StopLossLevel{Price: params.StopLoss, Type: StopLossTypeFixed},
+		RiskAmount:    risk,
+		RiskPercent:   params.RiskPercent,
+		NotionalValue: size * params.EntryPrice,
+	}, nil
+}
+
+func TestCalculateHedgedPosition(t *testing.T) {
+	longParams := PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44500.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+	}
+	shortParams := PositionParams{
+		Symbol:         "ETH-USDT",
+		Side:           SideShort,
+		EntryPrice:     3000.0,
+		StopLoss:       3060.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+	}
+	biggerShort := shortParams
+	biggerShort.RiskPercent = 3.0
+	biggerLong := longParams
+	biggerLong.AccountBalance = 4000.0
+
+	tests := []struct {
+		name     string
+		long     PositionParams
+		short    PositionParams
+		wantRisk float64
+	}{
+		{name: "Already matched", long: longParams, short: shortParams, wantRisk: 20.0},
+		{name: "Short leg scaled down", long: longParams, short: biggerShort, wantRisk: 20.0},
+		{name: "Long leg scaled down", long: biggerLong, short: shortParams, wantRisk: 20.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			long, short, err := CalculateHedgedPosition(context.Background(), &sizingStub{}, tt.long, tt.short)
+			if err != nil {
+				t.Fatalf("CalculateHedgedPosition() error = %v", err)
+			}
+
+			for _, leg := range []*PositionPlan{long, short} {
+				if math.Abs(leg.RiskAmount-tt.wantRisk) > 1e-9 {
+					t.Errorf("%s RiskAmount = %.4f, want %.4f", leg.Side, leg.RiskAmount, tt.wantRisk)
+				}
+				// Risk must still match what the size actually loses at the stop
+				stopLoss := leg.Size * math.Abs(leg.EntryPrice-leg.StopLoss.Price)
+				if math.Abs(stopLoss-leg.RiskAmount) > 1e-9 {
+					t.Errorf("%s stop-out loss = %.4f, want RiskAmount %.4f", leg.Side, stopLoss, leg.RiskAmount)
+				}
+				if leg.Leverage != 1 {
+					t.Errorf("%s Leverage = %d, want the planned 1x kept", leg.Side, leg.Leverage)
+				}
+				if math.Abs(leg.NotionalValue-leg.Size*leg.EntryPrice) > 1e-6 {
+					t.Errorf("%s NotionalValue = %.4f, want size * entry", leg.Side, leg.NotionalValue)
+				}
+			}
+
+			want := long.NotionalValue + short.NotionalValue
+			if got := HedgedNotional(long, short); got != want {
+				t.Errorf("HedgedNotional() = %.4f, want %.4f", got, want)
+			}
+		})
+	}
+}
+
+func TestCalculateHedgedPosition_WrongSides(t *testing.T) {
+	params := PositionParams{Side: SideShort, EntryPrice: 45000.0, StopLoss: 45500.0, AccountBalance: 1000.0, RiskPercent: 2.0}
+
+	if _, _, err := CalculateHedgedPosition(context.Background(), &sizingStub{}, params, params); err == nil {
+		t.Error("CalculateHedgedPosition() error = nil, want error for SHORT long leg")
+	}
+}
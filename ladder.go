@@ -0,0 +1,161 @@
+package strategy
+
+import (
+	"math"
+	"sort"
+)
+
+// Distribution is how a TP ladder splits the position across its rungs
+type Distribution string
+
+const (
+	DistributionEqual       Distribution = "equal"  // Same percentage on every rung
+	DistributionFrontLoaded Distribution = "front"  // Linearly more on nearer rungs (n, n-1, ..., 1)
+	DistributionBackLoaded  Distribution = "back"   // Linearly more on further rungs (1, 2, ..., n)
+	DistributionCustom      Distribution = "custom" // Caller-supplied weights, nearest rung first
+)
+
+// GenerateTPLadder splits a position into rungs equal partial take profits
+// spaced evenly in RR up to finalRR (finalRR/rungs, 2*finalRR/rungs, ...,
+// finalRR). The last rung absorbs rounding so percentages sum to exactly 100.
+// Returns nil for non-positive rungs or finalRR, or a stop equal to entry.
+func GenerateTPLadder(entry, stop float64, side Side, finalRR float64, rungs int) []*TakeProfitLevel {
+	return GenerateDistributedTPLadder(entry, stop, side, finalRR, rungs, DistributionEqual, nil)
+}
+
+// GenerateDistributedTPLadder is GenerateTPLadder with the per-rung
+// percentages set by dist instead of equal. weights is only read for
+// DistributionCustom, one positive weight per rung, and is scaled to sum
+// to 100. Also returns nil for an unknown distribution or invalid weights.
+func GenerateDistributedTPLadder(entry, stop float64, side Side, finalRR float64, rungs int, dist Distribution, weights []float64) []*TakeProfitLevel {
+	distance := math.Abs(entry - stop)
+	if rungs <= 0 || finalRR <= 0 || distance == 0 {
+		return nil
+	}
+	percentages := RungPercentages(dist, rungs, weights)
+	if percentages == nil {
+		return nil
+	}
+
+	direction := 1.0
+	if side == SideShort {
+		direction = -1.0
+	}
+
+	tps := make([]*TakeProfitLevel, rungs)
+	for i := range tps {
+		rr := finalRR * float64(i+1) / float64(rungs)
+		tps[i] = &TakeProfitLevel{
+			Price:      entry + direction*distance*rr,
+			Percentage: percentages[i],
+			Type:       TakeProfitTypeLimit,
+		}
+	}
+	return tps
+}
+
+// RungPercentages returns the percentage of the position each of rungs TP
+// rungs closes under dist, nearest rung first. The last rung absorbs
+// rounding so they sum to exactly 100. Returns nil for non-positive rungs,
+// an unknown distribution, or custom weights that aren't one positive
+// finite value per rung.
+func RungPercentages(dist Distribution, rungs int, weights []float64) []float64 {
+	if rungs <= 0 {
+		return nil
+	}
+
+	w := make([]float64, rungs)
+	for i := range w {
+		switch dist {
+		case "", DistributionEqual:
+			w[i] = 1
+		case DistributionFrontLoaded:
+			w[i] = float64(rungs - i)
+		case DistributionBackLoaded:
+			w[i] = float64(i + 1)
+		case DistributionCustom:
+			if len(weights) != rungs || !(weights[i] > 0) || math.IsInf(weights[i], 0) {
+				return nil
+			}
+			w[i] = weights[i]
+		default:
+			return nil
+		}
+	}
+
+	total := 0.0
+	for _, v := range w {
+		total += v
+	}
+	allocated := 0.0
+	for i := range w[:rungs-1] {
+		w[i] = 100 * w[i] / total
+		allocated += w[i]
+	}
+	w[rungs-1] = 100 - allocated
+	return w
+}
+
+// MergeTakeProfits combines levels of the same type whose prices are within
+// half of tickSize of each other (the same price once tick-rounded) into one
+// level carrying their summed percentage, so composed or laddered plans
+// don't place redundant orders. A non-positive tickSize only merges prices
+// equal within DefaultPriceEpsilon. Merged levels keep the price and
+// position of their first occurrence. The input is not modified; nil levels
+// are dropped.
+func MergeTakeProfits(tps []*TakeProfitLevel, tickSize float64) []*TakeProfitLevel {
+	merged := make([]*TakeProfitLevel, 0, len(tps))
+	for _, tp := range tps {
+		if tp == nil {
+			continue
+		}
+
+		var dup *TakeProfitLevel
+		for _, m := range merged {
+			if m.Type == tp.Type && withinHalfTick(m.Price, tp.Price, tickSize) {
+				dup = m
+				break
+			}
+		}
+		if dup != nil {
+			dup.Percentage += tp.Percentage
+			continue
+		}
+
+		level := *tp
+		merged = append(merged, &level)
+	}
+	return merged
+}
+
+// withinHalfTick reports whether a and b are at most half a tick apart, or
+// equal within DefaultPriceEpsilon when tick is non-positive
+func withinHalfTick(a, b, tick float64) bool {
+	if pricesEqual(a, b, DefaultPriceEpsilon) {
+		return true
+	}
+	return tick > 0 && math.Abs(a-b) <= tick/2*(1+lotEpsilon)
+}
+
+// SortTakeProfits orders plan.TakeProfits nearest-first in the direction of
+// profit: ascending price for a LONG, descending for a SHORT. The sort is
+// stable, so levels at the same price keep their relative order. Nil levels
+// sort last.
+func SortTakeProfits(plan *PositionPlan) {
+	if plan == nil {
+		return
+	}
+	tps := plan.TakeProfits
+	move := func(tp *TakeProfitLevel) float64 {
+		if plan.Side == SideShort {
+			return plan.EntryPrice - tp.Price
+		}
+		return tp.Price - plan.EntryPrice
+	}
+	sort.SliceStable(tps, func(i, j int) bool {
+		if tps[i] == nil || tps[j] == nil {
+			return tps[j] == nil && tps[i] != nil
+		}
+		return move(tps[i]) < move(tps[j])
+	})
+}
@@ -0,0 +1,321 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGenerateTPLadder(t *testing.T) {
+	tests := []struct {
+		name       string
+		side       Side
+		stop       float64
+		finalRR    float64
+		rungs      int
+		wantPrices []float64
+	}{
+		{name: "LONG 3:1 in 3 rungs", side: SideLong, stop: 44500.0, finalRR: 3, rungs: 3, wantPrices: []float64{45500, 46000, 46500}},
+		{name: "SHORT 3:1 in 3 rungs", side: SideShort, stop: 45500.0, finalRR: 3, rungs: 3, wantPrices: []float64{44500, 44000, 43500}},
+		{name: "LONG 2:1 in 4 rungs", side: SideLong, stop: 44500.0, finalRR: 2, rungs: 4, wantPrices: []float64{45250, 45500, 45750, 46000}},
+		{name: "Single rung", side: SideLong, stop: 44500.0, finalRR: 2, rungs: 1, wantPrices: []float64{46000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tps := GenerateTPLadder(45000.0, tt.stop, tt.side, tt.finalRR, tt.rungs)
+			if len(tps) != len(tt.wantPrices) {
+				t.Fatalf("len(tps) = %d, want %d", len(tps), len(tt.wantPrices))
+			}
+
+			total := 0.0
+			for i, tp := range tps {
+				if math.Abs(tp.Price-tt.wantPrices[i]) > 1e-6 {
+					t.Errorf("TP %d price = %.2f, want %.2f", i+1, tp.Price, tt.wantPrices[i])
+				}
+				if math.Abs(tp.Percentage-100/float64(tt.rungs)) > 1e-9 {
+					t.Errorf("TP %d percentage = %.4f, want %.4f", i+1, tp.Percentage, 100/float64(tt.rungs))
+				}
+				if tp.Type != TakeProfitTypeLimit {
+					t.Errorf("TP %d type = %v, want %v", i+1, tp.Type, TakeProfitTypeLimit)
+				}
+				total += tp.Percentage
+			}
+
+			if err := ValidateTakeProfits(tps, false); err != nil {
+				t.Errorf("ladder percentages don't cover the position (total %.12f): %v", total, err)
+			}
+		})
+	}
+}
+
+func TestGenerateTPLadder_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		stop    float64
+		finalRR float64
+		rungs   int
+	}{
+		{name: "Zero rungs", stop: 44500.0, finalRR: 3, rungs: 0},
+		{name: "Zero RR", stop: 44500.0, finalRR: 0, rungs: 3},
+		{name: "Stop equals entry", stop: 45000.0, finalRR: 3, rungs: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tps := GenerateTPLadder(45000.0, tt.stop, SideLong, tt.finalRR, tt.rungs); tps != nil {
+				t.Errorf("GenerateTPLadder() = %v, want nil", tps)
+			}
+		})
+	}
+}
+
+func TestRungPercentages(t *testing.T) {
+	tests := []struct {
+		name     string
+		dist     Distribution
+		rungs    int
+		weights  []float64
+		want     []float64
+		wantDesc bool // Percentages strictly decrease with distance
+		wantAsc  bool // Percentages strictly increase with distance
+	}{
+		{name: "Equal", dist: DistributionEqual, rungs: 4, want: []float64{25, 25, 25, 25}},
+		{name: "Empty means equal", dist: "", rungs: 2, want: []float64{50, 50}},
+		{name: "Front-loaded", dist: DistributionFrontLoaded, rungs: 4, want: []float64{40, 30, 20, 10}, wantDesc: true},
+		{name: "Back-loaded", dist: DistributionBackLoaded, rungs: 4, want: []float64{10, 20, 30, 40}, wantAsc: true},
+		{name: "Front-loaded thirds", dist: DistributionFrontLoaded, rungs: 3, want: []float64{50, 100.0 / 3, 100.0 / 6}, wantDesc: true},
+		{name: "Custom weights scaled to 100", dist: DistributionCustom, rungs: 3, weights: []float64{5, 3, 2}, want: []float64{50, 30, 20}, wantDesc: true},
+		{name: "Single rung", dist: DistributionBackLoaded, rungs: 1, want: []float64{100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RungPercentages(tt.dist, tt.rungs, tt.weights)
+			if len(got) != len(tt.want) {
+				t.Fatalf("RungPercentages() = %v, want %v", got, tt.want)
+			}
+
+			total := 0.0
+			for i, p := range got {
+				if math.Abs(p-tt.want[i]) > 1e-9 {
+					t.Errorf("rung %d = %.6f, want %.6f", i+1, p, tt.want[i])
+				}
+				if i > 0 && tt.wantDesc && p >= got[i-1] {
+					t.Errorf("rung %d = %.4f, want below rung %d's %.4f", i+1, p, i, got[i-1])
+				}
+				if i > 0 && tt.wantAsc && p <= got[i-1] {
+					t.Errorf("rung %d = %.4f, want above rung %d's %.4f", i+1, p, i, got[i-1])
+				}
+				total += p
+			}
+			if total != 100 {
+				t.Errorf("total = %.15f, want exactly 100", total)
+			}
+		})
+	}
+}
+
+func TestRungPercentages_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		dist    Distribution
+		rungs   int
+		weights []float64
+	}{
+		{name: "Zero rungs", dist: DistributionEqual, rungs: 0},
+		{name: "Unknown distribution", dist: "middle", rungs: 3},
+		{name: "Custom without weights", dist: DistributionCustom, rungs: 3},
+		{name: "Custom weight count mismatch", dist: DistributionCustom, rungs: 3, weights: []float64{1, 2}},
+		{name: "Custom zero weight", dist: DistributionCustom, rungs: 2, weights: []float64{1, 0}},
+		{name: "Custom NaN weight", dist: DistributionCustom, rungs: 2, weights: []float64{1, math.NaN()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RungPercentages(tt.dist, tt.rungs, tt.weights); got != nil {
+				t.Errorf("RungPercentages() = %v, want nil", got)
+			}
+		})
+	}
+}
+
+func TestGenerateDistributedTPLadder(t *testing.T) {
+	tps := GenerateDistributedTPLadder(45000.0, 44500.0, SideLong, 3, 3, DistributionFrontLoaded, nil)
+	if len(tps) != 3 {
+		t.Fatalf("len(tps) = %d, want 3", len(tps))
+	}
+
+	// Same prices as the equal ladder, only the split changes
+	wantPrices := []float64{45500, 46000, 46500}
+	wantPercentages := []float64{50, 100.0 / 3, 100.0 / 6}
+	for i, tp := range tps {
+		if math.Abs(tp.Price-wantPrices[i]) > 1e-6 || math.Abs(tp.Percentage-wantPercentages[i]) > 1e-9 {
+			t.Errorf("TP %d = %.2f @ %.4f%%, want %.2f @ %.4f%%", i+1, tp.Price, tp.Percentage, wantPrices[i], wantPercentages[i])
+		}
+	}
+	if err := ValidateTakeProfits(tps, false); err != nil {
+		t.Errorf("ladder percentages don't cover the position: %v", err)
+	}
+
+	if got := GenerateDistributedTPLadder(45000.0, 44500.0, SideLong, 3, 3, DistributionCustom, []float64{1}); got != nil {
+		t.Errorf("GenerateDistributedTPLadder() with bad weights = %v, want nil", got)
+	}
+}
+
+func TestMergeTakeProfits(t *testing.T) {
+	limit := func(price, pct float64) *TakeProfitLevel {
+		return &TakeProfitLevel{Price: price, Percentage: pct, Type: TakeProfitTypeLimit}
+	}
+
+	tests := []struct {
+		name string
+		in   []*TakeProfitLevel
+		tick float64
+		want []*TakeProfitLevel
+	}{
+		{
+			name: "Two 25% levels at one price",
+			in:   []*TakeProfitLevel{limit(46000, 25), limit(46000, 25), limit(47000, 50)},
+			want: []*TakeProfitLevel{limit(46000, 50), limit(47000, 50)},
+		},
+		{
+			name: "Float noise still merges",
+			in:   []*TakeProfitLevel{limit(46000, 25), limit(46000.00000001, 25), limit(47000, 50)},
+			want: []*TakeProfitLevel{limit(46000, 50), limit(47000, 50)},
+		},
+		{
+			name: "Non-adjacent duplicates keep first position",
+			in:   []*TakeProfitLevel{limit(47000, 30), limit(46000, 40), limit(47000, 30)},
+			want: []*TakeProfitLevel{limit(47000, 60), limit(46000, 40)},
+		},
+		{
+			name: "Different types stay separate",
+			in:   []*TakeProfitLevel{limit(46000, 50), {Price: 46000, Percentage: 50, Type: TakeProfitTypeTrailing}},
+			want: []*TakeProfitLevel{limit(46000, 50), {Price: 46000, Percentage: 50, Type: TakeProfitTypeTrailing}},
+		},
+		{
+			name: "Nil levels dropped",
+			in:   []*TakeProfitLevel{nil, limit(46000, 100)},
+			want: []*TakeProfitLevel{limit(46000, 100)},
+		},
+		{
+			name: "Within half a 0.5 tick merges",
+			in:   []*TakeProfitLevel{limit(46000, 25), limit(46000.2, 25), limit(46000.25, 25), limit(47000, 25)},
+			tick: 0.5,
+			want: []*TakeProfitLevel{limit(46000, 75), limit(47000, 25)},
+		},
+		{
+			name: "One 0.5 tick apart stays separate",
+			in:   []*TakeProfitLevel{limit(46000, 50), limit(46000.5, 50)},
+			tick: 0.5,
+			want: []*TakeProfitLevel{limit(46000, 50), limit(46000.5, 50)},
+		},
+		{
+			name: "Sub-tick spread without a tick stays separate",
+			in:   []*TakeProfitLevel{limit(46000, 50), limit(46000.2, 50)},
+			want: []*TakeProfitLevel{limit(46000, 50), limit(46000.2, 50)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeTakeProfits(tt.in, tt.tick)
+			if len(got) != len(tt.want) {
+				t.Fatalf("len(MergeTakeProfits()) = %d, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got[i].Price != want.Price || got[i].Type != want.Type || math.Abs(got[i].Percentage-want.Percentage) > 1e-9 {
+					t.Errorf("level %d = %+v, want %+v", i, *got[i], *want)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeTakeProfits_DoesNotModifyInput(t *testing.T) {
+	in := []*TakeProfitLevel{
+		{Price: 46000, Percentage: 25, Type: TakeProfitTypeLimit},
+		{Price: 46000, Percentage: 25, Type: TakeProfitTypeLimit},
+	}
+	MergeTakeProfits(in, 0)
+	if in[0].Percentage != 25 || in[1].Percentage != 25 {
+		t.Errorf("input modified: %+v, %+v", *in[0], *in[1])
+	}
+}
+
+func TestSortTakeProfits(t *testing.T) {
+	limit := func(price, pct float64) *TakeProfitLevel {
+		return &TakeProfitLevel{Price: price, Percentage: pct, Type: TakeProfitTypeLimit}
+	}
+
+	tests := []struct {
+		name       string
+		side       Side
+		entry      float64
+		in         []*TakeProfitLevel
+		wantPrices []float64
+	}{
+		{
+			name:       "LONG nearest first is ascending",
+			side:       SideLong,
+			entry:      45000.0,
+			in:         []*TakeProfitLevel{limit(47000, 25), limit(46000, 50), limit(48000, 25)},
+			wantPrices: []float64{46000, 47000, 48000},
+		},
+		{
+			name:       "SHORT nearest first is descending",
+			side:       SideShort,
+			entry:      3000.0,
+			in:         []*TakeProfitLevel{limit(2700, 25), limit(2900, 50), limit(2800, 25)},
+			wantPrices: []float64{2900, 2800, 2700},
+		},
+		{
+			name:       "Nil levels last",
+			side:       SideLong,
+			entry:      45000.0,
+			in:         []*TakeProfitLevel{nil, limit(47000, 50), limit(46000, 50)},
+			wantPrices: []float64{46000, 47000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := testPlan()
+			plan.Side = tt.side
+			plan.EntryPrice = tt.entry
+			plan.TakeProfits = tt.in
+
+			SortTakeProfits(plan)
+
+			if len(plan.TakeProfits) != len(tt.in) {
+				t.Fatalf("len(TakeProfits) = %d, want %d", len(plan.TakeProfits), len(tt.in))
+			}
+			for i, want := range tt.wantPrices {
+				if plan.TakeProfits[i] == nil || plan.TakeProfits[i].Price != want {
+					t.Errorf("TakeProfits[%d] = %+v, want price %v", i, plan.TakeProfits[i], want)
+				}
+			}
+			for _, tp := range plan.TakeProfits[len(tt.wantPrices):] {
+				if tp != nil {
+					t.Errorf("trailing level = %+v, want nil", *tp)
+				}
+			}
+		})
+	}
+}
+
+func TestSortTakeProfits_Stable(t *testing.T) {
+	plan := testPlan()
+	plan.TakeProfits = []*TakeProfitLevel{
+		{Price: 47000, Percentage: 50, Type: TakeProfitTypeLimit},
+		{Price: 46000, Percentage: 30, Type: TakeProfitTypeLimit},
+		{Price: 46000, Percentage: 20, Type: TakeProfitTypeTrailing},
+	}
+
+	SortTakeProfits(plan)
+	SortTakeProfits(nil)
+
+	if plan.TakeProfits[0].Type != TakeProfitTypeLimit || plan.TakeProfits[1].Type != TakeProfitTypeTrailing {
+		t.Errorf("equal-price levels reordered: %+v, %+v", *plan.TakeProfits[0], *plan.TakeProfits[1])
+	}
+}
@@ -0,0 +1,36 @@
+package strategy
+
+import "math"
+
+// SharpeFromRMultiples returns the mean over the sample standard deviation of
+// per-trade R-multiples. A positive periodsPerYear annualizes the ratio by
+// sqrt(periodsPerYear) (e.g. 252 for one trade per trading day); pass 0 for
+// the raw per-trade ratio. Returns 0 for fewer than two samples or zero
+// dispersion.
+func SharpeFromRMultiples(rs []float64, periodsPerYear float64) float64 {
+	n := len(rs)
+	if n < 2 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, r := range rs {
+		mean += r
+	}
+	mean /= float64(n)
+
+	variance := 0.0
+	for _, r := range rs {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(n - 1)
+	if variance == 0 {
+		return 0
+	}
+
+	sharpe := mean / math.Sqrt(variance)
+	if periodsPerYear > 0 {
+		sharpe *= math.Sqrt(periodsPerYear)
+	}
+	return sharpe
+}
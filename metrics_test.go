@@ -0,0 +1,34 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSharpeFromRMultiples(t *testing.T) {
+	// mean 0.7, squared deviations sum to 9.8, sample variance 9.8/4 = 2.45
+	series := []float64{2, -1, 2, -1, 1.5}
+
+	tests := []struct {
+		name           string
+		rs             []float64
+		periodsPerYear float64
+		want           float64
+	}{
+		{name: "Per-trade", rs: series, periodsPerYear: 0, want: 0.7 / math.Sqrt(2.45)},
+		{name: "Annualized", rs: series, periodsPerYear: 252, want: 0.7 / math.Sqrt(2.45) * math.Sqrt(252)},
+		{name: "Losing series is negative", rs: []float64{-1, -1, 0.5}, periodsPerYear: 0, want: -0.5 / math.Sqrt(0.75)},
+		{name: "Single sample", rs: []float64{2}, periodsPerYear: 0, want: 0},
+		{name: "No samples", rs: nil, periodsPerYear: 0, want: 0},
+		{name: "Zero dispersion", rs: []float64{1, 1, 1}, periodsPerYear: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SharpeFromRMultiples(tt.rs, tt.periodsPerYear)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("SharpeFromRMultiples() = %.9f, want %.9f", got, tt.want)
+			}
+		})
+	}
+}
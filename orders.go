@@ -0,0 +1,370 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrTooManyOrders is returned by CheckMaxOrders, and by PlanOrders with
+// WithMaxOrders, when a plan emits more orders than allowed
+var ErrTooManyOrders = errors.New("too many orders")
+
+// StopOrderKind is how a plan's stop loss is sent to the exchange
+type StopOrderKind string
+
+const (
+	StopMarket StopOrderKind = "STOP_MARKET" // Market order once the stop triggers
+	StopLimit  StopOrderKind = "STOP_LIMIT"  // Limit order at an offset once the stop triggers
+)
+
+// EntryOrderKind is how an entry leg is sent to the exchange
+type EntryOrderKind string
+
+const (
+	EntryMarket EntryOrderKind = "MARKET" // Filled immediately at market
+	EntryLimit  EntryOrderKind = "LIMIT"  // Resting order at Price
+)
+
+// EntryOrder is a broker-agnostic order opening (part of) a planned position
+type EntryOrder struct {
+	Symbol string
+	Side   Side
+	Kind   EntryOrderKind
+	Size   float64
+	Price  float64 // Limit price for EntryLimit; 0 for EntryMarket
+
+	// OCOGroup links orders where filling one cancels the others; empty
+	// means the order stands alone, as entries do
+	OCOGroup string
+
+	// ClientOrderID is a deterministic idempotency key for retries; set by
+	// PlanOrders
+	ClientOrderID string
+}
+
+// TakeProfitOrder is a broker-agnostic take-profit order closing part of a
+// planned position. Like StopOrder it only ever reduces the position, so
+// adapters should send it reduce-only.
+type TakeProfitOrder struct {
+	Symbol   string
+	Side     Side // Side of the position being closed
+	Type     TakeProfitType
+	Size     float64
+	Price    float64
+	OCOGroup string // Shared with the stop order it cancels
+
+	ClientOrderID string // Deterministic idempotency key; set by PlanOrders
+}
+
+// StopOrderConfig selects the stop-loss order sub-type. It is passed alongside
+// the plan because StopLossLevel is shared with trading-common-types.
+type StopOrderConfig struct {
+	Kind StopOrderKind // Empty means StopMarket
+
+	// LimitOffsetPercent places the stop-limit price this far beyond the
+	// stop (below it for a LONG stop, above it for a SHORT one), in % of the
+	// stop price. Ignored for StopMarket unless StopLimitFallback is set.
+	LimitOffsetPercent float64
+
+	// StopLimitFallback makes PlanOrders send every stop-market leg together
+	// with a stop-limit at the same trigger and LimitOffsetPercent, in the
+	// same OCO group, for exchanges that accept both in one bracket
+	StopLimitFallback bool
+}
+
+// StopOrder is a broker-agnostic stop-loss order that closes a planned position
+type StopOrder struct {
+	Symbol    string
+	Side      Side // Side of the position being protected
+	Kind      StopOrderKind
+	Size      float64
+	StopPrice float64 // Trigger price
+	Price     float64 // Limit price for StopLimit; 0 for StopMarket
+	OCOGroup  string  // Shared with the take profit it cancels; set by PlanOrders
+
+	ClientOrderID string // Deterministic idempotency key; set by PlanOrders
+}
+
+// StopLossOrder builds the stop-loss order for plan. Stop-limit orders set
+// both StopPrice and Price; stop-market orders set StopPrice only. Trailing
+// stops can only be sent as stop-market.
+func StopLossOrder(plan *PositionPlan, cfg StopOrderConfig) (*StopOrder, error) {
+	if plan == nil || plan.StopLoss == nil {
+		return nil, fmt.Errorf("plan has no stop loss")
+	}
+
+	kind := cfg.Kind
+	if kind == "" {
+		kind = StopMarket
+	}
+
+	order := &StopOrder{
+		Symbol:    plan.Symbol,
+		Side:      plan.Side,
+		Kind:      kind,
+		Size:      plan.Size,
+		StopPrice: plan.StopLoss.Price,
+	}
+
+	switch kind {
+	case StopMarket:
+		return order, nil
+	case StopLimit:
+		if plan.StopLoss.Type == StopLossTypeTrailing {
+			return nil, fmt.Errorf("trailing stop loss can't be sent as %s", StopLimit)
+		}
+		if cfg.LimitOffsetPercent < 0 || cfg.LimitOffsetPercent >= 100 {
+			return nil, fmt.Errorf("limit offset must be in [0, 100), got %.2f", cfg.LimitOffsetPercent)
+		}
+		offset := cfg.LimitOffsetPercent / 100
+		if plan.Side == SideShort {
+			order.Price = order.StopPrice * (1 + offset)
+		} else {
+			order.Price = order.StopPrice * (1 - offset)
+		}
+		return order, nil
+	default:
+		return nil, fmt.Errorf("unknown stop order kind %q", kind)
+	}
+}
+
+// OrderSet is a plan translated into orders. Stops[i] and TakeProfits[i]
+// form an OCO pair sharing an OCOGroup; when the plan leaves a runner, the
+// last stop protects it alone and has no matching take profit. With
+// StopOrderConfig.StopLimitFallback, FallbackStops[i] is the stop-limit
+// sent alongside Stops[i] in the same group.
+type OrderSet struct {
+	Entries       []*EntryOrder // One per entry leg; a single order unless WithEntryLegs is given
+	Stops         []*StopOrder
+	FallbackStops []*StopOrder
+	TakeProfits   []*TakeProfitOrder
+}
+
+// Count returns the number of orders in the set, entry legs included
+func (s *OrderSet) Count() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.Entries) + len(s.Stops) + len(s.FallbackStops) + len(s.TakeProfits)
+}
+
+// CheckMaxOrders errors with ErrTooManyOrders when set holds more than
+// maxOrders orders. PlanOrders runs it for WithMaxOrders; call it directly
+// for order sets built elsewhere. A non-positive maxOrders means no cap.
+func CheckMaxOrders(set *OrderSet, maxOrders int) error {
+	if maxOrders <= 0 {
+		return nil
+	}
+	if n := set.Count(); n > maxOrders {
+		return fmt.Errorf("%w: plan emits %d orders, max %d", ErrTooManyOrders, n, maxOrders)
+	}
+	return nil
+}
+
+// OrderOption configures PlanOrders
+type OrderOption func(*orderOptions)
+
+// orderOptions holds the settings applied by OrderOption
+type orderOptions struct {
+	maxOrders int
+	entryLegs []EntryLeg
+}
+
+// EntryLeg is one part of a laddered entry: Percentage of the plan's size,
+// filled at market or resting as a limit at Price
+type EntryLeg struct {
+	Kind       EntryOrderKind
+	Price      float64 // Limit price for EntryLimit; ignored for EntryMarket
+	Percentage float64
+}
+
+// WithEntryLegs makes PlanOrders split the entry into legs, e.g. scale-in's
+// market order plus limit ladder, instead of one order at the plan's entry.
+// The entryKind argument is then ignored. Leg percentages must sum to 100.
+func WithEntryLegs(legs ...EntryLeg) OrderOption {
+	return func(o *orderOptions) {
+		o.entryLegs = append([]EntryLeg(nil), legs...)
+	}
+}
+
+// WithMaxOrders makes PlanOrders fail with ErrTooManyOrders when the plan
+// would emit more than maxOrders orders (entry included), as a safety cap so
+// a misconfigured ladder or grid can't flood the exchange. A non-positive
+// maxOrders means no cap.
+func WithMaxOrders(maxOrders int) OrderOption {
+	return func(o *orderOptions) {
+		o.maxOrders = maxOrders
+	}
+}
+
+// PlanOrders translates plan into an entry order (market when entryKind is
+// empty or EntryMarket, otherwise a limit at the plan's entry), or one order
+// per leg with WithEntryLegs, and one stop/TP OCO pair per take profit, each sized to that TP's percentage.
+// Group IDs and client order IDs are derived from the symbol, plan timestamp
+// and leg index, so they are unique per plan and stable across calls for
+// the same plan: resubmitting it after a timeout reuses the same client
+// order IDs, and the exchange rejects the duplicates instead of opening a
+// second position.
+func PlanOrders(plan *PositionPlan, entryKind EntryOrderKind, stopCfg StopOrderConfig, opts ...OrderOption) (*OrderSet, error) {
+	var o orderOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	stop, err := StopLossOrder(plan, stopCfg)
+	if err != nil {
+		return nil, err
+	}
+	var fallback *StopOrder
+	if stopCfg.StopLimitFallback {
+		if stop.Kind != StopMarket {
+			return nil, fmt.Errorf("stop-limit fallback requires a %s primary stop, got %s", StopMarket, stop.Kind)
+		}
+		fallback, err = StopLossOrder(plan, StopOrderConfig{Kind: StopLimit, LimitOffsetPercent: stopCfg.LimitOffsetPercent})
+		if err != nil {
+			return nil, fmt.Errorf("stop-limit fallback: %w", err)
+		}
+	}
+	if err := ValidateTakeProfits(plan.TakeProfits, true); err != nil {
+		return nil, err
+	}
+
+	legs := o.entryLegs
+	if legs == nil {
+		legs = []EntryLeg{{Kind: entryKind, Price: plan.EntryPrice, Percentage: 100}}
+	}
+
+	prefix := fmt.Sprintf("%s-%d", plan.Symbol, plan.Timestamp.UnixNano())
+	set := &OrderSet{}
+	var total float64
+	for i, leg := range legs {
+		entry := &EntryOrder{
+			Symbol:        plan.Symbol,
+			Side:          plan.Side,
+			Kind:          leg.Kind,
+			Size:          plan.Size * leg.Percentage / 100,
+			ClientOrderID: fmt.Sprintf("%s-entry-%d", prefix, i+1),
+		}
+		switch leg.Kind {
+		case "", EntryMarket:
+			entry.Kind = EntryMarket
+		case EntryLimit:
+			if leg.Price <= 0 {
+				return nil, fmt.Errorf("entry leg %d: limit price must be positive, got %v", i+1, leg.Price)
+			}
+			entry.Price = leg.Price
+		default:
+			return nil, fmt.Errorf("unknown entry order kind %q", leg.Kind)
+		}
+		if leg.Percentage <= 0 {
+			return nil, fmt.Errorf("entry leg %d: percentage must be positive, got %.2f", i+1, leg.Percentage)
+		}
+		total += leg.Percentage
+		set.Entries = append(set.Entries, entry)
+	}
+	if len(set.Entries) == 0 || math.Abs(total-100) > percentTolerance {
+		return nil, fmt.Errorf("entry leg percentages must sum to 100, got %.2f", total)
+	}
+
+	addPair := func(percent float64, tp *TakeProfitLevel) {
+		n := len(set.Stops) + 1
+		group := fmt.Sprintf("%s-oco-%d", prefix, n)
+
+		leg := *stop
+		leg.Size = plan.Size * percent / 100
+		leg.OCOGroup = group
+		leg.ClientOrderID = fmt.Sprintf("%s-sl-%d", prefix, n)
+		set.Stops = append(set.Stops, &leg)
+
+		if fallback != nil {
+			fb := *fallback
+			fb.Size = leg.Size
+			fb.OCOGroup = group
+			fb.ClientOrderID = fmt.Sprintf("%s-slx-%d", prefix, n)
+			set.FallbackStops = append(set.FallbackStops, &fb)
+		}
+
+		if tp != nil {
+			set.TakeProfits = append(set.TakeProfits, &TakeProfitOrder{
+				Symbol:        plan.Symbol,
+				Side:          plan.Side,
+				Type:          tp.Type,
+				Size:          leg.Size,
+				Price:         tp.Price,
+				OCOGroup:      group,
+				ClientOrderID: fmt.Sprintf("%s-tp-%d", prefix, n),
+			})
+		}
+	}
+
+	for _, tp := range plan.TakeProfits {
+		addPair(tp.Percentage, tp)
+	}
+	if runner := RunnerPercent(plan); runner > 0 {
+		addPair(runner, nil)
+	}
+
+	if err := CheckMaxOrders(set, o.maxOrders); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// lotEpsilon absorbs float noise when converting sizes to whole lots, so
+// 0.3/0.1 counts as 3 lots rather than 2.9999999999999996
+const lotEpsilon = 1e-9
+
+// RoundToLotSize rounds every order in set down to a multiple of lotSize
+// while keeping the exits summing exactly to the rounded entry size: each
+// entry leg, TP and paired stop rounds down, and the exits' rounding
+// remainder goes to the last exit leg (the runner stop when there is one,
+// otherwise the last TP pair), so no dust is left open. Errors when a leg
+// rounds to zero lots.
+func RoundToLotSize(set *OrderSet, lotSize float64) error {
+	if set == nil || len(set.Entries) == 0 {
+		return fmt.Errorf("order set has no entry order")
+	}
+	if lotSize <= 0 {
+		return fmt.Errorf("lot size must be positive, got %v", lotSize)
+	}
+	if len(set.Stops) == 0 {
+		return fmt.Errorf("order set has no exit orders")
+	}
+
+	toLots := func(size float64) int64 {
+		return int64(math.Floor(size/lotSize + lotEpsilon))
+	}
+
+	var totalLots int64
+	for i, entry := range set.Entries {
+		lots := toLots(entry.Size)
+		if lots == 0 {
+			return fmt.Errorf("entry %d size %v is below lot size %v", i+1, entry.Size, lotSize)
+		}
+		entry.Size = float64(lots) * lotSize
+		totalLots += lots
+	}
+
+	remaining := totalLots
+	last := len(set.Stops) - 1
+	for i, stop := range set.Stops {
+		lots := toLots(stop.Size)
+		if i == last {
+			lots = remaining
+		}
+		if lots <= 0 {
+			return fmt.Errorf("exit %d rounds to zero lots of %v", i+1, lotSize)
+		}
+		remaining -= lots
+
+		stop.Size = float64(lots) * lotSize
+		if i < len(set.FallbackStops) {
+			set.FallbackStops[i].Size = stop.Size
+		}
+		if i < len(set.TakeProfits) {
+			set.TakeProfits[i].Size = stop.Size
+		}
+	}
+	return nil
+}
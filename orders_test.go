@@ -0,0 +1,573 @@
+package strategy
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStopLossOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		side          Side
+		stop          float64
+		cfg           StopOrderConfig
+		wantKind      StopOrderKind
+		wantStopPrice float64
+		wantPrice     float64
+	}{
+		{name: "Default is stop-market", side: SideLong, stop: 44500.0, cfg: StopOrderConfig{}, wantKind: StopMarket, wantStopPrice: 44500.0, wantPrice: 0},
+		{name: "Stop-market ignores offset", side: SideLong, stop: 44500.0, cfg: StopOrderConfig{Kind: StopMarket, LimitOffsetPercent: 0.1}, wantKind: StopMarket, wantStopPrice: 44500.0, wantPrice: 0},
+		{name: "LONG stop-limit below stop", side: SideLong, stop: 44500.0, cfg: StopOrderConfig{Kind: StopLimit, LimitOffsetPercent: 0.2}, wantKind: StopLimit, wantStopPrice: 44500.0, wantPrice: 44411.0},
+		{name: "SHORT stop-limit above stop", side: SideShort, stop: 45500.0, cfg: StopOrderConfig{Kind: StopLimit, LimitOffsetPercent: 0.2}, wantKind: StopLimit, wantStopPrice: 45500.0, wantPrice: 45591.0},
+		{name: "Stop-limit at zero offset", side: SideLong, stop: 44500.0, cfg: StopOrderConfig{Kind: StopLimit}, wantKind: StopLimit, wantStopPrice: 44500.0, wantPrice: 44500.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := testPlan()
+			plan.Side = tt.side
+			plan.StopLoss.Price = tt.stop
+
+			order, err := StopLossOrder(plan, tt.cfg)
+			if err != nil {
+				t.Fatalf("StopLossOrder() error = %v", err)
+			}
+
+			if order.Kind != tt.wantKind {
+				t.Errorf("Kind = %v, want %v", order.Kind, tt.wantKind)
+			}
+			if order.StopPrice != tt.wantStopPrice {
+				t.Errorf("StopPrice = %.2f, want %.2f", order.StopPrice, tt.wantStopPrice)
+			}
+			if math.Abs(order.Price-tt.wantPrice) > 1e-6 {
+				t.Errorf("Price = %.2f, want %.2f", order.Price, tt.wantPrice)
+			}
+			if order.Symbol != plan.Symbol || order.Side != plan.Side || order.Size != plan.Size {
+				t.Errorf("order = %+v, want symbol/side/size copied from plan", order)
+			}
+		})
+	}
+}
+
+func TestStopLossOrder_Invalid(t *testing.T) {
+	noStop := testPlan()
+	noStop.StopLoss = nil
+
+	trailing := testPlan()
+	trailing.StopLoss.Type = StopLossTypeTrailing
+
+	tests := []struct {
+		name string
+		plan *PositionPlan
+		cfg  StopOrderConfig
+	}{
+		{name: "Nil plan", plan: nil, cfg: StopOrderConfig{}},
+		{name: "No stop", plan: noStop, cfg: StopOrderConfig{}},
+		{name: "Trailing stop-limit", plan: trailing, cfg: StopOrderConfig{Kind: StopLimit}},
+		{name: "Negative offset", plan: testPlan(), cfg: StopOrderConfig{Kind: StopLimit, LimitOffsetPercent: -1}},
+		{name: "Unknown kind", plan: testPlan(), cfg: StopOrderConfig{Kind: "STOP_ICEBERG"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := StopLossOrder(tt.plan, tt.cfg); err == nil {
+				t.Error("StopLossOrder() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestPlanOrders(t *testing.T) {
+	multiTP := testPlan()
+	multiTP.TakeProfits = []*TakeProfitLevel{
+		{Price: 45500.0, Percentage: 50, Type: TakeProfitTypeLimit},
+		{Price: 46500.0, Percentage: 30, Type: TakeProfitTypeLimit},
+	}
+
+	tests := []struct {
+		name       string
+		plan       *PositionPlan
+		entryKind  EntryOrderKind
+		wantPairs  int
+		wantRunner bool
+	}{
+		{name: "Single TP, market entry", plan: testPlan(), entryKind: "", wantPairs: 1},
+		{name: "Two TPs plus runner, limit entry", plan: multiTP, entryKind: EntryLimit, wantPairs: 2, wantRunner: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := PlanOrders(tt.plan, tt.entryKind, StopOrderConfig{})
+			if err != nil {
+				t.Fatalf("PlanOrders() error = %v", err)
+			}
+
+			if len(set.Entries) != 1 {
+				t.Fatalf("len(Entries) = %d, want a single entry order", len(set.Entries))
+			}
+			if set.Entries[0].OCOGroup != "" {
+				t.Errorf("Entries[0].OCOGroup = %q, want entry outside any OCO group", set.Entries[0].OCOGroup)
+			}
+			if set.Entries[0].Size != tt.plan.Size {
+				t.Errorf("Entries[0].Size = %v, want %v", set.Entries[0].Size, tt.plan.Size)
+			}
+			if tt.entryKind == EntryLimit && set.Entries[0].Price != tt.plan.EntryPrice {
+				t.Errorf("Entries[0].Price = %v, want limit at %v", set.Entries[0].Price, tt.plan.EntryPrice)
+			}
+
+			wantStops := tt.wantPairs
+			if tt.wantRunner {
+				wantStops++
+			}
+			if len(set.TakeProfits) != tt.wantPairs || len(set.Stops) != wantStops {
+				t.Fatalf("got %d stops / %d TPs, want %d / %d", len(set.Stops), len(set.TakeProfits), wantStops, tt.wantPairs)
+			}
+
+			groups := make(map[string]bool)
+			var stopSize float64
+			for i, stop := range set.Stops {
+				if stop.OCOGroup == "" || groups[stop.OCOGroup] {
+					t.Errorf("Stops[%d].OCOGroup = %q, want a unique non-empty group", i, stop.OCOGroup)
+				}
+				groups[stop.OCOGroup] = true
+				stopSize += stop.Size
+
+				if i < len(set.TakeProfits) {
+					tp := set.TakeProfits[i]
+					if tp.OCOGroup != stop.OCOGroup {
+						t.Errorf("TakeProfits[%d].OCOGroup = %q, want %q shared with its stop", i, tp.OCOGroup, stop.OCOGroup)
+					}
+					if math.Abs(tp.Size-stop.Size) > 1e-12 || tp.Price != tt.plan.TakeProfits[i].Price {
+						t.Errorf("TakeProfits[%d] = %+v, want size %v at %v", i, tp, stop.Size, tt.plan.TakeProfits[i].Price)
+					}
+				}
+			}
+			// The stops together must protect the whole position
+			if math.Abs(stopSize-tt.plan.Size) > 1e-12 {
+				t.Errorf("total stop size = %v, want %v", stopSize, tt.plan.Size)
+			}
+		})
+	}
+}
+
+func TestPlanOrders_Invalid(t *testing.T) {
+	noStop := testPlan()
+	noStop.StopLoss = nil
+
+	if _, err := PlanOrders(noStop, EntryMarket, StopOrderConfig{}); err == nil {
+		t.Error("PlanOrders() error = nil, want error for plan without stop")
+	}
+	if _, err := PlanOrders(testPlan(), "FOK", StopOrderConfig{}); err == nil {
+		t.Error("PlanOrders() error = nil, want error for unknown entry kind")
+	}
+}
+
+func TestRoundToLotSize(t *testing.T) {
+	threeTP := testPlan()
+	threeTP.Size = 0.1237
+	threeTP.TakeProfits = []*TakeProfitLevel{
+		{Price: 45500.0, Percentage: 45, Type: TakeProfitTypeLimit},
+		{Price: 46000.0, Percentage: 35, Type: TakeProfitTypeLimit},
+		{Price: 46500.0, Percentage: 20, Type: TakeProfitTypeLimit},
+	}
+
+	withRunner := testPlan()
+	withRunner.Size = 0.1237
+	withRunner.TakeProfits = []*TakeProfitLevel{
+		{Price: 45500.0, Percentage: 45, Type: TakeProfitTypeLimit},
+		{Price: 46000.0, Percentage: 35, Type: TakeProfitTypeLimit},
+	}
+
+	tests := []struct {
+		name      string
+		plan      *PositionPlan
+		lotSize   float64
+		wantEntry float64
+		wantExits []float64 // Stop sizes, in order
+	}{
+		{
+			name:      "Three uneven TPs, remainder to last TP",
+			plan:      threeTP,
+			lotSize:   0.01,
+			wantEntry: 0.12,
+			wantExits: []float64{0.05, 0.04, 0.03}, // 0.0557, 0.0433 round down; last takes the rest
+		},
+		{
+			name:      "Runner stop takes remainder",
+			plan:      withRunner,
+			lotSize:   0.01,
+			wantEntry: 0.12,
+			wantExits: []float64{0.05, 0.04, 0.03},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := PlanOrders(tt.plan, EntryMarket, StopOrderConfig{})
+			if err != nil {
+				t.Fatalf("PlanOrders() error = %v", err)
+			}
+			if err := RoundToLotSize(set, tt.lotSize); err != nil {
+				t.Fatalf("RoundToLotSize() error = %v", err)
+			}
+
+			if math.Abs(set.Entries[0].Size-tt.wantEntry) > 1e-12 {
+				t.Errorf("Entries[0].Size = %v, want %v", set.Entries[0].Size, tt.wantEntry)
+			}
+			if len(set.Stops) != len(tt.wantExits) {
+				t.Fatalf("len(Stops) = %d, want %d", len(set.Stops), len(tt.wantExits))
+			}
+
+			var exitLots int64
+			for i, stop := range set.Stops {
+				if math.Abs(stop.Size-tt.wantExits[i]) > 1e-12 {
+					t.Errorf("Stops[%d].Size = %v, want %v", i, stop.Size, tt.wantExits[i])
+				}
+				if i < len(set.TakeProfits) && set.TakeProfits[i].Size != stop.Size {
+					t.Errorf("TakeProfits[%d].Size = %v, want %v matching its stop", i, set.TakeProfits[i].Size, stop.Size)
+				}
+				exitLots += int64(math.Round(stop.Size / tt.lotSize))
+			}
+			// Exits must close the rounded position exactly, leaving no dust
+			if entryLots := int64(math.Round(set.Entries[0].Size / tt.lotSize)); exitLots != entryLots {
+				t.Errorf("exits total %d lots, want %d", exitLots, entryLots)
+			}
+		})
+	}
+}
+
+func TestPlanOrders_MaxOrdersEntryLadder(t *testing.T) {
+	// A 10-leg entry ladder with one TP: 10 entries + 1 stop + 1 TP
+	legs := make([]EntryLeg, 10)
+	for i := range legs {
+		legs[i] = EntryLeg{Kind: EntryLimit, Price: 45000.0 - float64(i)*10, Percentage: 10}
+	}
+
+	tests := []struct {
+		name      string
+		maxOrders int
+		wantErr   bool
+	}{
+		{name: "Cap at count", maxOrders: 12, wantErr: false},
+		{name: "Cap one below count", maxOrders: 11, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := PlanOrders(testPlan(), EntryMarket, StopOrderConfig{}, WithEntryLegs(legs...), WithMaxOrders(tt.maxOrders))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PlanOrders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrTooManyOrders) {
+					t.Errorf("PlanOrders() error = %v, want ErrTooManyOrders", err)
+				}
+				return
+			}
+			if n := set.Count(); n != 12 {
+				t.Errorf("Count() = %d, want 12 with every entry leg counted", n)
+			}
+		})
+	}
+}
+
+func TestPlanOrders_EntryLegs(t *testing.T) {
+	plan := testPlan()
+	legs := []EntryLeg{
+		{Kind: EntryMarket, Percentage: 50},
+		{Kind: EntryLimit, Price: 44800.0, Percentage: 25},
+		{Kind: EntryLimit, Price: 44600.0, Percentage: 25},
+	}
+
+	set, err := PlanOrders(plan, EntryMarket, StopOrderConfig{}, WithEntryLegs(legs...))
+	if err != nil {
+		t.Fatalf("PlanOrders() error = %v", err)
+	}
+	if len(set.Entries) != len(legs) {
+		t.Fatalf("len(Entries) = %d, want %d", len(set.Entries), len(legs))
+	}
+
+	wantSizes := []float64{0.02, 0.01, 0.01}
+	wantPrices := []float64{0, 44800.0, 44600.0}
+	seen := make(map[string]bool)
+	for i, entry := range set.Entries {
+		if entry.Kind != legs[i].Kind || entry.Price != wantPrices[i] {
+			t.Errorf("Entries[%d] = %+v, want %s at %v", i, entry, legs[i].Kind, wantPrices[i])
+		}
+		if math.Abs(entry.Size-wantSizes[i]) > 1e-12 {
+			t.Errorf("Entries[%d].Size = %v, want %v", i, entry.Size, wantSizes[i])
+		}
+		if entry.ClientOrderID == "" || seen[entry.ClientOrderID] {
+			t.Errorf("Entries[%d].ClientOrderID = %q, want a unique non-empty ID", i, entry.ClientOrderID)
+		}
+		seen[entry.ClientOrderID] = true
+	}
+
+	// Exits still close the whole position
+	if err := RoundToLotSize(set, 0.001); err != nil {
+		t.Fatalf("RoundToLotSize() error = %v", err)
+	}
+	var entryLots, exitLots int64
+	for _, entry := range set.Entries {
+		entryLots += int64(math.Round(entry.Size / 0.001))
+	}
+	for _, stop := range set.Stops {
+		exitLots += int64(math.Round(stop.Size / 0.001))
+	}
+	if entryLots != 40 || exitLots != entryLots {
+		t.Errorf("entries total %d lots and exits %d, want 40 each", entryLots, exitLots)
+	}
+
+	invalid := map[string][]EntryLeg{
+		"Percentages short of 100": {{Kind: EntryMarket, Percentage: 50}, {Kind: EntryLimit, Price: 44800.0, Percentage: 40}},
+		"Limit without price":      {{Kind: EntryMarket, Percentage: 50}, {Kind: EntryLimit, Percentage: 50}},
+		"Non-positive percentage":  {{Kind: EntryMarket, Percentage: 100}, {Kind: EntryLimit, Price: 44800.0, Percentage: 0}},
+		"Unknown kind":             {{Kind: "FOK", Percentage: 100}},
+	}
+	for name, legs := range invalid {
+		if _, err := PlanOrders(plan, EntryMarket, StopOrderConfig{}, WithEntryLegs(legs...)); err == nil {
+			t.Errorf("%s: PlanOrders() error = nil, want error", name)
+		}
+	}
+}
+
+func TestRoundToLotSize_Invalid(t *testing.T) {
+	tiny := testPlan()
+	tiny.TakeProfits = []*TakeProfitLevel{
+		{Price: 45500.0, Percentage: 1, Type: TakeProfitTypeLimit},
+		{Price: 46000.0, Percentage: 99, Type: TakeProfitTypeLimit},
+	}
+
+	tests := []struct {
+		name    string
+		plan    *PositionPlan
+		lotSize float64
+	}{
+		{name: "Zero lot size", plan: testPlan(), lotSize: 0},
+		{name: "Entry below one lot", plan: testPlan(), lotSize: 0.1},
+		{name: "TP rounds to zero lots", plan: tiny, lotSize: 0.001},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := PlanOrders(tt.plan, EntryMarket, StopOrderConfig{})
+			if err != nil {
+				t.Fatalf("PlanOrders() error = %v", err)
+			}
+			if err := RoundToLotSize(set, tt.lotSize); err == nil {
+				t.Error("RoundToLotSize() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestPlanOrders_InvalidPercentage(t *testing.T) {
+	for _, pct := range []float64{0, -10, 150} {
+		plan := testPlan()
+		plan.TakeProfits[0].Percentage = pct
+
+		if _, err := PlanOrders(plan, EntryMarket, StopOrderConfig{}); err == nil {
+			t.Errorf("PlanOrders() with %v%% TP error = nil, want error", pct)
+		}
+	}
+}
+
+func TestCheckMaxOrders(t *testing.T) {
+	// A 20-rung TP grid: 1 entry + 20 stops + 20 TPs
+	plan := testPlan()
+	plan.TakeProfits = GenerateTPLadder(plan.EntryPrice, plan.StopLoss.Price, plan.Side, 4.0, 20)
+
+	set, err := PlanOrders(plan, EntryLimit, StopOrderConfig{})
+	if err != nil {
+		t.Fatalf("PlanOrders() error = %v", err)
+	}
+	if n := set.Count(); n != 41 {
+		t.Fatalf("Count() = %d, want 41", n)
+	}
+
+	tests := []struct {
+		name      string
+		maxOrders int
+		wantErr   bool
+	}{
+		{name: "Cap above count", maxOrders: 50, wantErr: false},
+		{name: "Cap at count", maxOrders: 41, wantErr: false},
+		{name: "Cap one below count", maxOrders: 40, wantErr: true},
+		{name: "No cap", maxOrders: 0, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckMaxOrders(set, tt.maxOrders)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckMaxOrders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrTooManyOrders) {
+				t.Errorf("CheckMaxOrders() error = %v, want ErrTooManyOrders", err)
+			}
+		})
+
+		t.Run("PlanOrders "+tt.name, func(t *testing.T) {
+			got, err := PlanOrders(plan, EntryLimit, StopOrderConfig{}, WithMaxOrders(tt.maxOrders))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PlanOrders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrTooManyOrders) {
+					t.Errorf("PlanOrders() error = %v, want ErrTooManyOrders", err)
+				}
+				if got != nil {
+					t.Errorf("PlanOrders() set = %+v, want nil when over the cap", got)
+				}
+				return
+			}
+			if n := got.Count(); n != 41 {
+				t.Errorf("Count() = %d, want 41", n)
+			}
+		})
+	}
+
+	// The stop-limit fallback adds a stop per pair, so the cap sees 61 orders
+	if _, err := PlanOrders(plan, EntryLimit, StopOrderConfig{StopLimitFallback: true}, WithMaxOrders(41)); !errors.Is(err, ErrTooManyOrders) {
+		t.Errorf("PlanOrders() with fallback stops error = %v, want ErrTooManyOrders", err)
+	}
+
+	if n := (*OrderSet)(nil).Count(); n != 0 {
+		t.Errorf("nil Count() = %d, want 0", n)
+	}
+}
+
+func TestPlanOrders_StopLimitFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		side      Side
+		entry     float64
+		stop      float64
+		tp        float64
+		wantLimit float64
+	}{
+		{name: "LONG fallback limit below the trigger", side: SideLong, entry: 45000.0, stop: 44500.0, tp: 46000.0, wantLimit: 44500.0 * 0.998},
+		{name: "SHORT fallback limit above the trigger", side: SideShort, entry: 3000.0, stop: 3100.0, tp: 2800.0, wantLimit: 3100.0 * 1.002},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := testPlan()
+			plan.Side = tt.side
+			plan.EntryPrice = tt.entry
+			plan.StopLoss.Price = tt.stop
+			plan.TakeProfits[0].Price = tt.tp
+
+			set, err := PlanOrders(plan, EntryMarket, StopOrderConfig{LimitOffsetPercent: 0.2, StopLimitFallback: true})
+			if err != nil {
+				t.Fatalf("PlanOrders() error = %v", err)
+			}
+			if len(set.Stops) != 1 || len(set.FallbackStops) != 1 {
+				t.Fatalf("got %d stops / %d fallbacks, want 1 / 1", len(set.Stops), len(set.FallbackStops))
+			}
+			if set.Count() != 4 {
+				t.Errorf("Count() = %d, want 4 (entry, stop, fallback, TP)", set.Count())
+			}
+
+			hard, fallback := set.Stops[0], set.FallbackStops[0]
+			if hard.Kind != StopMarket || hard.StopPrice != tt.stop || hard.Price != 0 {
+				t.Errorf("hard stop = %+v, want %s triggering at %v", hard, StopMarket, tt.stop)
+			}
+			if fallback.Kind != StopLimit || fallback.StopPrice != tt.stop || math.Abs(fallback.Price-tt.wantLimit) > 1e-9 {
+				t.Errorf("fallback = %+v, want %s triggering at %v, limit %v", fallback, StopLimit, tt.stop, tt.wantLimit)
+			}
+			if fallback.Size != hard.Size {
+				t.Errorf("fallback size = %v, want %v", fallback.Size, hard.Size)
+			}
+			if fallback.OCOGroup != hard.OCOGroup || set.TakeProfits[0].OCOGroup != hard.OCOGroup {
+				t.Errorf("OCO groups = %q / %q / %q, want all equal", hard.OCOGroup, fallback.OCOGroup, set.TakeProfits[0].OCOGroup)
+			}
+		})
+	}
+}
+
+func TestPlanOrders_StopLimitFallbackInvalid(t *testing.T) {
+	// A stop-limit primary has no stop-market to fall back from
+	if _, err := PlanOrders(testPlan(), EntryMarket, StopOrderConfig{Kind: StopLimit, StopLimitFallback: true}); err == nil {
+		t.Error("PlanOrders() with stop-limit primary error = nil, want error")
+	}
+
+	trailing := testPlan()
+	trailing.StopLoss.Type = StopLossTypeTrailing
+	if _, err := PlanOrders(trailing, EntryMarket, StopOrderConfig{StopLimitFallback: true}); err == nil {
+		t.Error("PlanOrders() with trailing stop error = nil, want error")
+	}
+}
+
+func TestRoundToLotSize_FallbackStops(t *testing.T) {
+	plan := testPlan()
+	plan.Size = 0.0437
+
+	set, err := PlanOrders(plan, EntryMarket, StopOrderConfig{LimitOffsetPercent: 0.2, StopLimitFallback: true})
+	if err != nil {
+		t.Fatalf("PlanOrders() error = %v", err)
+	}
+	if err := RoundToLotSize(set, 0.001); err != nil {
+		t.Fatalf("RoundToLotSize() error = %v", err)
+	}
+	if set.FallbackStops[0].Size != set.Stops[0].Size || math.Abs(set.Stops[0].Size-0.043) > 1e-12 {
+		t.Errorf("stop / fallback sizes = %v / %v, want both 0.043", set.Stops[0].Size, set.FallbackStops[0].Size)
+	}
+}
+
+func TestPlanOrders_ClientOrderIDs(t *testing.T) {
+	newPlan := func() *PositionPlan {
+		plan := testPlan()
+		plan.TakeProfits = []*TakeProfitLevel{
+			{Price: 45500.0, Percentage: 50, Type: TakeProfitTypeLimit},
+			{Price: 46500.0, Percentage: 30, Type: TakeProfitTypeLimit},
+		}
+		return plan
+	}
+	ids := func(plan *PositionPlan) []string {
+		set, err := PlanOrders(plan, EntryLimit, StopOrderConfig{LimitOffsetPercent: 0.2, StopLimitFallback: true})
+		if err != nil {
+			t.Fatalf("PlanOrders() error = %v", err)
+		}
+		out := []string{set.Entries[0].ClientOrderID}
+		for _, o := range set.Stops {
+			out = append(out, o.ClientOrderID)
+		}
+		for _, o := range set.FallbackStops {
+			out = append(out, o.ClientOrderID)
+		}
+		for _, o := range set.TakeProfits {
+			out = append(out, o.ClientOrderID)
+		}
+		return out
+	}
+
+	first := ids(newPlan())
+	seen := make(map[string]bool)
+	for i, id := range first {
+		if id == "" || seen[id] {
+			t.Errorf("order %d ClientOrderID = %q, want a unique non-empty ID", i, id)
+		}
+		seen[id] = true
+	}
+
+	// Resubmitting the same plan must reuse every ID
+	if again := ids(newPlan()); !reflect.DeepEqual(again, first) {
+		t.Errorf("same plan IDs = %v, want %v", again, first)
+	}
+
+	later := newPlan()
+	later.Timestamp = later.Timestamp.Add(time.Second)
+	otherSymbol := newPlan()
+	otherSymbol.Symbol = "ETH-USDT"
+	for name, plan := range map[string]*PositionPlan{"later timestamp": later, "other symbol": otherSymbol} {
+		for _, id := range ids(plan) {
+			if seen[id] {
+				t.Errorf("%s reuses ClientOrderID %q from another plan", name, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package strategy
+
+import "time"
+
+// TradeOutcome records a closed trade for performance metrics such as
+// expectancy and SharpeFromRMultiples
+type TradeOutcome struct {
+	Symbol     string
+	Side       Side
+	EntryPrice float64
+	ExitPrice  float64
+	Size       float64
+	Fees       float64 // Total fees paid, in quote currency
+	Funding    float64 // Net funding paid (negative when received), in quote currency
+	PnL        float64 // Realized PnL before fees
+	RMultiple  float64 // PnL in units of the plan's risk amount
+	OpenTime   time.Time
+	CloseTime  time.Time
+	ExitReason string
+}
+
+// NewTradeOutcome derives the outcome of closing the whole plan at
+// exitPrice. OpenTime is the plan's timestamp and Fees and Funding start at
+// zero; set them afterwards when known. Returns nil for a nil plan.
+func NewTradeOutcome(plan *PositionPlan, exitPrice float64, exitReason string, closeTime time.Time) *TradeOutcome {
+	if plan == nil {
+		return nil
+	}
+
+	pnl := RealizedPnL(plan, exitPrice)
+	var r float64
+	if plan.RiskAmount > 0 {
+		r = pnl / plan.RiskAmount
+	}
+
+	return &TradeOutcome{
+		Symbol:     plan.Symbol,
+		Side:       plan.Side,
+		EntryPrice: plan.EntryPrice,
+		ExitPrice:  exitPrice,
+		Size:       plan.Size,
+		PnL:        pnl,
+		RMultiple:  r,
+		OpenTime:   plan.Timestamp,
+		CloseTime:  closeTime,
+		ExitReason: exitReason,
+	}
+}
+
+// NetPnL returns the realized PnL after fees and funding
+func (o *TradeOutcome) NetPnL() float64 {
+	return o.PnL - o.Fees - o.Funding
+}
+
+// PnLAttribution splits a trade's net PnL into its sources. Each component
+// is its signed contribution, so Price + Fees + Funding == Net.
+type PnLAttribution struct {
+	Price   float64 // PnL from the price move
+	Fees    float64 // Fees, zero or negative
+	Funding float64 // Funding, negative when paid and positive when received
+	Net     float64
+}
+
+// Attribution breaks the outcome's net PnL into price, fees and funding
+func (o *TradeOutcome) Attribution() PnLAttribution {
+	return PnLAttribution{
+		Price:   o.PnL,
+		Fees:    -o.Fees,
+		Funding: -o.Funding,
+		Net:     o.NetPnL(),
+	}
+}
+
+// Duration returns how long the trade was open
+func (o *TradeOutcome) Duration() time.Duration {
+	return o.CloseTime.Sub(o.OpenTime)
+}
+
+// RMultiples extracts each outcome's R-multiple, e.g. for SharpeFromRMultiples
+func RMultiples(outcomes []*TradeOutcome) []float64 {
+	rs := make([]float64, 0, len(outcomes))
+	for _, o := range outcomes {
+		if o != nil {
+			rs = append(rs, o.RMultiple)
+		}
+	}
+	return rs
+}
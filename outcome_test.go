@@ -0,0 +1,123 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewTradeOutcome(t *testing.T) {
+	closeTime := time.Date(2025, 1, 2, 18, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		exit    float64
+		reason  string
+		wantPnL float64
+		wantR   float64
+	}{
+		{name: "TP exit is +RR", exit: 46000.0, reason: "take profit", wantPnL: 40.0, wantR: 2.0},
+		{name: "SL exit is -1R", exit: 44500.0, reason: "stop loss", wantPnL: -20.0, wantR: -1.0},
+		{name: "Breakeven exit", exit: 45000.0, reason: "manual", wantPnL: 0, wantR: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := testPlan()
+			o := NewTradeOutcome(plan, tt.exit, tt.reason, closeTime)
+
+			if math.Abs(o.PnL-tt.wantPnL) > 1e-9 {
+				t.Errorf("PnL = %.4f, want %.4f", o.PnL, tt.wantPnL)
+			}
+			if math.Abs(o.RMultiple-tt.wantR) > 1e-9 {
+				t.Errorf("RMultiple = %.4f, want %.4f", o.RMultiple, tt.wantR)
+			}
+			if o.ExitReason != tt.reason || o.ExitPrice != tt.exit {
+				t.Errorf("exit = %v (%q), want %v (%q)", o.ExitPrice, o.ExitReason, tt.exit, tt.reason)
+			}
+			if o.Symbol != plan.Symbol || o.Side != plan.Side || o.Size != plan.Size || o.EntryPrice != plan.EntryPrice {
+				t.Errorf("outcome %+v doesn't match plan", o)
+			}
+			if !o.OpenTime.Equal(plan.Timestamp) || o.Duration() != 3*time.Hour {
+				t.Errorf("OpenTime = %v, Duration() = %v, want plan timestamp and 3h", o.OpenTime, o.Duration())
+			}
+		})
+	}
+}
+
+func TestTradeOutcome_Fees(t *testing.T) {
+	o := NewTradeOutcome(testPlan(), 46000.0, "take profit", time.Now().UTC())
+	if o.Fees != 0 {
+		t.Errorf("Fees = %v, want 0 before being set", o.Fees)
+	}
+	o.Fees = 1.5
+	if got := o.NetPnL(); math.Abs(got-38.5) > 1e-9 {
+		t.Errorf("NetPnL() = %.4f, want 38.5", got)
+	}
+}
+
+func TestTradeOutcome_Attribution(t *testing.T) {
+	tests := []struct {
+		name    string
+		funding float64
+		want    PnLAttribution
+	}{
+		{
+			// +0.8 on the move, 1.8004 of taker fees and 0.18 funding paid
+			name:    "Fees turn a small win negative",
+			funding: 0.18,
+			want:    PnLAttribution{Price: 0.8, Fees: -1.8004, Funding: -0.18, Net: -1.1804},
+		},
+		{
+			name:    "Funding received offsets fees",
+			funding: -0.5,
+			want:    PnLAttribution{Price: 0.8, Fees: -1.8004, Funding: 0.5, Net: -0.5004},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewTradeOutcome(testPlan(), 45020.0, "manual", time.Now().UTC())
+			o.ApplyFees(0.0005, 0.0005)
+			o.Funding = tt.funding
+
+			got := o.Attribution()
+			for _, c := range []struct {
+				field     string
+				got, want float64
+			}{
+				{"Price", got.Price, tt.want.Price},
+				{"Fees", got.Fees, tt.want.Fees},
+				{"Funding", got.Funding, tt.want.Funding},
+				{"Net", got.Net, tt.want.Net},
+			} {
+				if math.Abs(c.got-c.want) > 1e-9 {
+					t.Errorf("%s = %.6f, want %.6f", c.field, c.got, c.want)
+				}
+			}
+			if sum := got.Price + got.Fees + got.Funding; math.Abs(sum-o.NetPnL()) > 1e-9 {
+				t.Errorf("components sum to %.6f, want NetPnL() %.6f", sum, o.NetPnL())
+			}
+		})
+	}
+}
+
+func TestNewTradeOutcome_NilPlan(t *testing.T) {
+	if o := NewTradeOutcome(nil, 46000.0, "take profit", time.Now().UTC()); o != nil {
+		t.Errorf("NewTradeOutcome(nil) = %+v, want nil", o)
+	}
+}
+
+func TestRMultiples(t *testing.T) {
+	now := time.Now().UTC()
+	outcomes := []*TradeOutcome{
+		NewTradeOutcome(testPlan(), 46000.0, "take profit", now),
+		nil,
+		NewTradeOutcome(testPlan(), 44500.0, "stop loss", now),
+	}
+
+	rs := RMultiples(outcomes)
+	if len(rs) != 2 || math.Abs(rs[0]-2.0) > 1e-9 || math.Abs(rs[1]+1.0) > 1e-9 {
+		t.Errorf("RMultiples() = %v, want [2 -1]", rs)
+	}
+}
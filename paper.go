@@ -0,0 +1,45 @@
+package strategy
+
+import (
+	"time"
+)
+
+// PaperFill records a simulated execution of an order so planned and actual
+// prices can be reconciled
+type PaperFill struct {
+	Order         *OrderRequest
+	Side          Side    // Direction of the fill: LONG = buy, SHORT = sell
+	IntendedPrice float64 // Price the plan asked for
+	FillPrice     float64 // Simulated execution price
+	Timestamp     time.Time
+}
+
+// SimulateFill returns a PaperFill for order filled slippageBps worse than
+// intendedPrice: higher for a buy (LONG), lower for a sell (SHORT)
+func SimulateFill(order *OrderRequest, side Side, intendedPrice, slippageBps float64, ts time.Time) *PaperFill {
+	factor := 1 + slippageBps/10000
+	if side == SideShort {
+		factor = 1 - slippageBps/10000
+	}
+
+	return &PaperFill{
+		Order:         order,
+		Side:          side,
+		IntendedPrice: intendedPrice,
+		FillPrice:     intendedPrice * factor,
+		Timestamp:     ts,
+	}
+}
+
+// SlippageBps returns realized slippage in basis points of the intended
+// price. Positive is worse than intended, negative is price improvement.
+func (f *PaperFill) SlippageBps() float64 {
+	if f.IntendedPrice == 0 {
+		return 0
+	}
+	bps := (f.FillPrice - f.IntendedPrice) / f.IntendedPrice * 10000
+	if f.Side == SideShort {
+		return -bps
+	}
+	return bps
+}
@@ -0,0 +1,64 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSimulateFill(t *testing.T) {
+	order := &OrderRequest{}
+	ts := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		side          Side
+		intendedPrice float64
+		slippageBps   float64
+		wantFill      float64
+	}{
+		{name: "LONG entry fills 5 bps higher", side: SideLong, intendedPrice: 45000.0, slippageBps: 5, wantFill: 45022.5},
+		{name: "SHORT entry fills 5 bps lower", side: SideShort, intendedPrice: 3000.0, slippageBps: 5, wantFill: 2998.5},
+		{name: "No slippage", side: SideLong, intendedPrice: 45000.0, slippageBps: 0, wantFill: 45000.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fill := SimulateFill(order, tt.side, tt.intendedPrice, tt.slippageBps, ts)
+
+			if fill.Order != order {
+				t.Error("Order not retained")
+			}
+			if !fill.Timestamp.Equal(ts) {
+				t.Errorf("Timestamp = %v, want %v", fill.Timestamp, ts)
+			}
+			if math.Abs(fill.FillPrice-tt.wantFill) > 1e-9 {
+				t.Errorf("FillPrice = %.4f, want %.4f", fill.FillPrice, tt.wantFill)
+			}
+			if math.Abs(fill.SlippageBps()-tt.slippageBps) > 1e-9 {
+				t.Errorf("SlippageBps() = %.4f, want %.4f", fill.SlippageBps(), tt.slippageBps)
+			}
+		})
+	}
+}
+
+func TestPaperFillSlippageBps(t *testing.T) {
+	tests := []struct {
+		name string
+		fill PaperFill
+		want float64
+	}{
+		{name: "LONG filled 5 bps worse", fill: PaperFill{Side: SideLong, IntendedPrice: 45000.0, FillPrice: 45022.5}, want: 5},
+		{name: "LONG price improvement", fill: PaperFill{Side: SideLong, IntendedPrice: 45000.0, FillPrice: 44977.5}, want: -5},
+		{name: "SHORT filled 10 bps worse", fill: PaperFill{Side: SideShort, IntendedPrice: 3000.0, FillPrice: 2997.0}, want: 10},
+		{name: "Zero intended price", fill: PaperFill{Side: SideLong, FillPrice: 1.0}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fill.SlippageBps(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("SlippageBps() = %.4f, want %.4f", got, tt.want)
+			}
+		})
+	}
+}
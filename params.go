@@ -0,0 +1,249 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ParamType is the value type of a strategy parameter
+type ParamType string
+
+const (
+	ParamTypeFloat  ParamType = "float"
+	ParamTypeInt    ParamType = "int"
+	ParamTypeString ParamType = "string"
+	ParamTypeBool   ParamType = "bool"
+)
+
+// ParamSpec describes one strategy parameter for config UIs and validation
+type ParamSpec struct {
+	Name        string
+	Type        ParamType
+	Description string
+	Default     interface{} // nil = no default
+	Required    bool
+
+	// Numeric constraints (nil = unbounded)
+	Min          *float64
+	Max          *float64
+	ExclusiveMin bool // Min itself is not allowed
+	ExclusiveMax bool // Max itself is not allowed
+}
+
+// Bound returns a pointer to v, for ParamSpec.Min and ParamSpec.Max
+func Bound(v float64) *float64 {
+	return &v
+}
+
+// ValidateAgainstSpec checks params against specs, e.g. a strategy's
+// Parameters(), before anything runs: required keys are present, values
+// have the spec's type, and numbers respect Min/Max. All violations are
+// returned joined, not just the first. Keys without a spec are ignored,
+// since wrappers and callers may pass params of their own.
+func ValidateAgainstSpec(params StrategyParams, specs []ParamSpec) error {
+	var errs []error
+	for _, spec := range specs {
+		raw, ok := params[spec.Name]
+		if !ok {
+			if spec.Required {
+				errs = append(errs, fmt.Errorf("param %q is required", spec.Name))
+			}
+			continue
+		}
+
+		var err error
+		switch spec.Type {
+		case ParamTypeFloat:
+			var v float64
+			if v, _, err = params.Float(spec.Name); err == nil {
+				err = checkBounds(spec, v)
+			}
+		case ParamTypeInt:
+			var v int
+			if v, _, err = params.Int(spec.Name); err == nil {
+				err = checkBounds(spec, float64(v))
+			}
+		case ParamTypeString:
+			_, _, err = params.String(spec.Name)
+		case ParamTypeBool:
+			if _, isBool := raw.(bool); !isBool {
+				err = fmt.Errorf("param %q must be a bool, got %T", spec.Name, raw)
+			}
+		default:
+			err = fmt.Errorf("param %q has unknown spec type %q", spec.Name, spec.Type)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkBounds checks value against spec's Min and Max
+func checkBounds(spec ParamSpec, value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("param %q must be finite, got %v", spec.Name, value)
+	}
+	if spec.Min != nil {
+		if spec.ExclusiveMin && value <= *spec.Min {
+			return fmt.Errorf("param %q must be > %v, got %v", spec.Name, *spec.Min, value)
+		}
+		if value < *spec.Min {
+			return fmt.Errorf("param %q must be >= %v, got %v", spec.Name, *spec.Min, value)
+		}
+	}
+	if spec.Max != nil {
+		if spec.ExclusiveMax && value >= *spec.Max {
+			return fmt.Errorf("param %q must be < %v, got %v", spec.Name, *spec.Max, value)
+		}
+		if value > *spec.Max {
+			return fmt.Errorf("param %q must be <= %v, got %v", spec.Name, *spec.Max, value)
+		}
+	}
+	return nil
+}
+
+// ParameterDescriber is implemented by strategies that describe the
+// StrategyParams keys they read, for config UIs and ValidateAgainstSpec.
+// It is optional, so existing Strategy implementations keep compiling.
+type ParameterDescriber interface {
+	Parameters() []ParamSpec
+}
+
+// Parameters returns strat's param specs, or nil when it doesn't implement
+// ParameterDescriber
+func Parameters(strat Strategy) []ParamSpec {
+	if d, ok := strat.(ParameterDescriber); ok {
+		return d.Parameters()
+	}
+	return nil
+}
+
+// NoParameters can be embedded by strategies that expose no parameters
+type NoParameters struct{}
+
+// Parameters returns no parameter specs
+func (NoParameters) Parameters() []ParamSpec {
+	return nil
+}
+
+// Int returns the integer stored under key; ok is false when key is absent.
+// Whole-number floats (as produced by encoding/json) are accepted.
+func (p StrategyParams) Int(key string) (value int, ok bool, err error) {
+	raw, ok := p[key]
+	if !ok {
+		return 0, false, nil
+	}
+	value, err = toInt(key, raw)
+	return value, true, err
+}
+
+// Ints returns the integer list stored under key; ok is false when key is
+// absent. Both []int and JSON-decoded []interface{} lists are accepted.
+func (p StrategyParams) Ints(key string) (values []int, ok bool, err error) {
+	raw, ok := p[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	switch v := raw.(type) {
+	case []int:
+		return v, true, nil
+	case []interface{}:
+		values = make([]int, len(v))
+		for i, elem := range v {
+			if values[i], err = toInt(key, elem); err != nil {
+				return nil, true, err
+			}
+		}
+		return values, true, nil
+	default:
+		return nil, true, fmt.Errorf("param %q must be a list of integers, got %T", key, raw)
+	}
+}
+
+// String returns the string stored under key; ok is false when key is absent
+func (p StrategyParams) String(key string) (value string, ok bool, err error) {
+	raw, ok := p[key]
+	if !ok {
+		return "", false, nil
+	}
+	value, isString := raw.(string)
+	if !isString {
+		return "", true, fmt.Errorf("param %q must be a string, got %T", key, raw)
+	}
+	return value, true, nil
+}
+
+// toInt converts a single param value to int
+func toInt(key string, raw interface{}) (int, error) {
+	switch v := raw.(type) {
+	case int:
+		return v, nil
+	case int32:
+		return int(v), nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("param %q must be an integer, got %v", key, v)
+		}
+		return int(v), nil
+	default:
+		return 0, fmt.Errorf("param %q must be an integer, got %T", key, raw)
+	}
+}
+
+// Float returns the number stored under key; ok is false when key is absent.
+// Any integer or float type is accepted.
+func (p StrategyParams) Float(key string) (value float64, ok bool, err error) {
+	raw, ok := p[key]
+	if !ok {
+		return 0, false, nil
+	}
+	value, err = toFloat(key, raw)
+	return value, true, err
+}
+
+// Floats returns the number list stored under key; ok is false when key is
+// absent. Both []float64 and JSON-decoded []interface{} lists are accepted.
+func (p StrategyParams) Floats(key string) (values []float64, ok bool, err error) {
+	raw, ok := p[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	switch v := raw.(type) {
+	case []float64:
+		return v, true, nil
+	case []interface{}:
+		values = make([]float64, len(v))
+		for i, elem := range v {
+			if values[i], err = toFloat(key, elem); err != nil {
+				return nil, true, err
+			}
+		}
+		return values, true, nil
+	default:
+		return nil, true, fmt.Errorf("param %q must be a list of numbers, got %T", key, raw)
+	}
+}
+
+// toFloat converts a single param value to float64
+func toFloat(key string, raw interface{}) (float64, error) {
+	switch v := raw.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("param %q must be a number, got %T", key, raw)
+	}
+}
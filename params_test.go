@@ -0,0 +1,253 @@
+package strategy
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStrategyParamsInt(t *testing.T) {
+	params := StrategyParams{
+		"int":      3,
+		"int64":    int64(4),
+		"json":     5.0,
+		"fraction": 5.5,
+		"string":   "6",
+	}
+
+	tests := []struct {
+		key     string
+		want    int
+		wantOK  bool
+		wantErr bool
+	}{
+		{key: "int", want: 3, wantOK: true},
+		{key: "int64", want: 4, wantOK: true},
+		{key: "json", want: 5, wantOK: true},
+		{key: "fraction", wantOK: true, wantErr: true},
+		{key: "string", wantOK: true, wantErr: true},
+		{key: "missing", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, ok, err := params.Int(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Int(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("Int(%q) ok = %v, want %v", tt.key, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("Int(%q) = %d, want %d", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrategyParamsString(t *testing.T) {
+	params := StrategyParams{"policy": "shrink", "number": 5}
+
+	if got, ok, err := params.String("policy"); got != "shrink" || !ok || err != nil {
+		t.Errorf("String(policy) = %q, %v, %v, want shrink, true, nil", got, ok, err)
+	}
+	if _, ok, err := params.String("number"); !ok || err == nil {
+		t.Errorf("String(number) ok = %v, err = %v, want true and an error", ok, err)
+	}
+	if got, ok, err := params.String("missing"); got != "" || ok || err != nil {
+		t.Errorf("String(missing) = %q, %v, %v, want empty, false, nil", got, ok, err)
+	}
+}
+
+func TestStrategyParamsInts(t *testing.T) {
+	params := StrategyParams{
+		"ints":   []int{1, 2, 5},
+		"json":   []interface{}{1.0, 2.0, 5.0},
+		"mixed":  []interface{}{1, 2.5},
+		"scalar": 5,
+	}
+
+	tests := []struct {
+		key     string
+		want    []int
+		wantOK  bool
+		wantErr bool
+	}{
+		{key: "ints", want: []int{1, 2, 5}, wantOK: true},
+		{key: "json", want: []int{1, 2, 5}, wantOK: true},
+		{key: "mixed", wantOK: true, wantErr: true},
+		{key: "scalar", wantOK: true, wantErr: true},
+		{key: "missing", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, ok, err := params.Ints(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Ints(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("Ints(%q) ok = %v, want %v", tt.key, ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Ints(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrategyParamsFloats(t *testing.T) {
+	params := StrategyParams{
+		"floats": []float64{44200, 44500.5},
+		"json":   []interface{}{44200.0, 44500.5, 3},
+		"mixed":  []interface{}{44200.0, "low"},
+		"scalar": 44200.0,
+	}
+
+	tests := []struct {
+		key     string
+		want    []float64
+		wantOK  bool
+		wantErr bool
+	}{
+		{key: "floats", want: []float64{44200, 44500.5}, wantOK: true},
+		{key: "json", want: []float64{44200, 44500.5, 3}, wantOK: true},
+		{key: "mixed", wantOK: true, wantErr: true},
+		{key: "scalar", wantOK: true, wantErr: true},
+		{key: "missing", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, ok, err := params.Floats(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Floats(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("Floats(%q) ok = %v, want %v", tt.key, ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Floats(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrategyParamsFloat(t *testing.T) {
+	params := StrategyParams{
+		"float": 1.5,
+		"int":   2,
+		"bool":  true,
+	}
+
+	tests := []struct {
+		key     string
+		want    float64
+		wantOK  bool
+		wantErr bool
+	}{
+		{key: "float", want: 1.5, wantOK: true},
+		{key: "int", want: 2.0, wantOK: true},
+		{key: "bool", wantOK: true, wantErr: true},
+		{key: "missing", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, ok, err := params.Float(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Float(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("Float(%q) ok = %v, want %v", tt.key, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("Float(%q) = %.2f, want %.2f", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAgainstSpec(t *testing.T) {
+	specs := []ParamSpec{
+		{Name: "rrRatio", Type: ParamTypeFloat, Required: true, Min: Bound(0), ExclusiveMin: true},
+		{Name: "trailPercent", Type: ParamTypeFloat, Min: Bound(0), Max: Bound(1), ExclusiveMin: true, ExclusiveMax: true},
+		{Name: "maxPositions", Type: ParamTypeInt, Min: Bound(1), Max: Bound(10)},
+		{Name: "clampPolicy", Type: ParamTypeString},
+		{Name: "reduceOnly", Type: ParamTypeBool},
+	}
+
+	tests := []struct {
+		name     string
+		params   StrategyParams
+		wantErrs []string
+	}{
+		{
+			name:   "All valid",
+			params: StrategyParams{"rrRatio": 2.0, "trailPercent": 0.01, "maxPositions": 3, "clampPolicy": "shrink", "reduceOnly": true},
+		},
+		{name: "Optional params omitted", params: StrategyParams{"rrRatio": 2}},
+		{name: "Unknown keys ignored", params: StrategyParams{"rrRatio": 2.0, "atr": "n/a"}},
+		{name: "Missing required", params: StrategyParams{}, wantErrs: []string{`"rrRatio" is required`}},
+		{
+			name:     "Wrong types",
+			params:   StrategyParams{"rrRatio": "2", "maxPositions": 2.5, "clampPolicy": 1, "reduceOnly": "yes"},
+			wantErrs: []string{`"rrRatio" must be a number`, `"maxPositions" must be an integer`, `"clampPolicy" must be a string`, `"reduceOnly" must be a bool`},
+		},
+		{
+			name:     "Out of range",
+			params:   StrategyParams{"rrRatio": 0.0, "trailPercent": 1.0, "maxPositions": 11},
+			wantErrs: []string{`"rrRatio" must be > 0`, `"trailPercent" must be < 1`, `"maxPositions" must be <= 10`},
+		},
+		{name: "Inclusive minimum", params: StrategyParams{"rrRatio": 2.0, "maxPositions": 0}, wantErrs: []string{`"maxPositions" must be >= 1`}},
+		{name: "NaN", params: StrategyParams{"rrRatio": math.NaN()}, wantErrs: []string{`"rrRatio" must be finite`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgainstSpec(tt.params, specs)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Errorf("ValidateAgainstSpec() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("ValidateAgainstSpec() error = nil, want %v", tt.wantErrs)
+			}
+			// Every violation is reported, one per line
+			if lines := strings.Split(err.Error(), "\n"); len(lines) != len(tt.wantErrs) {
+				t.Errorf("ValidateAgainstSpec() reported %d violations, want %d: %v", len(lines), len(tt.wantErrs), err)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("ValidateAgainstSpec() error = %v, want it to mention %s", err, want)
+				}
+			}
+		})
+	}
+}
+
+// describedStub is a stubStrategy that describes one params key
+type describedStub struct {
+	stubStrategy
+}
+
+func (describedStub) Parameters() []ParamSpec {
+	return []ParamSpec{{Name: "atr", Type: ParamTypeFloat}}
+}
+
+func TestParameters(t *testing.T) {
+	if specs := Parameters(&describedStub{}); len(specs) != 1 || specs[0].Name != "atr" {
+		t.Errorf("Parameters(describer) = %+v, want the atr spec", specs)
+	}
+	// Embedding the Strategy interface hides stubStrategy's NoParameters
+	plain := struct{ Strategy }{&stubStrategy{}}
+	if _, ok := Strategy(plain).(ParameterDescriber); ok {
+		t.Fatal("plain strategy should not implement ParameterDescriber")
+	}
+	if specs := Parameters(plain); specs != nil {
+		t.Errorf("Parameters(non-describer) = %+v, want nil", specs)
+	}
+}
@@ -0,0 +1,293 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PositionPlan is defined in trading-common-types, so plan helpers are plain
+// functions taking the plan rather than methods on it.
+
+// PlanSummary returns a compact multi-line, human-readable summary of a plan
+func PlanSummary(plan *PositionPlan) string {
+	if plan == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s @ %s (%dx)\n", plan.Symbol, plan.Side, formatQuantity(plan.Size), formatQuantity(plan.EntryPrice), plan.Leverage)
+
+	if plan.StopLoss != nil {
+		fmt.Fprintf(&b, "  Stop:     %s (%s)\n", formatQuantity(plan.StopLoss.Price), plan.StopLoss.Type)
+	} else {
+		b.WriteString("  Stop:     none\n")
+	}
+
+	n := 0
+	for _, tp := range plan.TakeProfits {
+		if tp == nil {
+			continue
+		}
+		n++
+		fmt.Fprintf(&b, "  TP %-7s%s (%.0f%%)\n", fmt.Sprintf("%d:", n), formatQuantity(tp.Price), tp.Percentage)
+	}
+
+	fmt.Fprintf(&b, "  Risk:     $%.2f (%.2f%%)\n", plan.RiskAmount, plan.RiskPercent)
+	fmt.Fprintf(&b, "  Notional: $%.2f\n", plan.NotionalValue)
+
+	if rr, ok := ImpliedRR(plan); ok {
+		fmt.Fprintf(&b, "  R:R:      %s:1\n", FormatRatio(rr))
+	} else {
+		b.WriteString("  R:R:      n/a\n")
+	}
+
+	return b.String()
+}
+
+// quantityDecimals is the most decimals PlanSummary shows for prices and
+// sizes, enough for sub-cent prices like 0.000123 while hiding float noise
+const quantityDecimals = 10
+
+// formatQuantity formats a price or size with trailing zeros trimmed: 45000
+// -> "45000", 0.000123 -> "0.000123"
+func formatQuantity(v float64) string {
+	s := strconv.FormatFloat(v, 'f', quantityDecimals, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// ratioDecimals is the most decimals FormatRatio shows, enough for ratios
+// like 2.125 while hiding float noise in ratios implied from prices
+const ratioDecimals = 4
+
+// FormatRatio formats a risk-reward ratio for display without losing
+// precision: 2 -> "2.0", 1.5 -> "1.5", 2.25 -> "2.25". At least one decimal
+// is always shown and at most ratioDecimals.
+func FormatRatio(rr float64) string {
+	s := strconv.FormatFloat(rr, 'f', ratioDecimals, 64)
+	s = strings.TrimRight(s, "0")
+	if strings.HasSuffix(s, ".") {
+		s += "0"
+	}
+	return s
+}
+
+// ImpliedRR returns the reward-to-risk of the first TP against the stop, so a
+// strategy's plan can be checked against the RR it claims. The reward is
+// signed like WeightedRR's, so a TP on the losing side of entry gives a
+// negative RR. ok is false when the plan has no stop, no TP, or a stop at
+// entry.
+func ImpliedRR(plan *PositionPlan) (rr float64, ok bool) {
+	if plan == nil || plan.StopLoss == nil || len(plan.TakeProfits) == 0 || plan.TakeProfits[0] == nil {
+		return 0, false
+	}
+	risk := math.Abs(plan.EntryPrice - plan.StopLoss.Price)
+	if risk == 0 {
+		return 0, false
+	}
+	reward := plan.TakeProfits[0].Price - plan.EntryPrice
+	if plan.Side == SideShort {
+		reward = -reward
+	}
+	return reward / risk, true
+}
+
+// WeightedRR returns the headline reward-to-risk of a multi-TP plan: each
+// level's RR weighted by its percentage, averaged over the TP percentages.
+// A runner not covered by any TP has no fixed reward and is left out. Returns
+// 0 when the plan has no stop, no TP, or a stop at entry.
+func WeightedRR(plan *PositionPlan) float64 {
+	if plan == nil || plan.StopLoss == nil {
+		return 0
+	}
+	risk := math.Abs(plan.EntryPrice - plan.StopLoss.Price)
+	if risk == 0 {
+		return 0
+	}
+
+	var weighted, total float64
+	for _, tp := range plan.TakeProfits {
+		if tp == nil {
+			continue
+		}
+		reward := tp.Price - plan.EntryPrice
+		if plan.Side == SideShort {
+			reward = -reward
+		}
+		weighted += tp.Percentage * reward / risk
+		total += tp.Percentage
+	}
+	if total == 0 {
+		return 0
+	}
+	return weighted / total
+}
+
+// ActualRisk returns the dollar loss if the plan's stop is hit after
+// filling at actualEntry instead of plan.EntryPrice: the planned size times
+// the distance from the real fill to the planned stop. Compare it with
+// plan.RiskAmount to log slippage-driven risk drift. Returns 0 for a plan
+// without a stop, or a fill at or past the stop.
+func ActualRisk(plan *PositionPlan, actualEntry float64) float64 {
+	if plan == nil || plan.StopLoss == nil {
+		return 0
+	}
+	distance := actualEntry - plan.StopLoss.Price
+	if plan.Side == SideShort {
+		distance = -distance
+	}
+	if distance <= 0 {
+		return 0
+	}
+	return math.Abs(plan.Size) * distance
+}
+
+// OneR returns the dollar value of 1R for plan: its RiskAmount, the loss if
+// the stop is hit. Returns 0 for a nil plan.
+func OneR(plan *PositionPlan) float64 {
+	if plan == nil {
+		return 0
+	}
+	return plan.RiskAmount
+}
+
+// RToDollars converts r R-multiples of plan into dollars, e.g. the value of
+// a 2R target or a -1R stop-out
+func RToDollars(plan *PositionPlan, r float64) float64 {
+	return r * OneR(plan)
+}
+
+// PriceAtR returns the price at which the plan's open profit is r times its
+// risk: entry +/- r * |entry - stop| for LONG/SHORT. Negative r gives prices
+// on the losing side, so -1 is the stop itself. Returns 0 for a plan
+// without a stop.
+func PriceAtR(plan *PositionPlan, r float64) float64 {
+	if plan == nil || plan.StopLoss == nil {
+		return 0
+	}
+	move := r * math.Abs(plan.EntryPrice-plan.StopLoss.Price)
+	if plan.Side == SideShort {
+		return plan.EntryPrice - move
+	}
+	return plan.EntryPrice + move
+}
+
+// ClonePlan returns a deep copy of plan, including its stop loss and every
+// take-profit level, so the copy can be mutated (e.g. rounded) safely
+func ClonePlan(plan *PositionPlan) *PositionPlan {
+	if plan == nil {
+		return nil
+	}
+
+	clone := *plan
+	if plan.StopLoss != nil {
+		stop := *plan.StopLoss
+		clone.StopLoss = &stop
+	}
+	if plan.TakeProfits != nil {
+		clone.TakeProfits = make([]*TakeProfitLevel, len(plan.TakeProfits))
+		for i, tp := range plan.TakeProfits {
+			if tp != nil {
+				level := *tp
+				clone.TakeProfits[i] = &level
+			}
+		}
+	}
+	return &clone
+}
+
+// FillOption configures AdjustForFill
+type FillOption func(*fillOptions)
+
+// fillOptions holds the settings applied by FillOption
+type fillOptions struct {
+	maxLeverage         int
+	marginBufferPercent float64
+}
+
+// WithFillMaxLeverage caps the recomputed leverage, as the strategy's max
+// leverage capped the original plan. Defaults to DefaultMaxLeverage.
+func WithFillMaxLeverage(maxLeverage int) FillOption {
+	return func(o *fillOptions) {
+		o.maxLeverage = maxLeverage
+	}
+}
+
+// WithFillMarginBuffer keeps percent of the balance out of the margin, as
+// the plan's margin buffer did. Must be in [0, 100).
+func WithFillMarginBuffer(percent float64) FillOption {
+	return func(o *fillOptions) {
+		o.marginBufferPercent = percent
+	}
+}
+
+// AdjustForFill returns a copy of plan rebuilt around what actually filled,
+// e.g. 60% of a laddered entry at its own average price. Size, EntryPrice
+// and NotionalValue come from the fill; RiskAmount and RiskPercent are the
+// stop-out loss of the filled size at the planned stop. Each take profit
+// keeps its signed RR against the new entry, so a fill closer to the stop
+// pulls the targets in with it.
+//
+// Leverage is recomputed as ceil(notional/margin) and clamped to the max
+// leverage, where margin is the balance implied by the plan's RiskAmount and
+// RiskPercent less the margin buffer; it is left unchanged when the plan
+// doesn't carry them. Returns nil for a plan without a stop, non-positive
+// fill inputs, a fill at or past the stop, a take profit at or behind the
+// planned entry, or a fill whose margin doesn't fit at the max leverage.
+func AdjustForFill(plan *PositionPlan, filledSize, avgFillPrice float64, opts ...FillOption) *PositionPlan {
+	o := fillOptions{maxLeverage: DefaultMaxLeverage}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if plan == nil || plan.StopLoss == nil || filledSize <= 0 || avgFillPrice <= 0 {
+		return nil
+	}
+	if o.maxLeverage <= 0 || o.marginBufferPercent < 0 || o.marginBufferPercent >= 100 {
+		return nil
+	}
+	stop := plan.StopLoss.Price
+	if ValidateStopLoss(plan.Side, avgFillPrice, stop, DefaultPriceEpsilon) != nil {
+		return nil
+	}
+
+	plannedDistance := math.Abs(plan.EntryPrice - stop)
+	distance := math.Abs(avgFillPrice - stop)
+	balance := 0.0
+	if plan.RiskPercent > 0 {
+		balance = plan.RiskAmount * 100 / plan.RiskPercent
+	}
+
+	adjusted := ClonePlan(plan)
+	adjusted.Size = filledSize
+	adjusted.EntryPrice = avgFillPrice
+	adjusted.NotionalValue = filledSize * avgFillPrice
+	adjusted.RiskAmount = filledSize * distance
+	if balance > 0 {
+		margin := balance * (1 - o.marginBufferPercent/100)
+		adjusted.RiskPercent = RiskPercent(adjusted.RiskAmount, balance)
+		adjusted.Leverage = min(o.maxLeverage, max(1, int(math.Ceil(adjusted.NotionalValue/margin))))
+		if ValidateMargin(adjusted.NotionalValue, adjusted.Leverage, margin) != nil {
+			return nil
+		}
+	}
+
+	direction := 1.0
+	if plan.Side == SideShort {
+		direction = -1
+	}
+	for _, tp := range adjusted.TakeProfits {
+		if tp == nil || plannedDistance == 0 {
+			continue
+		}
+		rr := (tp.Price - plan.EntryPrice) * direction / plannedDistance
+		if rr <= 0 {
+			return nil
+		}
+		tp.Price = avgFillPrice + direction*rr*distance
+	}
+	return adjusted
+}
@@ -0,0 +1,495 @@
+package strategy
+
+import (
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/calculator-go"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// testPlan returns the canonical 2% / 2:1 BTC LONG plan used across tests
+func testPlan() *PositionPlan {
+	return &PositionPlan{
+		Symbol:     "BTC-USDT",
+		Side:       SideLong,
+		Size:       0.04,
+		EntryPrice: 45000.0,
+		Leverage:   2,
+		StopLoss: &StopLossLevel{
+			Price: 44500.0,
+			Type:  StopLossTypeFixed,
+		},
+		TakeProfits: []*TakeProfitLevel{
+			{
+				Price:      46000.0,
+				Percentage: 100,
+				Type:       TakeProfitTypeLimit,
+			},
+		},
+		RiskAmount:    20.0,
+		RiskPercent:   2.0,
+		NotionalValue: 1800.0,
+		StrategyName:  "risk-ratio",
+		Timestamp:     time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestPlanSummary(t *testing.T) {
+	multiTP := testPlan()
+	multiTP.TakeProfits = []*TakeProfitLevel{
+		{Price: 45500.0, Percentage: 50, Type: TakeProfitTypeLimit},
+		{Price: 46500.0, Percentage: 50, Type: TakeProfitTypeLimit},
+	}
+
+	// Nil levels are skipped, so this renders like multiTP
+	nilTP := testPlan()
+	nilTP.TakeProfits = []*TakeProfitLevel{
+		{Price: 45500.0, Percentage: 50, Type: TakeProfitTypeLimit},
+		nil,
+		{Price: 46500.0, Percentage: 50, Type: TakeProfitTypeLimit},
+		nil,
+	}
+
+	noStop := testPlan()
+	noStop.StopLoss = nil
+
+	// Sub-cent prices keep their significant digits
+	subCent := testPlan()
+	subCent.Symbol = "PEPE-USDT"
+	subCent.Size = 10000000
+	subCent.EntryPrice = 0.000123
+	subCent.StopLoss.Price = 0.000118
+	subCent.TakeProfits[0].Price = 0.000133
+	subCent.RiskAmount = 50.0
+	subCent.NotionalValue = 1230.0
+
+	tests := []struct {
+		name   string
+		plan   *PositionPlan
+		golden string
+	}{
+		{name: "Standard 2:1 LONG", plan: testPlan(), golden: "plan_summary.golden"},
+		{name: "Two TP levels", plan: multiTP, golden: "plan_summary_multi_tp.golden"},
+		{name: "No stop loss", plan: noStop, golden: "plan_summary_no_stop.golden"},
+		{name: "Nil TP levels skipped", plan: nilTP, golden: "plan_summary_multi_tp.golden"},
+		{name: "Sub-cent prices", plan: subCent, golden: "plan_summary_sub_cent.golden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PlanSummary(tt.plan)
+			path := filepath.Join("testdata", tt.golden)
+
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("PlanSummary() mismatch\ngot:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestPlanSummary_Nil(t *testing.T) {
+	if got := PlanSummary(nil); got != "" {
+		t.Errorf("PlanSummary(nil) = %q, want empty", got)
+	}
+}
+
+func TestFormatRatio(t *testing.T) {
+	tests := []struct {
+		rr   float64
+		want string
+	}{
+		{rr: 2.0, want: "2.0"},
+		{rr: 1.5, want: "1.5"},
+		{rr: 2.25, want: "2.25"},
+		{rr: 2.125, want: "2.125"},
+		{rr: 1.9999999999, want: "2.0"}, // noise from prices is hidden
+	}
+
+	for _, tt := range tests {
+		if got := FormatRatio(tt.rr); got != tt.want {
+			t.Errorf("FormatRatio(%v) = %q, want %q", tt.rr, got, tt.want)
+		}
+	}
+}
+
+func TestFormatQuantity(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want string
+	}{
+		{v: 45000.0, want: "45000"},
+		{v: 45000.5, want: "45000.5"},
+		{v: 0.000123, want: "0.000123"},
+		{v: 0.1 + 0.2, want: "0.3"}, // float noise is hidden
+		{v: 0, want: "0"},
+	}
+
+	for _, tt := range tests {
+		if got := formatQuantity(tt.v); got != tt.want {
+			t.Errorf("formatQuantity(%v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestImpliedRR(t *testing.T) {
+	noStop := testPlan()
+	noStop.StopLoss = nil
+
+	noTP := testPlan()
+	noTP.TakeProfits = nil
+
+	short := testPlan()
+	short.Side = SideShort
+	short.StopLoss.Price = 45500.0
+	short.TakeProfits[0].Price = 43500.0
+
+	wrongSideLong := testPlan()
+	wrongSideLong.TakeProfits[0].Price = 44000.0
+
+	wrongSideShort := testPlan()
+	wrongSideShort.Side = SideShort
+	wrongSideShort.StopLoss.Price = 45500.0
+	wrongSideShort.TakeProfits[0].Price = 45250.0
+
+	tests := []struct {
+		name   string
+		plan   *PositionPlan
+		wantRR float64
+		wantOK bool
+	}{
+		{name: "2:1 LONG", plan: testPlan(), wantRR: 2.0, wantOK: true},
+		{name: "3:1 SHORT", plan: short, wantRR: 3.0, wantOK: true},
+		{name: "LONG TP below entry", plan: wrongSideLong, wantRR: -2.0, wantOK: true},
+		{name: "SHORT TP between entry and stop", plan: wrongSideShort, wantRR: -0.5, wantOK: true},
+		{name: "No stop", plan: noStop, wantRR: 0, wantOK: false},
+		{name: "No take profit", plan: noTP, wantRR: 0, wantOK: false},
+		{name: "Nil plan", plan: nil, wantRR: 0, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, ok := ImpliedRR(tt.plan)
+			if ok != tt.wantOK {
+				t.Fatalf("ImpliedRR() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if math.Abs(rr-tt.wantRR) > 1e-9 {
+				t.Errorf("ImpliedRR() = %v, want %v", rr, tt.wantRR)
+			}
+		})
+	}
+}
+
+func TestWeightedRR(t *testing.T) {
+	// LONG 45000, stop 44500: 1R = 500
+	ladder := testPlan()
+	ladder.TakeProfits = []*TakeProfitLevel{
+		{Price: 45500.0, Percentage: 50, Type: TakeProfitTypeLimit}, // 1R
+		{Price: 46500.0, Percentage: 50, Type: TakeProfitTypeLimit}, // 3R
+	}
+
+	frontLoaded := testPlan()
+	frontLoaded.TakeProfits = []*TakeProfitLevel{
+		{Price: 45500.0, Percentage: 75, Type: TakeProfitTypeLimit}, // 1R
+		{Price: 46500.0, Percentage: 25, Type: TakeProfitTypeLimit}, // 3R
+	}
+
+	withRunner := testPlan()
+	withRunner.TakeProfits = []*TakeProfitLevel{
+		{Price: 45500.0, Percentage: 40, Type: TakeProfitTypeLimit}, // 1R
+		{Price: 46500.0, Percentage: 40, Type: TakeProfitTypeLimit}, // 3R
+	}
+
+	short := testPlan()
+	short.Side = SideShort
+	short.StopLoss.Price = 45500.0
+	short.TakeProfits = []*TakeProfitLevel{
+		{Price: 44500.0, Percentage: 50, Type: TakeProfitTypeLimit}, // 1R
+		{Price: 43500.0, Percentage: 50, Type: TakeProfitTypeLimit}, // 3R
+	}
+
+	noTP := testPlan()
+	noTP.TakeProfits = nil
+
+	tests := []struct {
+		name string
+		plan *PositionPlan
+		want float64
+	}{
+		{name: "50% at 1R + 50% at 3R", plan: ladder, want: 2.0},
+		{name: "75% at 1R + 25% at 3R", plan: frontLoaded, want: 1.5},
+		{name: "Runner left out", plan: withRunner, want: 2.0},
+		{name: "SHORT ladder", plan: short, want: 2.0},
+		{name: "Single TP matches ImpliedRR", plan: testPlan(), want: 2.0},
+		{name: "No take profit", plan: noTP, want: 0},
+		{name: "Nil plan", plan: nil, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WeightedRR(tt.plan); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("WeightedRR() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActualRisk(t *testing.T) {
+	// testPlan: LONG 0.04 BTC at 45000, stop 44500, 20 planned risk
+	short := testPlan()
+	short.Side = SideShort
+	short.StopLoss.Price = 45500.0
+
+	noStop := testPlan()
+	noStop.StopLoss = nil
+
+	tests := []struct {
+		name        string
+		plan        *PositionPlan
+		actualEntry float64
+		want        float64
+	}{
+		{name: "LONG filled as planned", plan: testPlan(), actualEntry: 45000.0, want: 20.0},
+		{name: "LONG filled 100 higher", plan: testPlan(), actualEntry: 45100.0, want: 24.0},
+		{name: "LONG filled 100 lower", plan: testPlan(), actualEntry: 44900.0, want: 16.0},
+		{name: "SHORT filled 50 lower", plan: short, actualEntry: 44950.0, want: 22.0},
+		{name: "LONG filled past the stop", plan: testPlan(), actualEntry: 44400.0, want: 0},
+		{name: "No stop", plan: noStop, actualEntry: 45000.0, want: 0},
+		{name: "Nil plan", plan: nil, actualEntry: 45000.0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ActualRisk(tt.plan, tt.actualEntry); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ActualRisk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOneR(t *testing.T) {
+	// testPlan: 2:1 LONG risking 20, TP at 46000
+	plan := testPlan()
+
+	if got := OneR(plan); got != 20.0 {
+		t.Errorf("OneR() = %v, want 20", got)
+	}
+	if got, want := RToDollars(plan, 2), RealizedPnL(plan, plan.TakeProfits[0].Price); math.Abs(got-want) > 1e-9 {
+		t.Errorf("RToDollars(2) = %v, want the TP's %v", got, want)
+	}
+	if got, want := RToDollars(plan, -1), RealizedPnL(plan, plan.StopLoss.Price); math.Abs(got-want) > 1e-9 {
+		t.Errorf("RToDollars(-1) = %v, want the stop-out's %v", got, want)
+	}
+	if got := RToDollars(nil, 2); got != 0 {
+		t.Errorf("RToDollars(nil) = %v, want 0", got)
+	}
+}
+
+func TestPriceAtR(t *testing.T) {
+	calc := calculator.New(125)
+	long := testPlan() // LONG 45000, stop 44500
+	short := testPlan()
+	short.Side = SideShort
+	short.StopLoss.Price = 45500.0
+
+	tests := []struct {
+		name string
+		plan *PositionPlan
+		r    float64
+		want float64
+	}{
+		{name: "LONG 1R", plan: long, r: 1, want: calc.CalculateRRTakeProfit(45000.0, 44500.0, 1, SideLong)},
+		{name: "LONG 2R is the 2:1 TP", plan: long, r: 2, want: long.TakeProfits[0].Price},
+		{name: "LONG -1R is the stop", plan: long, r: -1, want: 44500.0},
+		{name: "SHORT 2R", plan: short, r: 2, want: calc.CalculateRRTakeProfit(45000.0, 45500.0, 2, SideShort)},
+		{name: "Zero R is entry", plan: short, r: 0, want: 45000.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PriceAtR(tt.plan, tt.r)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("PriceAtR(%v) = %v, want %v", tt.r, got, tt.want)
+			}
+			// Closing there realizes exactly r times the risk
+			if pnl := RealizedPnL(tt.plan, got); math.Abs(pnl-RToDollars(tt.plan, tt.r)) > 1e-9 {
+				t.Errorf("PnL at PriceAtR(%v) = %v, want %v", tt.r, pnl, RToDollars(tt.plan, tt.r))
+			}
+		})
+	}
+
+	noStop := testPlan()
+	noStop.StopLoss = nil
+	if got := PriceAtR(noStop, 2); got != 0 {
+		t.Errorf("PriceAtR() without stop = %v, want 0", got)
+	}
+}
+
+func TestClonePlan(t *testing.T) {
+	original := testPlan()
+	clone := ClonePlan(original)
+
+	clone.Size = 1.0
+	clone.StopLoss.Price = 44000.0
+	clone.TakeProfits[0].Price = 47000.0
+	clone.TakeProfits[0].Percentage = 50
+	clone.TakeProfits = append(clone.TakeProfits, &TakeProfitLevel{Price: 48000.0, Percentage: 50})
+
+	want := testPlan()
+	if original.Size != want.Size {
+		t.Errorf("original Size = %.4f, want %.4f", original.Size, want.Size)
+	}
+	if original.StopLoss.Price != want.StopLoss.Price {
+		t.Errorf("original StopLoss.Price = %.2f, want %.2f", original.StopLoss.Price, want.StopLoss.Price)
+	}
+	if len(original.TakeProfits) != 1 {
+		t.Fatalf("original len(TakeProfits) = %d, want 1", len(original.TakeProfits))
+	}
+	if *original.TakeProfits[0] != *want.TakeProfits[0] {
+		t.Errorf("original TakeProfits[0] = %+v, want %+v", *original.TakeProfits[0], *want.TakeProfits[0])
+	}
+}
+
+func TestClonePlan_NilFields(t *testing.T) {
+	if ClonePlan(nil) != nil {
+		t.Error("ClonePlan(nil) != nil")
+	}
+
+	plan := testPlan()
+	plan.StopLoss = nil
+	plan.TakeProfits = nil
+
+	clone := ClonePlan(plan)
+	if clone.StopLoss != nil || clone.TakeProfits != nil {
+		t.Errorf("ClonePlan() = %+v, want nil StopLoss and TakeProfits", clone)
+	}
+}
+
+func TestAdjustForFill(t *testing.T) {
+	// Planned ladder: 0.03 @ 45000, 0.03 @ 44800, 0.04 @ 44600, avg 44780.
+	// Stop 44280 risks 50 (5% of 1000); TPs at 2R and 3R.
+	plan := testPlan()
+	plan.Size = 0.1
+	plan.EntryPrice = 44780.0
+	plan.Leverage = 5
+	plan.StopLoss.Price = 44280.0
+	plan.TakeProfits = []*TakeProfitLevel{
+		{Price: 45780.0, Percentage: 50, Type: TakeProfitTypeLimit},
+		{Price: 46280.0, Percentage: 50, Type: TakeProfitTypeLimit},
+	}
+	plan.RiskAmount = 50.0
+	plan.RiskPercent = 5.0
+	plan.NotionalValue = 4478.0
+
+	// Only the first two rungs fill: 60% at 44900
+	got := AdjustForFill(plan, 0.06, 44900.0)
+	if got == nil {
+		t.Fatal("AdjustForFill() = nil, want adjusted plan")
+	}
+
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"Size", got.Size, 0.06},
+		{"EntryPrice", got.EntryPrice, 44900.0},
+		{"NotionalValue", got.NotionalValue, 2694.0},
+		{"RiskAmount", got.RiskAmount, 0.06 * 620},
+		{"RiskPercent", got.RiskPercent, 3.72},
+		{"Leverage", float64(got.Leverage), 3},
+		{"StopLoss", got.StopLoss.Price, 44280.0},
+		{"TP1 (2R)", got.TakeProfits[0].Price, 44900.0 + 2*620},
+		{"TP2 (3R)", got.TakeProfits[1].Price, 44900.0 + 3*620},
+		{"TP1 percentage", got.TakeProfits[0].Percentage, 50},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	if plan.Size != 0.1 || plan.TakeProfits[0].Price != 45780.0 {
+		t.Errorf("input plan modified: Size = %v, TP1 = %v", plan.Size, plan.TakeProfits[0].Price)
+	}
+}
+
+func TestAdjustForFill_Short(t *testing.T) {
+	plan := testPlan()
+	plan.Side = SideShort
+	plan.StopLoss.Price = 45500.0
+	plan.TakeProfits[0].Price = 44000.0 // 2R
+
+	// Half filled 100 better than planned
+	got := AdjustForFill(plan, 0.02, 44900.0)
+	if got == nil {
+		t.Fatal("AdjustForFill() = nil, want adjusted plan")
+	}
+	if math.Abs(got.RiskAmount-12.0) > 1e-9 || math.Abs(got.TakeProfits[0].Price-43700.0) > 1e-9 {
+		t.Errorf("RiskAmount = %v, TP = %v, want 12 and 43700", got.RiskAmount, got.TakeProfits[0].Price)
+	}
+}
+
+func TestAdjustForFill_LeverageClamp(t *testing.T) {
+	plan := testPlan()
+
+	// 0.0625 @ 48000 is 3x the 1000 balance: 3x with no buffer, 4x once 20%
+	// is held back
+	if got := AdjustForFill(plan, 0.0625, 48000.0); got == nil || got.Leverage != 3 {
+		t.Fatalf("AdjustForFill() = %+v, want leverage 3", got)
+	}
+	got := AdjustForFill(plan, 0.0625, 48000.0, WithFillMarginBuffer(20), WithFillMaxLeverage(5))
+	if got == nil || got.Leverage != 4 {
+		t.Fatalf("AdjustForFill(20%% buffer) = %+v, want leverage 4", got)
+	}
+	if got := AdjustForFill(plan, 0.0625, 48000.0, WithFillMarginBuffer(20), WithFillMaxLeverage(3)); got != nil {
+		t.Errorf("AdjustForFill(20%% buffer, max 3x) = %+v, want nil: margin needs 4x", got)
+	}
+}
+
+func TestAdjustForFill_Invalid(t *testing.T) {
+	noStop := testPlan()
+	noStop.StopLoss = nil
+	wrongSideTP := testPlan()
+	wrongSideTP.TakeProfits[0].Price = 44800.0 // Below the long entry
+
+	tests := []struct {
+		name         string
+		plan         *PositionPlan
+		filledSize   float64
+		avgFillPrice float64
+		opts         []FillOption
+	}{
+		{name: "Nil plan", plan: nil, filledSize: 0.02, avgFillPrice: 45000.0},
+		{name: "No stop", plan: noStop, filledSize: 0.02, avgFillPrice: 45000.0},
+		{name: "Nothing filled", plan: testPlan(), filledSize: 0, avgFillPrice: 45000.0},
+		{name: "Fill past the stop", plan: testPlan(), filledSize: 0.02, avgFillPrice: 44400.0},
+		{name: "TP behind the entry", plan: wrongSideTP, filledSize: 0.02, avgFillPrice: 44900.0},
+		{name: "Margin over max leverage", plan: testPlan(), filledSize: 1, avgFillPrice: 45000.0,
+			opts: []FillOption{WithFillMaxLeverage(10)}},
+		{name: "Margin buffer of 100%", plan: testPlan(), filledSize: 0.02, avgFillPrice: 45000.0,
+			opts: []FillOption{WithFillMarginBuffer(100)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AdjustForFill(tt.plan, tt.filledSize, tt.avgFillPrice, tt.opts...); got != nil {
+				t.Errorf("AdjustForFill() = %+v, want nil", got)
+			}
+		})
+	}
+}
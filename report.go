@@ -0,0 +1,90 @@
+package strategy
+
+import (
+	"math"
+)
+
+// RiskReport summarizes a plan's key risk figures for dashboards
+type RiskReport struct {
+	EffectiveRR           float64 // First TP reward over stop risk (0 if unknown)
+	DistanceToStopPercent float64 // Stop distance as % of entry
+	DistanceToTPPercent   float64 // First TP distance as % of entry
+	MarginRequired        float64 // Notional / leverage
+	LiquidationPrice      float64 // Estimated isolated-margin liquidation price
+	// LiquidationBufferPercent is how far beyond the stop the liquidation
+	// price sits, as % of entry. Negative means liquidation comes first.
+	LiquidationBufferPercent float64
+}
+
+// NewRiskReport computes a RiskReport for plan.
+//
+// The liquidation price is a simple isolated-margin estimate that ignores
+// maintenance margin and fees: entry * (1 -/+ 1/leverage) for LONG/SHORT.
+// Fields that need a missing stop or TP are left at zero.
+func NewRiskReport(plan *PositionPlan) RiskReport {
+	return newRiskReport(plan, 0)
+}
+
+// NewRiskReportWithBrackets is NewRiskReport with the liquidation price
+// brought in by the maintenance margin brackets require for the plan's
+// notional, as on exchanges with tiered maintenance rates
+func NewRiskReportWithBrackets(plan *PositionPlan, brackets LeverageBrackets) RiskReport {
+	if plan == nil {
+		return RiskReport{}
+	}
+	return newRiskReport(plan, MaintenanceMargin(plan.NotionalValue, brackets))
+}
+
+// LiquidationPrice returns the isolated-margin liquidation price of plan
+// once maintenanceMargin (quote currency) must be kept: the price where
+// the loss leaves only that much of the initial margin, fees ignored. With
+// no maintenance margin it is entry * (1 -/+ 1/leverage) for LONG/SHORT.
+// Returns 0 for a plan without leverage.
+func LiquidationPrice(plan *PositionPlan, maintenanceMargin float64) float64 {
+	if plan == nil || plan.Leverage <= 0 {
+		return 0
+	}
+	var buffer float64
+	if plan.Size != 0 {
+		buffer = maintenanceMargin / math.Abs(plan.Size)
+	}
+	if plan.Side == SideLong {
+		return plan.EntryPrice*(1-1/float64(plan.Leverage)) + buffer
+	}
+	return plan.EntryPrice*(1+1/float64(plan.Leverage)) - buffer
+}
+
+// newRiskReport computes the report with maintenanceMargin applied to the
+// liquidation price
+func newRiskReport(plan *PositionPlan, maintenanceMargin float64) RiskReport {
+	var r RiskReport
+	if plan == nil || plan.EntryPrice <= 0 {
+		return r
+	}
+
+	if rr, ok := ImpliedRR(plan); ok {
+		r.EffectiveRR = rr
+	}
+
+	if plan.StopLoss != nil {
+		r.DistanceToStopPercent = math.Abs(plan.EntryPrice-plan.StopLoss.Price) / plan.EntryPrice * 100
+	}
+	if len(plan.TakeProfits) > 0 && plan.TakeProfits[0] != nil {
+		r.DistanceToTPPercent = math.Abs(plan.TakeProfits[0].Price-plan.EntryPrice) / plan.EntryPrice * 100
+	}
+
+	if plan.Leverage > 0 {
+		r.MarginRequired = plan.NotionalValue / float64(plan.Leverage)
+		r.LiquidationPrice = LiquidationPrice(plan, maintenanceMargin)
+
+		if plan.StopLoss != nil {
+			buffer := plan.StopLoss.Price - r.LiquidationPrice
+			if plan.Side == SideShort {
+				buffer = -buffer
+			}
+			r.LiquidationBufferPercent = buffer / plan.EntryPrice * 100
+		}
+	}
+
+	return r
+}
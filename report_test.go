@@ -0,0 +1,126 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewRiskReport(t *testing.T) {
+	short := &PositionPlan{
+		Symbol:        "ETH-USDT",
+		Side:          SideShort,
+		Size:          1.0,
+		EntryPrice:    3000.0,
+		Leverage:      10,
+		StopLoss:      &StopLossLevel{Price: 3100.0, Type: StopLossTypeFixed},
+		TakeProfits:   []*TakeProfitLevel{{Price: 2700.0, Percentage: 100, Type: TakeProfitTypeLimit}},
+		NotionalValue: 3000.0,
+	}
+
+	tests := []struct {
+		name string
+		plan *PositionPlan
+		want RiskReport
+	}{
+		{
+			name: "Standard 2:1 LONG",
+			plan: testPlan(),
+			want: RiskReport{
+				EffectiveRR:              2.0,
+				DistanceToStopPercent:    1.1111, // 500 / 45000
+				DistanceToTPPercent:      2.2222, // 1000 / 45000
+				MarginRequired:           900.0,  // 1800 / 2x
+				LiquidationPrice:         22500.0,
+				LiquidationBufferPercent: 48.8889, // (44500 - 22500) / 45000
+			},
+		},
+		{
+			name: "3:1 SHORT at 10x",
+			plan: short,
+			want: RiskReport{
+				EffectiveRR:              3.0,
+				DistanceToStopPercent:    3.3333,
+				DistanceToTPPercent:      10.0,
+				MarginRequired:           300.0,
+				LiquidationPrice:         3300.0,
+				LiquidationBufferPercent: 6.6667, // (3300 - 3100) / 3000
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewRiskReport(tt.plan)
+
+			checks := []struct {
+				field     string
+				got, want float64
+			}{
+				{"EffectiveRR", got.EffectiveRR, tt.want.EffectiveRR},
+				{"DistanceToStopPercent", got.DistanceToStopPercent, tt.want.DistanceToStopPercent},
+				{"DistanceToTPPercent", got.DistanceToTPPercent, tt.want.DistanceToTPPercent},
+				{"MarginRequired", got.MarginRequired, tt.want.MarginRequired},
+				{"LiquidationPrice", got.LiquidationPrice, tt.want.LiquidationPrice},
+				{"LiquidationBufferPercent", got.LiquidationBufferPercent, tt.want.LiquidationBufferPercent},
+			}
+			for _, c := range checks {
+				if math.Abs(c.got-c.want) > 0.0001 {
+					t.Errorf("%s = %.4f, want %.4f", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRiskReport_MissingLevels(t *testing.T) {
+	plan := testPlan()
+	plan.StopLoss = nil
+	plan.TakeProfits = nil
+
+	got := NewRiskReport(plan)
+	if got.EffectiveRR != 0 || got.DistanceToStopPercent != 0 || got.DistanceToTPPercent != 0 || got.LiquidationBufferPercent != 0 {
+		t.Errorf("NewRiskReport() = %+v, want zero stop/TP-derived fields", got)
+	}
+	if got.MarginRequired != 900.0 {
+		t.Errorf("MarginRequired = %.2f, want 900.00", got.MarginRequired)
+	}
+}
+
+func TestLiquidationPrice_Maintenance(t *testing.T) {
+	// testPlan: LONG 0.04 BTC at 45000, 2x, 1800 notional
+	plan := testPlan()
+	short := testPlan()
+	short.Side = SideShort
+
+	tests := []struct {
+		name        string
+		plan        *PositionPlan
+		maintenance float64
+		want        float64
+	}{
+		{name: "LONG without maintenance", plan: plan, maintenance: 0, want: 22500.0},
+		{name: "LONG with 10 maintenance", plan: plan, maintenance: 10, want: 22750.0},
+		{name: "SHORT with 10 maintenance", plan: short, maintenance: 10, want: 67250.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LiquidationPrice(tt.plan, tt.maintenance); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("LiquidationPrice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 1800 notional sits in the 0.4% tier: 7.2 maintenance, 180 per BTC
+	brackets := LeverageBrackets{
+		{NotionalCap: 50000, MaxLeverage: 125, MaintenanceRate: 0.004},
+		{NotionalCap: 250000, MaxLeverage: 100, MaintenanceRate: 0.005},
+	}
+	r := NewRiskReportWithBrackets(plan, brackets)
+	if math.Abs(r.LiquidationPrice-22680.0) > 1e-9 {
+		t.Errorf("LiquidationPrice = %v, want 22680", r.LiquidationPrice)
+	}
+	if base := NewRiskReport(plan); r.LiquidationBufferPercent >= base.LiquidationBufferPercent {
+		t.Errorf("LiquidationBufferPercent = %v, want below %v once maintenance applies", r.LiquidationBufferPercent, base.LiquidationBufferPercent)
+	}
+}
@@ -0,0 +1,41 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrStrategyPanic is returned by SafeCalculate when the strategy panics
+var ErrStrategyPanic = errors.New("strategy panicked")
+
+// SafeCalculate calls strat.CalculatePosition and converts a panic into an
+// error wrapping ErrStrategyPanic, so a buggy strategy can't crash a
+// long-running process. The error message includes the panic stack. A nil
+// strat is an error rather than a panic.
+func SafeCalculate(ctx context.Context, strat Strategy, params PositionParams) (plan *PositionPlan, err error) {
+	if strat == nil {
+		return nil, fmt.Errorf("strategy is nil")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			plan = nil
+			err = fmt.Errorf("%w: %s: %v\n%s", ErrStrategyPanic, strategyName(strat), r, debug.Stack())
+		}
+	}()
+
+	return strat.CalculatePosition(ctx, params)
+}
+
+// strategyName returns strat.Name(), or a placeholder when Name itself
+// panics, so reporting a recovered panic can't panic again
+func strategyName(strat Strategy) (name string) {
+	defer func() {
+		if recover() != nil {
+			name = "unnamed strategy"
+		}
+	}()
+	return strat.Name()
+}
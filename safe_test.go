@@ -0,0 +1,102 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// stubStrategy returns plan from CalculatePosition, or panics with panicValue when set
+type stubStrategy struct {
+	NoParameters
+
+	plan       *PositionPlan
+	panicValue interface{}
+}
+
+func (s *stubStrategy) Name() string                               { return "stub" }
+func (s *stubStrategy) Description() string                        { return "Test stub strategy" }
+func (s *stubStrategy) ValidateParams(params StrategyParams) error { return nil }
+
+func (s *stubStrategy) CalculatePosition(ctx context.Context, params PositionParams) (*PositionPlan, error) {
+	if s.panicValue != nil {
+		panic(s.panicValue)
+	}
+	return s.plan, nil
+}
+
+func (s *stubStrategy) OnPositionOpened(ctx context.Context, position *Position) error {
+	return nil
+}
+
+func (s *stubStrategy) OnPriceUpdate(ctx context.Context, position *Position, currentPrice float64) (*StrategyAction, error) {
+	return &StrategyAction{Type: ActionTypeNone}, nil
+}
+
+func (s *stubStrategy) ShouldClose(ctx context.Context, position *Position, currentPrice float64) (bool, string) {
+	return false, ""
+}
+
+func TestSafeCalculate_Panic(t *testing.T) {
+	strat := &stubStrategy{panicValue: "index out of range"}
+
+	plan, err := SafeCalculate(context.Background(), strat, PositionParams{})
+	if !errors.Is(err, ErrStrategyPanic) {
+		t.Fatalf("SafeCalculate() error = %v, want ErrStrategyPanic", err)
+	}
+	if plan != nil {
+		t.Error("SafeCalculate() returned a plan alongside the panic error")
+	}
+	if !strings.Contains(err.Error(), "stub: index out of range") {
+		t.Errorf("error %q should name the strategy and panic value", err)
+	}
+	if !strings.Contains(err.Error(), "goroutine") {
+		t.Errorf("error %q should include the stack", err)
+	}
+}
+
+func TestSafeCalculate_NoPanic(t *testing.T) {
+	want := testPlan()
+	strat := &stubStrategy{plan: want}
+
+	plan, err := SafeCalculate(context.Background(), strat, PositionParams{})
+	if err != nil {
+		t.Fatalf("SafeCalculate() error = %v, want nil", err)
+	}
+	if plan != want {
+		t.Error("SafeCalculate() should return the strategy's plan unchanged")
+	}
+}
+
+// panickyNameStrategy panics from both CalculatePosition and Name
+type panickyNameStrategy struct {
+	stubStrategy
+}
+
+func (s *panickyNameStrategy) Name() string { panic("name unavailable") }
+
+func TestSafeCalculate_PanickingName(t *testing.T) {
+	strat := &panickyNameStrategy{stubStrategy{panicValue: "boom"}}
+
+	_, err := SafeCalculate(context.Background(), strat, PositionParams{})
+	if !errors.Is(err, ErrStrategyPanic) {
+		t.Fatalf("SafeCalculate() error = %v, want ErrStrategyPanic", err)
+	}
+	if !strings.Contains(err.Error(), "unnamed strategy: boom") {
+		t.Errorf("error %q should fall back to a placeholder name", err)
+	}
+}
+
+func TestSafeCalculate_NilStrategy(t *testing.T) {
+	plan, err := SafeCalculate(context.Background(), nil, PositionParams{})
+	if err == nil {
+		t.Fatal("SafeCalculate(nil) error = nil, want error")
+	}
+	if errors.Is(err, ErrStrategyPanic) {
+		t.Errorf("SafeCalculate(nil) error = %v, want a plain error, not a recovered panic", err)
+	}
+	if plan != nil {
+		t.Errorf("SafeCalculate(nil) plan = %+v, want nil", plan)
+	}
+}
@@ -0,0 +1,79 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+)
+
+// selfTestTolerance is the relative error SelfTest accepts on float results
+const selfTestTolerance = 1e-9
+
+// Calculator is the sizing, take-profit and leverage math the strategies
+// rely on. Both *calculator.Calculator and *DecimalCalculator satisfy it.
+type Calculator interface {
+	CalculateSize(balance, riskPercent, entry, stopLoss float64, side Side) float64
+	CalculateRRTakeProfit(entry, stopLoss, rrRatio float64, side Side) float64
+	CalculateLeverage(size, price, balance float64, maxLeverage int) int
+}
+
+// selfTestCase is one known-answer setup checked by SelfTest
+type selfTestCase struct {
+	name        string
+	side        Side
+	balance     float64
+	riskPercent float64
+	entry       float64
+	stopLoss    float64
+	rrRatio     float64
+	maxLeverage int
+	size        float64
+	takeProfit  float64
+	leverage    int
+}
+
+// selfTestCases are hand-checked answers for the canonical setups in the tests
+var selfTestCases = []selfTestCase{
+	{
+		name: "BTC long 2% risk, 2:1 RR", side: SideLong,
+		balance: 1000, riskPercent: 2, entry: 45000, stopLoss: 44500, rrRatio: 2, maxLeverage: 125,
+		size: 0.04, takeProfit: 46000, leverage: 2,
+	},
+	{
+		name: "ETH short 1% risk, 2:1 RR", side: SideShort,
+		balance: 1000, riskPercent: 1, entry: 3000, stopLoss: 3100, rrRatio: 2, maxLeverage: 125,
+		size: 0.1, takeProfit: 2800, leverage: 1,
+	},
+	{
+		name: "BTC long tight stop, leverage capped", side: SideLong,
+		balance: 1000, riskPercent: 2, entry: 45000, stopLoss: 44990, rrRatio: 2, maxLeverage: 20,
+		size: 2, takeProfit: 45020, leverage: 20,
+	},
+}
+
+// SelfTest runs calc through a handful of known-answer calculations and
+// returns an error naming the first result that diverges, as a startup
+// health check before trading with a calculator build
+func SelfTest(calc Calculator) error {
+	for _, tc := range selfTestCases {
+		size := calc.CalculateSize(tc.balance, tc.riskPercent, tc.entry, tc.stopLoss, tc.side)
+		if !selfTestClose(size, tc.size) {
+			return fmt.Errorf("self-test %q: size = %v, want %v", tc.name, size, tc.size)
+		}
+
+		tp := calc.CalculateRRTakeProfit(tc.entry, tc.stopLoss, tc.rrRatio, tc.side)
+		if !selfTestClose(tp, tc.takeProfit) {
+			return fmt.Errorf("self-test %q: take profit = %v, want %v", tc.name, tp, tc.takeProfit)
+		}
+
+		leverage := calc.CalculateLeverage(tc.size, tc.entry, tc.balance, tc.maxLeverage)
+		if leverage != tc.leverage {
+			return fmt.Errorf("self-test %q: leverage = %d, want %d", tc.name, leverage, tc.leverage)
+		}
+	}
+	return nil
+}
+
+// selfTestClose reports whether got is within selfTestTolerance of want
+func selfTestClose(got, want float64) bool {
+	return math.Abs(got-want) <= selfTestTolerance*math.Max(1, math.Abs(want))
+}
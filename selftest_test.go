@@ -0,0 +1,46 @@
+package strategy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/calculator-go"
+)
+
+// skewedCalculator wraps a calculator and scales its sizes, standing in for a
+// build whose math has drifted
+type skewedCalculator struct {
+	Calculator
+	factor float64
+}
+
+func (c skewedCalculator) CalculateSize(balance, riskPercent, entry, stopLoss float64, side Side) float64 {
+	return c.Calculator.CalculateSize(balance, riskPercent, entry, stopLoss, side) * c.factor
+}
+
+func TestSelfTest(t *testing.T) {
+	tests := []struct {
+		name    string
+		calc    Calculator
+		wantErr string
+	}{
+		{name: "calculator-go", calc: calculator.New(125)},
+		{name: "Decimal calculator", calc: NewDecimalCalculator(125, 8)},
+		{name: "Drifted sizing", calc: skewedCalculator{Calculator: calculator.New(125), factor: 1.01}, wantErr: "size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SelfTest(tt.calc)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("SelfTest() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("SelfTest() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package strategy
+
+import "sync"
+
+// RealizedPnL returns the profit (negative for a loss) of closing the whole
+// plan at exitPrice
+func RealizedPnL(plan *PositionPlan, exitPrice float64) float64 {
+	if plan == nil {
+		return 0
+	}
+	pnl := plan.Size * (exitPrice - plan.EntryPrice)
+	if plan.Side == SideShort {
+		pnl = -pnl
+	}
+	return pnl
+}
+
+// Session tracks running equity across sequential trades so each new plan
+// sizes off the compounded balance instead of a static one. It is safe for
+// concurrent use.
+type Session struct {
+	mu             sync.Mutex
+	startingEquity float64
+	equity         float64
+}
+
+// NewSession starts a session with startingEquity
+func NewSession(startingEquity float64) *Session {
+	return &Session{
+		startingEquity: startingEquity,
+		equity:         startingEquity,
+	}
+}
+
+// Balance returns the current equity
+func (s *Session) Balance() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.equity
+}
+
+// ReturnPercent returns the session's compounded return so far
+func (s *Session) ReturnPercent() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.startingEquity == 0 {
+		return 0
+	}
+	return (s.equity - s.startingEquity) / s.startingEquity * 100
+}
+
+// ApplyPnL adds realized pnl to equity and returns the new balance
+func (s *Session) ApplyPnL(pnl float64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.equity += pnl
+	return s.equity
+}
+
+// Close applies the realized PnL of closing plan at exitPrice and returns it
+func (s *Session) Close(plan *PositionPlan, exitPrice float64) float64 {
+	pnl := RealizedPnL(plan, exitPrice)
+	s.ApplyPnL(pnl)
+	return pnl
+}
+
+// Params returns params with AccountBalance set to the current equity, ready
+// to pass to CalculatePosition
+func (s *Session) Params(params PositionParams) PositionParams {
+	params.AccountBalance = s.Balance()
+	return params
+}
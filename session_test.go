@@ -0,0 +1,79 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/agatticelli/calculator-go"
+)
+
+func TestRealizedPnL(t *testing.T) {
+	short := testPlan()
+	short.Side = SideShort
+
+	tests := []struct {
+		name string
+		plan *PositionPlan
+		exit float64
+		want float64
+	}{
+		{name: "LONG win", plan: testPlan(), exit: 46000.0, want: 40.0},
+		{name: "LONG loss", plan: testPlan(), exit: 44500.0, want: -20.0},
+		{name: "SHORT win", plan: short, exit: 44500.0, want: 20.0},
+		{name: "Nil plan", plan: nil, exit: 46000.0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RealizedPnL(tt.plan, tt.exit); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("RealizedPnL() = %.4f, want %.4f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSession_Compounding(t *testing.T) {
+	calc := calculator.New(125)
+	session := NewSession(1000.0)
+	base := PositionParams{
+		Symbol:      "BTC-USDT",
+		Side:        SideLong,
+		EntryPrice:  45000.0,
+		StopLoss:    44500.0,
+		RiskPercent: 2.0,
+		MaxLeverage: 125,
+	}
+
+	// Each trade wins 2R = 4% of the equity it was sized from
+	wantSizes := []float64{0.04, 0.0416, 0.043264}
+	wantEquity := []float64{1040.0, 1081.6, 1124.864}
+
+	for i := range wantSizes {
+		params := session.Params(base)
+		size := calc.CalculateSize(params.AccountBalance, params.RiskPercent, params.EntryPrice, params.StopLoss, params.Side)
+		if math.Abs(size-wantSizes[i]) > 1e-9 {
+			t.Errorf("trade %d size = %.6f, want %.6f", i+1, size, wantSizes[i])
+		}
+
+		plan := &PositionPlan{Symbol: params.Symbol, Side: params.Side, Size: size, EntryPrice: params.EntryPrice}
+		session.Close(plan, 46000.0)
+
+		if got := session.Balance(); math.Abs(got-wantEquity[i]) > 1e-9 {
+			t.Errorf("equity after trade %d = %.4f, want %.4f", i+1, got, wantEquity[i])
+		}
+	}
+
+	if got := session.ReturnPercent(); math.Abs(got-12.4864) > 1e-9 {
+		t.Errorf("ReturnPercent() = %.4f, want 12.4864", got)
+	}
+}
+
+func TestSession_ApplyPnL(t *testing.T) {
+	session := NewSession(1000.0)
+	if got := session.ApplyPnL(-50); got != 950 {
+		t.Errorf("ApplyPnL(-50) = %.2f, want 950.00", got)
+	}
+	if got := session.Params(PositionParams{AccountBalance: 1}).AccountBalance; got != 950 {
+		t.Errorf("Params().AccountBalance = %.2f, want 950.00", got)
+	}
+}
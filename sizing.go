@@ -0,0 +1,267 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// RiskAmount returns the quote-currency amount riskPercent of balance risks
+func RiskAmount(balance, riskPercent float64) float64 {
+	return balance * riskPercent / 100
+}
+
+// RiskPercent returns riskAmount as a percentage of balance, the inverse of
+// RiskAmount. Returns 0 for a non-positive balance.
+func RiskPercent(riskAmount, balance float64) float64 {
+	if balance <= 0 {
+		return 0
+	}
+	return riskAmount / balance * 100
+}
+
+// MaxStopDistanceForLeverage returns the stop distance at which risk-based
+// sizing needs exactly maxLeverage.
+//
+// Size is (balance * risk%) / distance, so leverage falls as the stop widens:
+// any stop at least this far from entry stays at or below the cap, while a
+// tighter one forces the max-leverage clamp. Balance cancels out of the
+// formula but is kept so callers can pass the same inputs as CalculateSize.
+// Returns 0 for non-positive inputs.
+func MaxStopDistanceForLeverage(balance, riskPercent, entry float64, maxLeverage int) float64 {
+	if balance <= 0 || riskPercent <= 0 || entry <= 0 || maxLeverage <= 0 {
+		return 0
+	}
+
+	// leverage = (balance * risk% / distance) * entry / balance
+	// => distance = entry * risk% / leverage
+	return entry * riskPercent / 100 / float64(maxLeverage)
+}
+
+// SizeForMaxLoss returns the position size whose stop-out loss equals
+// maxLoss in quote currency, for externally imposed dollar caps (e.g. a prop
+// firm's max daily loss) rather than a percent of balance
+func SizeForMaxLoss(maxLoss, entry, stopLoss float64, side Side) (float64, error) {
+	if maxLoss <= 0 || entry <= 0 || stopLoss <= 0 {
+		return 0, fmt.Errorf("max loss and prices must be positive")
+	}
+	if err := ValidateStopLoss(side, entry, stopLoss, DefaultPriceEpsilon); err != nil {
+		return 0, err
+	}
+
+	return maxLoss / math.Abs(entry-stopLoss), nil
+}
+
+// StopForRisk is the inverse of SizeForMaxLoss: for a size fixed elsewhere
+// (e.g. by a rebalance), it returns the stop price at which a stop-out
+// loses riskDollars, riskDollars/size away from entry. Returns 0 for
+// non-positive inputs, an unknown side, or a LONG stop that would fall to
+// or below zero.
+func StopForRisk(entry, size, riskDollars float64, side Side) float64 {
+	if entry <= 0 || size <= 0 || riskDollars <= 0 {
+		return 0
+	}
+
+	distance := riskDollars / size
+	switch side {
+	case SideLong:
+		if distance >= entry {
+			return 0
+		}
+		return entry - distance
+	case SideShort:
+		return entry + distance
+	}
+	return 0
+}
+
+// MinBalanceForSetup returns the smallest account balance at which risking
+// riskPercent on the setup gives a size whose notional meets the exchange's
+// minNotional. Size scales with balance, so this is
+// minNotional * distance / (entry * risk%).
+//
+// Required leverage doesn't depend on balance, so when the setup needs more
+// than maxLeverage no balance can take it in full and +Inf is returned
+// (maxLeverage <= 0 means no cap). Returns 0 when minNotional is not positive
+// or the inputs are invalid.
+func MinBalanceForSetup(entry, stopLoss float64, side Side, riskPercent float64, minNotional float64, maxLeverage int) float64 {
+	if entry <= 0 || stopLoss <= 0 || riskPercent <= 0 || minNotional <= 0 {
+		return 0
+	}
+	if ValidateStopLoss(side, entry, stopLoss, DefaultPriceEpsilon) != nil {
+		return 0
+	}
+
+	// notional / balance = entry * risk% / distance
+	distance := math.Abs(entry - stopLoss)
+	notionalPerBalance := entry * riskPercent / 100 / distance
+	if maxLeverage > 0 && notionalPerBalance > float64(maxLeverage) {
+		return math.Inf(1)
+	}
+	return minNotional / notionalPerBalance
+}
+
+// SizeFromMarginPercent returns the size that commits marginPercent of
+// balance as margin at leverage: balance*(marginPercent/100)*leverage/price.
+// Unlike risk-based sizing it ignores the stop, so the loss at the stop
+// depends on its distance. Returns 0 for non-positive inputs or a
+// marginPercent above 100.
+func SizeFromMarginPercent(balance, marginPercent float64, leverage int, price float64) float64 {
+	if balance <= 0 || marginPercent <= 0 || marginPercent > 100 || leverage <= 0 || price <= 0 {
+		return 0
+	}
+	return balance * (marginPercent / 100) * float64(leverage) / price
+}
+
+// SizeForDelta returns the signed position size that offsets delta, e.g.
+// from an options book: positive means LONG, negative SHORT. Each unit of
+// the contract contributes contractMultiplier delta (1 for a linear perp),
+// so a -0.7 delta is hedged by 0.7 units long. Returns 0 for a non-positive
+// multiplier.
+func SizeForDelta(delta, contractMultiplier float64) float64 {
+	if contractMultiplier <= 0 {
+		return 0
+	}
+	return -delta / contractMultiplier
+}
+
+// DefaultMaxLeverage is the leverage ceiling the bundled strategies apply on
+// top of params.MaxLeverage, which can lower it but never raise it
+const DefaultMaxLeverage = 125
+
+// EffectiveMaxLeverage reconciles the caller's params.MaxLeverage with a
+// strategy's own ceiling: the effective cap is min(paramsMax, strategyCap),
+// so neither side can raise leverage past the other. A non-positive value on
+// either side means that side sets no cap.
+func EffectiveMaxLeverage(paramsMax, strategyCap int) int {
+	switch {
+	case paramsMax <= 0:
+		return strategyCap
+	case strategyCap <= 0:
+		return paramsMax
+	}
+	return min(paramsMax, strategyCap)
+}
+
+// atrMarginBudgetPercent is the share of margin RecommendLeverage lets a
+// single ATR move against the position consume
+const atrMarginBudgetPercent = 10.0
+
+// RecommendLeverage suggests a leverage for current volatility, as opposed to
+// the mechanical leverage a size requires: the highest leverage at which a
+// one-ATR adverse move costs at most atrMarginBudgetPercent of margin, i.e.
+// 10/atr%. That is 20x at 0.5% ATR, 5x at 2% and 1x from 10% up. The result
+// is at least 1 and at most maxLeverage (maxLeverage <= 0 means no cap).
+// Returns 0 for a non-positive or non-finite atrPercent.
+func RecommendLeverage(atrPercent float64, maxLeverage int) int {
+	if !(atrPercent > 0) || math.IsInf(atrPercent, 0) {
+		return 0
+	}
+
+	lev := math.Floor(atrMarginBudgetPercent / atrPercent)
+	if maxLeverage > 0 && lev > float64(maxLeverage) {
+		return maxLeverage
+	}
+	if lev < 1 {
+		return 1
+	}
+	return int(lev)
+}
+
+// SnapLeverage rounds leverage up to the nearest value in allowed, for
+// exchanges that only accept a discrete set of leverage settings.
+//
+// The result never exceeds maxLeverage (maxLeverage <= 0 means no cap): when
+// no allowed value covers the required leverage, the highest allowed value
+// within the cap is returned, mirroring how leverage clamps at the max.
+// An empty allowed list returns leverage unchanged.
+func SnapLeverage(leverage int, allowed []int, maxLeverage int) (int, error) {
+	if len(allowed) == 0 {
+		return leverage, nil
+	}
+
+	best, highest := 0, 0
+	for _, lev := range allowed {
+		if lev <= 0 {
+			return 0, fmt.Errorf("allowed leverages must be positive, got %d", lev)
+		}
+		if maxLeverage > 0 && lev > maxLeverage {
+			continue
+		}
+		if lev >= leverage && (best == 0 || lev < best) {
+			best = lev
+		}
+		if lev > highest {
+			highest = lev
+		}
+	}
+
+	if best != 0 {
+		return best, nil
+	}
+	if highest != 0 {
+		return highest, nil
+	}
+	return 0, fmt.Errorf("no allowed leverage within max %dx", maxLeverage)
+}
+
+// maxExactFloat is the largest integer float64 represents exactly (2^53)
+const maxExactFloat = 1 << 53
+
+// ScaledSize computes a risk-based position size and its notional using
+// scaled integers (satoshi-style) instead of float64 arithmetic.
+//
+// Prices are converted to integer ticks of 10^-priceDecimals, so the stop
+// distance is exact even for assets priced like 0.00001234 where
+// subtracting two nearby floats loses precision. The size is rounded down to
+// sizeDecimals places (never risking more than requested), and the notional
+// is computed from that rounded size, so notional == size * entry exactly.
+// Errors when the risk buys less than one unit of 10^-sizeDecimals.
+func ScaledSize(balance, riskPercent, entry, stopLoss float64, priceDecimals, sizeDecimals int) (size, notional float64, err error) {
+	if balance <= 0 || riskPercent <= 0 || entry <= 0 || stopLoss <= 0 {
+		return 0, 0, fmt.Errorf("balance, risk percent and prices must be positive")
+	}
+	if priceDecimals < 0 || sizeDecimals < 0 {
+		return 0, 0, fmt.Errorf("decimals must not be negative")
+	}
+
+	priceScale := math.Pow10(priceDecimals)
+	entryScaled := math.Round(entry * priceScale)
+	stopScaled := math.Round(stopLoss * priceScale)
+	if entryScaled > maxExactFloat || stopScaled > maxExactFloat {
+		return 0, 0, fmt.Errorf("%d price decimals overflow exact integer range for entry %v", priceDecimals, entry)
+	}
+
+	entryTicks := big.NewInt(int64(entryScaled))
+	distTicks := big.NewInt(int64(math.Abs(entryScaled - stopScaled)))
+	if distTicks.Sign() == 0 {
+		return 0, 0, fmt.Errorf("stop loss equals entry at %d price decimals", priceDecimals)
+	}
+
+	priceScaleInt := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(priceDecimals)), nil)
+	sizeScaleInt := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(sizeDecimals)), nil)
+
+	// Parse the shortest decimal form so e.g. 0.3 isn't 0.29999999999999998
+	risk, ok := new(big.Rat).SetString(formatFloat(RiskAmount(balance, riskPercent)))
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid risk amount")
+	}
+
+	// size units = floor(risk$ * priceScale * sizeScale / distTicks)
+	units := new(big.Rat).Mul(risk, new(big.Rat).SetInt(new(big.Int).Mul(priceScaleInt, sizeScaleInt)))
+	units.Quo(units, new(big.Rat).SetInt(distTicks))
+	sizeUnits := new(big.Int).Quo(units.Num(), units.Denom())
+	if sizeUnits.Sign() == 0 {
+		return 0, 0, fmt.Errorf("size rounds to zero at %d size decimals", sizeDecimals)
+	}
+
+	sizeRat := new(big.Rat).SetFrac(sizeUnits, sizeScaleInt)
+	notionalRat := new(big.Rat).SetFrac(
+		new(big.Int).Mul(sizeUnits, entryTicks),
+		new(big.Int).Mul(sizeScaleInt, priceScaleInt),
+	)
+
+	size, _ = sizeRat.Float64()
+	notional, _ = notionalRat.Float64()
+	return size, notional, nil
+}
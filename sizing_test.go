@@ -0,0 +1,517 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/agatticelli/calculator-go"
+)
+
+func TestRiskAmountRiskPercent(t *testing.T) {
+	tests := []struct {
+		name        string
+		balance     float64
+		riskPercent float64
+		wantAmount  float64
+	}{
+		{name: "2% of 1000", balance: 1000.0, riskPercent: 2.0, wantAmount: 20.0},
+		{name: "Fractional percent", balance: 2500.0, riskPercent: 0.75, wantAmount: 18.75},
+		{name: "Large balance", balance: 1e9, riskPercent: 1.5, wantAmount: 1.5e7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount := RiskAmount(tt.balance, tt.riskPercent)
+			if math.Abs(amount-tt.wantAmount) > 1e-9*tt.wantAmount {
+				t.Errorf("RiskAmount() = %v, want %v", amount, tt.wantAmount)
+			}
+			// Round trip back to the percent
+			if pct := RiskPercent(amount, tt.balance); math.Abs(pct-tt.riskPercent) > 1e-12 {
+				t.Errorf("RiskPercent(RiskAmount()) = %v, want %v", pct, tt.riskPercent)
+			}
+		})
+	}
+
+	for _, balance := range []float64{0, -1000.0} {
+		if pct := RiskPercent(20.0, balance); pct != 0 {
+			t.Errorf("RiskPercent(20, %v) = %v, want 0", balance, pct)
+		}
+	}
+}
+
+func TestMaxStopDistanceForLeverage(t *testing.T) {
+	tests := []struct {
+		name         string
+		balance      float64
+		riskPercent  float64
+		entry        float64
+		maxLeverage  int
+		wantDistance float64
+	}{
+		{
+			name:         "BTC 2% risk, 8x cap",
+			balance:      1000.0,
+			riskPercent:  2.0,
+			entry:        40000.0,
+			maxLeverage:  8,
+			wantDistance: 100.0,
+		},
+		{
+			name:         "ETH 1% risk, 5x cap",
+			balance:      2000.0,
+			riskPercent:  1.0,
+			entry:        3000.0,
+			maxLeverage:  5,
+			wantDistance: 6.0,
+		},
+		{
+			name:         "Invalid: zero leverage",
+			balance:      1000.0,
+			riskPercent:  2.0,
+			entry:        40000.0,
+			maxLeverage:  0,
+			wantDistance: 0,
+		},
+		{
+			name:         "Invalid: zero balance",
+			balance:      0,
+			riskPercent:  2.0,
+			entry:        40000.0,
+			maxLeverage:  8,
+			wantDistance: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MaxStopDistanceForLeverage(tt.balance, tt.riskPercent, tt.entry, tt.maxLeverage)
+			if math.Abs(got-tt.wantDistance) > 1e-9 {
+				t.Errorf("MaxStopDistanceForLeverage() = %.6f, want %.6f", got, tt.wantDistance)
+			}
+		})
+	}
+}
+
+// TestMaxStopDistanceForLeverage_CrossCheck sizes a LONG with the returned stop
+// and confirms the calculator needs exactly the cap leverage
+func TestMaxStopDistanceForLeverage_CrossCheck(t *testing.T) {
+	calc := calculator.New(125)
+
+	tests := []struct {
+		name        string
+		balance     float64
+		riskPercent float64
+		entry       float64
+		maxLeverage int
+	}{
+		{name: "8x cap", balance: 1000.0, riskPercent: 2.0, entry: 40000.0, maxLeverage: 8},
+		{name: "5x cap", balance: 2000.0, riskPercent: 1.0, entry: 3000.0, maxLeverage: 5},
+		{name: "20x cap", balance: 500.0, riskPercent: 4.0, entry: 2000.0, maxLeverage: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			distance := MaxStopDistanceForLeverage(tt.balance, tt.riskPercent, tt.entry, tt.maxLeverage)
+			stopLoss := tt.entry - distance
+
+			size := calc.CalculateSize(tt.balance, tt.riskPercent, tt.entry, stopLoss, SideLong)
+			// Use a generous cap so the result is the required leverage, not the clamp
+			leverage := calc.CalculateLeverage(size, tt.entry, tt.balance, 125)
+			if leverage != tt.maxLeverage {
+				t.Errorf("leverage at returned stop = %d, want %d", leverage, tt.maxLeverage)
+			}
+
+			// A tighter stop must need more than the cap
+			tighter := calc.CalculateSize(tt.balance, tt.riskPercent, tt.entry, tt.entry-distance*0.9, SideLong)
+			if lev := calc.CalculateLeverage(tighter, tt.entry, tt.balance, 125); lev <= tt.maxLeverage {
+				t.Errorf("leverage at tighter stop = %d, want > %d", lev, tt.maxLeverage)
+			}
+		})
+	}
+}
+
+func TestSizeForMaxLoss(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxLoss  float64
+		entry    float64
+		stopLoss float64
+		side     Side
+		wantErr  bool
+	}{
+		{name: "LONG BTC $50 cap", maxLoss: 50.0, entry: 45000.0, stopLoss: 44500.0, side: SideLong},
+		{name: "SHORT ETH $125 cap", maxLoss: 125.0, entry: 3000.0, stopLoss: 3075.0, side: SideShort},
+		{name: "Low-priced asset", maxLoss: 10.0, entry: 0.00001234, stopLoss: 0.00001200, side: SideLong},
+		{name: "Invalid: zero max loss", maxLoss: 0, entry: 45000.0, stopLoss: 44500.0, side: SideLong, wantErr: true},
+		{name: "Invalid: stop on wrong side", maxLoss: 50.0, entry: 45000.0, stopLoss: 45500.0, side: SideLong, wantErr: true},
+		{name: "Invalid: stop equals entry", maxLoss: 50.0, entry: 45000.0, stopLoss: 45000.0, side: SideShort, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, err := SizeForMaxLoss(tt.maxLoss, tt.entry, tt.stopLoss, tt.side)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SizeForMaxLoss() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			loss := size * math.Abs(tt.entry-tt.stopLoss)
+			if math.Abs(loss-tt.maxLoss) > 1e-9*tt.maxLoss {
+				t.Errorf("stop-out loss = %v, want %v", loss, tt.maxLoss)
+			}
+		})
+	}
+}
+
+func TestStopForRisk(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    float64
+		size     float64
+		risk     float64
+		side     Side
+		wantStop float64
+	}{
+		{name: "LONG BTC $20 at 0.04", entry: 45000.0, size: 0.04, risk: 20.0, side: SideLong, wantStop: 44500.0},
+		{name: "SHORT ETH $20 at 0.2", entry: 3000.0, size: 0.2, risk: 20.0, side: SideShort, wantStop: 3100.0},
+		{name: "Invalid: LONG stop below zero", entry: 100.0, size: 1.0, risk: 150.0, side: SideLong, wantStop: 0},
+		{name: "Invalid: zero size", entry: 45000.0, size: 0, risk: 20.0, side: SideLong, wantStop: 0},
+		{name: "Invalid: unknown side", entry: 45000.0, size: 0.04, risk: 20.0, side: Side("FLAT"), wantStop: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stop := StopForRisk(tt.entry, tt.size, tt.risk, tt.side)
+			if math.Abs(stop-tt.wantStop) > 1e-9 {
+				t.Fatalf("StopForRisk() = %v, want %v", stop, tt.wantStop)
+			}
+			if tt.wantStop == 0 {
+				return
+			}
+
+			if err := ValidateStopLoss(tt.side, tt.entry, stop, DefaultPriceEpsilon); err != nil {
+				t.Errorf("stop %v is on the wrong side: %v", stop, err)
+			}
+			loss := tt.size * math.Abs(tt.entry-stop)
+			if math.Abs(loss-tt.risk) > 1e-9 {
+				t.Errorf("implied loss = %v, want %v", loss, tt.risk)
+			}
+		})
+	}
+}
+
+func TestMinBalanceForSetup(t *testing.T) {
+	tests := []struct {
+		name        string
+		entry       float64
+		stopLoss    float64
+		side        Side
+		riskPercent float64
+		minNotional float64
+		maxLeverage int
+		want        float64
+	}{
+		// 2% risk over a $500 stop on 45000: notional = 1.8 * balance
+		{name: "LONG bounded by $100 min notional", entry: 45000.0, stopLoss: 44500.0, side: SideLong, riskPercent: 2.0, minNotional: 100.0, maxLeverage: 125, want: 100.0 / 1.8},
+		{name: "SHORT bounded by $5 min notional", entry: 3000.0, stopLoss: 3030.0, side: SideShort, riskPercent: 1.0, minNotional: 5.0, maxLeverage: 125, want: 5.0},
+		{name: "Leverage over cap at any balance", entry: 45000.0, stopLoss: 44990.0, side: SideLong, riskPercent: 2.0, minNotional: 100.0, maxLeverage: 20, want: math.Inf(1)},
+		{name: "Uncapped leverage", entry: 45000.0, stopLoss: 44990.0, side: SideLong, riskPercent: 2.0, minNotional: 90.0, maxLeverage: 0, want: 1.0},
+		{name: "No min notional", entry: 45000.0, stopLoss: 44500.0, side: SideLong, riskPercent: 2.0, minNotional: 0, maxLeverage: 125, want: 0},
+		{name: "Invalid: stop on wrong side", entry: 45000.0, stopLoss: 45500.0, side: SideLong, riskPercent: 2.0, minNotional: 100.0, maxLeverage: 125, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MinBalanceForSetup(tt.entry, tt.stopLoss, tt.side, tt.riskPercent, tt.minNotional, tt.maxLeverage)
+			if math.IsInf(tt.want, 1) {
+				if !math.IsInf(got, 1) {
+					t.Errorf("MinBalanceForSetup() = %v, want +Inf", got)
+				}
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("MinBalanceForSetup() = %v, want %v", got, tt.want)
+			}
+			if tt.want == 0 {
+				return
+			}
+
+			// At exactly that balance, the risk-based notional meets the minimum
+			size, err := SizeForMaxLoss(got*tt.riskPercent/100, tt.entry, tt.stopLoss, tt.side)
+			if err != nil {
+				t.Fatalf("SizeForMaxLoss() error = %v", err)
+			}
+			if notional := size * tt.entry; math.Abs(notional-tt.minNotional) > 1e-6 {
+				t.Errorf("notional at min balance = %v, want %v", notional, tt.minNotional)
+			}
+		})
+	}
+}
+
+func TestSizeFromMarginPercent(t *testing.T) {
+	tests := []struct {
+		name          string
+		balance       float64
+		marginPercent float64
+		leverage      int
+		price         float64
+		want          float64
+	}{
+		{name: "10% of 1000 at 10x BTC", balance: 1000, marginPercent: 10, leverage: 10, price: 50000, want: 0.02},
+		{name: "25% of 2000 at 3x ETH", balance: 2000, marginPercent: 25, leverage: 3, price: 3000, want: 0.5},
+		{name: "All margin at 1x", balance: 1000, marginPercent: 100, leverage: 1, price: 100, want: 10},
+		{name: "Invalid: above 100%", balance: 1000, marginPercent: 150, leverage: 10, price: 50000, want: 0},
+		{name: "Invalid: zero leverage", balance: 1000, marginPercent: 10, leverage: 0, price: 50000, want: 0},
+		{name: "Invalid: zero price", balance: 1000, marginPercent: 10, leverage: 10, price: 0, want: 0},
+		{name: "Invalid: negative balance", balance: -1000, marginPercent: 10, leverage: 10, price: 50000, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SizeFromMarginPercent(tt.balance, tt.marginPercent, tt.leverage, tt.price)
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Fatalf("SizeFromMarginPercent() = %v, want %v", got, tt.want)
+			}
+			if tt.want == 0 {
+				return
+			}
+			// The margin the size ties up is the requested share of balance
+			margin := got * tt.price / float64(tt.leverage)
+			if math.Abs(margin-tt.balance*tt.marginPercent/100) > 1e-9 {
+				t.Errorf("margin = %v, want %v%% of %v", margin, tt.marginPercent, tt.balance)
+			}
+		})
+	}
+}
+
+func TestSizeForDelta(t *testing.T) {
+	tests := []struct {
+		name       string
+		delta      float64
+		multiplier float64
+		want       float64
+	}{
+		{name: "Short delta hedged long", delta: -0.7, multiplier: 1, want: 0.7},
+		{name: "Long delta hedged short", delta: 2.5, multiplier: 1, want: -2.5},
+		{name: "Contract multiplier 0.1", delta: -0.7, multiplier: 0.1, want: 7.0},
+		{name: "Flat book", delta: 0, multiplier: 1, want: 0},
+		{name: "Invalid: zero multiplier", delta: -0.7, multiplier: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SizeForDelta(tt.delta, tt.multiplier)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("SizeForDelta(%v, %v) = %v, want %v", tt.delta, tt.multiplier, got, tt.want)
+			}
+			// The hedge must net the book to zero delta
+			if tt.multiplier > 0 && math.Abs(tt.delta+got*tt.multiplier) > 1e-9 {
+				t.Errorf("net delta = %v, want 0", tt.delta+got*tt.multiplier)
+			}
+		})
+	}
+}
+
+func TestEffectiveMaxLeverage(t *testing.T) {
+	tests := []struct {
+		name        string
+		paramsMax   int
+		strategyCap int
+		want        int
+	}{
+		{name: "Params above strategy cap", paramsMax: 125, strategyCap: 20, want: 20},
+		{name: "Params below strategy cap", paramsMax: 10, strategyCap: 125, want: 10},
+		{name: "Equal", paramsMax: 50, strategyCap: 50, want: 50},
+		{name: "Params unset", paramsMax: 0, strategyCap: 125, want: 125},
+		{name: "Strategy uncapped", paramsMax: 75, strategyCap: 0, want: 75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EffectiveMaxLeverage(tt.paramsMax, tt.strategyCap); got != tt.want {
+				t.Errorf("EffectiveMaxLeverage(%d, %d) = %d, want %d", tt.paramsMax, tt.strategyCap, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommendLeverage(t *testing.T) {
+	tests := []struct {
+		name        string
+		atrPercent  float64
+		maxLeverage int
+		want        int
+	}{
+		{name: "Very low ATR hits the cap", atrPercent: 0.05, maxLeverage: 125, want: 125},
+		{name: "Low ATR", atrPercent: 0.5, maxLeverage: 125, want: 20},
+		{name: "Moderate ATR", atrPercent: 2.0, maxLeverage: 125, want: 5},
+		{name: "Rounds down", atrPercent: 3.0, maxLeverage: 125, want: 3},
+		{name: "High ATR floors at 1x", atrPercent: 25.0, maxLeverage: 125, want: 1},
+		{name: "Uncapped", atrPercent: 0.05, maxLeverage: 0, want: 200},
+		{name: "Invalid: zero ATR", atrPercent: 0, maxLeverage: 125, want: 0},
+		{name: "Invalid: NaN ATR", atrPercent: math.NaN(), maxLeverage: 125, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RecommendLeverage(tt.atrPercent, tt.maxLeverage); got != tt.want {
+				t.Errorf("RecommendLeverage(%v, %d) = %d, want %d", tt.atrPercent, tt.maxLeverage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommendLeverage_DecreasesWithVolatility(t *testing.T) {
+	prev := RecommendLeverage(0.1, 125)
+	for _, atr := range []float64{0.25, 0.5, 1, 2, 4, 8, 16} {
+		got := RecommendLeverage(atr, 125)
+		if got > prev {
+			t.Errorf("RecommendLeverage(%v) = %d, above %d at lower ATR", atr, got, prev)
+		}
+		prev = got
+	}
+	if low, high := RecommendLeverage(0.5, 125), RecommendLeverage(5, 125); low <= high {
+		t.Errorf("RecommendLeverage low ATR = %d, high ATR = %d, want strictly lower at high ATR", low, high)
+	}
+}
+
+func TestSnapLeverage(t *testing.T) {
+	brackets := []int{1, 2, 3, 5, 10}
+
+	tests := []struct {
+		name        string
+		leverage    int
+		allowed     []int
+		maxLeverage int
+		want        int
+		wantErr     bool
+	}{
+		{name: "Required 4x snaps up to 5x", leverage: 4, allowed: brackets, maxLeverage: 125, want: 5},
+		{name: "Exact bracket kept", leverage: 3, allowed: brackets, maxLeverage: 125, want: 3},
+		{name: "Unsorted brackets", leverage: 4, allowed: []int{10, 5, 1}, maxLeverage: 125, want: 5},
+		{name: "Snap capped at max", leverage: 6, allowed: brackets, maxLeverage: 8, want: 5},
+		{name: "Above every bracket uses highest", leverage: 15, allowed: brackets, maxLeverage: 125, want: 10},
+		{name: "Zero max means uncapped", leverage: 6, allowed: brackets, maxLeverage: 0, want: 10},
+		{name: "No brackets keeps leverage", leverage: 4, allowed: nil, maxLeverage: 125, want: 4},
+		{name: "Invalid: no bracket within max", leverage: 4, allowed: []int{5, 10}, maxLeverage: 3, wantErr: true},
+		{name: "Invalid: non-positive bracket", leverage: 4, allowed: []int{0, 5}, maxLeverage: 125, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SnapLeverage(tt.leverage, tt.allowed, tt.maxLeverage)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SnapLeverage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("SnapLeverage() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaledSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		balance       float64
+		riskPercent   float64
+		entry         float64
+		stopLoss      float64
+		priceDecimals int
+		sizeDecimals  int
+		wantSize      float64
+		wantNotional  float64
+		wantErr       bool
+	}{
+		{
+			name:          "SHIB-style LONG with tiny stop distance",
+			balance:       1000.0,
+			riskPercent:   1.0,
+			entry:         0.00001234,
+			stopLoss:      0.00001230,
+			priceDecimals: 8,
+			sizeDecimals:  0,
+			wantSize:      250000000,
+			wantNotional:  3085.0, // 250,000,000 * 0.00001234
+		},
+		{
+			name:          "SHIB-style SHORT",
+			balance:       500.0,
+			riskPercent:   0.3,
+			entry:         0.00001234,
+			stopLoss:      0.00001237,
+			priceDecimals: 8,
+			sizeDecimals:  0,
+			wantSize:      50000000,
+			wantNotional:  617.0,
+		},
+		{
+			name:          "Size rounded down to size decimals",
+			balance:       1000.0,
+			riskPercent:   2.0,
+			entry:         45000.0,
+			stopLoss:      44700.0,
+			priceDecimals: 2,
+			sizeDecimals:  3,
+			wantSize:      0.066, // 20 / 300 = 0.0666...
+			wantNotional:  2970.0,
+		},
+		{
+			name:          "Invalid: stop equals entry after quantizing",
+			balance:       1000.0,
+			riskPercent:   1.0,
+			entry:         0.00001234,
+			stopLoss:      0.000012341,
+			priceDecimals: 8,
+			wantErr:       true,
+		},
+		{
+			// Risk of 0.00000002 buys half a token at this stop distance
+			name:          "Invalid: size rounds below one unit",
+			balance:       0.000002,
+			riskPercent:   1.0,
+			entry:         0.00001234,
+			stopLoss:      0.00001230,
+			priceDecimals: 8,
+			sizeDecimals:  0,
+			wantErr:       true,
+		},
+		{
+			name:          "Invalid: too many decimals",
+			balance:       1000.0,
+			riskPercent:   1.0,
+			entry:         45000.0,
+			stopLoss:      44500.0,
+			priceDecimals: 15,
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, notional, err := ScaledSize(tt.balance, tt.riskPercent, tt.entry, tt.stopLoss, tt.priceDecimals, tt.sizeDecimals)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("ScaledSize() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ScaledSize() error = %v", err)
+			}
+
+			if size != tt.wantSize {
+				t.Errorf("size = %v, want %v", size, tt.wantSize)
+			}
+			if math.Abs(notional-tt.wantNotional) > 1e-9 {
+				t.Errorf("notional = %.12f, want %.12f", notional, tt.wantNotional)
+			}
+			// Notional must stay consistent with the returned size
+			if math.Abs(notional-size*tt.entry)/notional > 1e-12 {
+				t.Errorf("notional %.12f drifts from size*entry %.12f", notional, size*tt.entry)
+			}
+		})
+	}
+}
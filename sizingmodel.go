@@ -0,0 +1,33 @@
+package strategy
+
+import "fmt"
+
+// SizingModel computes a position size from the planning inputs, so
+// strategies can swap fixed-fractional, fixed-dollar, Kelly or
+// volatility-target sizing without changing their TP/SL logic
+type SizingModel interface {
+	Size(params PositionParams) (float64, error)
+}
+
+// FixedFractional sizes so the stop-out loss is params.RiskPercent of the
+// account balance
+type FixedFractional struct{}
+
+// Size returns (balance * risk%) / stop distance
+func (FixedFractional) Size(params PositionParams) (float64, error) {
+	if params.AccountBalance <= 0 || params.RiskPercent <= 0 {
+		return 0, fmt.Errorf("account balance and risk percent must be positive")
+	}
+	return SizeForMaxLoss(RiskAmount(params.AccountBalance, params.RiskPercent), params.EntryPrice, params.StopLoss, params.Side)
+}
+
+// FixedDollar sizes so the stop-out loss is Amount in quote currency,
+// whatever the balance or params.RiskPercent
+type FixedDollar struct {
+	Amount float64
+}
+
+// Size returns Amount / stop distance
+func (m FixedDollar) Size(params PositionParams) (float64, error) {
+	return SizeForMaxLoss(m.Amount, params.EntryPrice, params.StopLoss, params.Side)
+}
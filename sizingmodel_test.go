@@ -0,0 +1,46 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSizingModels(t *testing.T) {
+	long := PositionParams{
+		Side:           SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44500.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+	}
+	noRisk := long
+	noRisk.RiskPercent = 0
+	wrongSide := long
+	wrongSide.StopLoss = 45500.0
+
+	tests := []struct {
+		name     string
+		model    SizingModel
+		params   PositionParams
+		wantSize float64
+		wantErr  bool
+	}{
+		{name: "Fixed-fractional 2% of $1000", model: FixedFractional{}, params: long, wantSize: 0.04},
+		{name: "Fixed-dollar ignores risk percent", model: FixedDollar{Amount: 50}, params: noRisk, wantSize: 0.1},
+		{name: "Invalid: fixed-fractional without risk", model: FixedFractional{}, params: noRisk, wantErr: true},
+		{name: "Invalid: fixed-dollar zero amount", model: FixedDollar{}, params: long, wantErr: true},
+		{name: "Invalid: stop on wrong side", model: FixedDollar{Amount: 50}, params: wrongSide, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, err := tt.model.Size(tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Size() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && math.Abs(size-tt.wantSize) > 1e-9 {
+				t.Errorf("Size() = %v, want %v", size, tt.wantSize)
+			}
+		})
+	}
+}
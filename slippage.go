@@ -0,0 +1,74 @@
+package strategy
+
+import "math"
+
+// exitFill returns the price an exit triggered at price actually fills at,
+// slippageBps worse for the position: lower when closing a LONG (selling),
+// higher when closing a SHORT (buying)
+func exitFill(side Side, price, slippageBps float64) float64 {
+	if side == SideShort {
+		return price * (1 + slippageBps/10000)
+	}
+	return price * (1 - slippageBps/10000)
+}
+
+// GrossPnLAtStop returns the PnL (negative) of the whole plan exiting at its
+// stop with stopSlippageBps adverse slippage, before fees (see
+// NetPnLAfterFees). Stops are taker orders that usually fill worse than the
+// trigger. Returns 0 when the plan has no stop.
+func GrossPnLAtStop(plan *PositionPlan, stopSlippageBps float64) float64 {
+	if plan == nil || plan.StopLoss == nil {
+		return 0
+	}
+	return RealizedPnL(plan, exitFill(plan.Side, plan.StopLoss.Price, stopSlippageBps))
+}
+
+// GrossPnLAtTP returns the PnL of the whole plan exiting at its first TP with
+// tpSlippageBps adverse slippage (often 0 for resting maker limits), before
+// fees. Returns 0 when the plan has no TP.
+func GrossPnLAtTP(plan *PositionPlan, tpSlippageBps float64) float64 {
+	if plan == nil || len(plan.TakeProfits) == 0 || plan.TakeProfits[0] == nil {
+		return 0
+	}
+	return RealizedPnL(plan, exitFill(plan.Side, plan.TakeProfits[0].Price, tpSlippageBps))
+}
+
+// BreakEvenStop returns the stop trigger price that nets zero gross PnL once
+// stopSlippageBps of slippage is paid: slightly above entry for LONG and
+// below it for SHORT. The wider the expected slippage, the further the
+// break-even stop sits from entry.
+func BreakEvenStop(plan *PositionPlan, stopSlippageBps float64) float64 {
+	if plan == nil {
+		return 0
+	}
+	if plan.Side == SideShort {
+		return plan.EntryPrice / (1 + stopSlippageBps/10000)
+	}
+	return plan.EntryPrice / (1 - stopSlippageBps/10000)
+}
+
+// BreakEvenWinRate returns the win rate (0-1) at which the plan's expectancy
+// is zero before fees, given separate slippage on stop and TP exits:
+// loss / (win + loss).
+// Returns 0 when the plan lacks a stop or TP or the TP doesn't profit.
+func BreakEvenWinRate(plan *PositionPlan, stopSlippageBps, tpSlippageBps float64) float64 {
+	win := GrossPnLAtTP(plan, tpSlippageBps)
+	loss := math.Abs(GrossPnLAtStop(plan, stopSlippageBps))
+	if win <= 0 || loss == 0 {
+		return 0
+	}
+	return loss / (win + loss)
+}
+
+// WorstCaseLoss returns the loss (positive) if the plan is stopped out with
+// slippageBps of adverse slippage and feeBps charged on both legs, the
+// downside to report next to the idealized RiskAmount. Returns 0 when the
+// plan has no stop.
+func WorstCaseLoss(plan *PositionPlan, slippageBps, feeBps float64) float64 {
+	if plan == nil || plan.StopLoss == nil {
+		return 0
+	}
+	fill := exitFill(plan.Side, plan.StopLoss.Price, slippageBps)
+	fees := RoundTripFees(plan.Size, plan.EntryPrice, fill, feeBps/10000, feeBps/10000)
+	return -RealizedPnL(plan, fill) + fees
+}
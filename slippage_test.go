@@ -0,0 +1,131 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGrossPnL(t *testing.T) {
+	// testPlan: 0.04 BTC, entry 45000, stop 44500, TP 46000
+	tests := []struct {
+		name            string
+		stopSlippageBps float64
+		tpSlippageBps   float64
+		wantStop        float64
+		wantTP          float64
+	}{
+		{name: "No slippage", wantStop: -20.0, wantTP: 40.0},
+		{name: "Taker stop, maker TP", stopSlippageBps: 10, tpSlippageBps: 0, wantStop: -20.0 - 0.04*44.5, wantTP: 40.0},
+		{name: "Both legs slip", stopSlippageBps: 10, tpSlippageBps: 2, wantStop: -20.0 - 0.04*44.5, wantTP: 40.0 - 0.04*9.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GrossPnLAtStop(testPlan(), tt.stopSlippageBps); math.Abs(got-tt.wantStop) > 1e-9 {
+				t.Errorf("GrossPnLAtStop() = %.4f, want %.4f", got, tt.wantStop)
+			}
+			if got := GrossPnLAtTP(testPlan(), tt.tpSlippageBps); math.Abs(got-tt.wantTP) > 1e-9 {
+				t.Errorf("GrossPnLAtTP() = %.4f, want %.4f", got, tt.wantTP)
+			}
+		})
+	}
+}
+
+func TestGrossPnL_Short(t *testing.T) {
+	plan := testPlan()
+	plan.Side = SideShort
+	plan.StopLoss.Price = 45500.0
+	plan.TakeProfits[0].Price = 44000.0
+
+	// Buying back fills above the trigger
+	if got, want := GrossPnLAtStop(plan, 10), -20.0-0.04*45.5; math.Abs(got-want) > 1e-9 {
+		t.Errorf("GrossPnLAtStop() = %.4f, want %.4f", got, want)
+	}
+	if got, want := GrossPnLAtTP(plan, 0), 40.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("GrossPnLAtTP() = %.4f, want %.4f", got, want)
+	}
+}
+
+func TestBreakEvenStop(t *testing.T) {
+	plan := testPlan()
+
+	if got := BreakEvenStop(plan, 0); got != plan.EntryPrice {
+		t.Errorf("BreakEvenStop(0) = %.4f, want entry %.4f", got, plan.EntryPrice)
+	}
+
+	// The break-even stop moves further above entry as stop slippage grows
+	prev := plan.EntryPrice
+	for _, bps := range []float64{5, 10, 25} {
+		be := BreakEvenStop(plan, bps)
+		if be <= prev {
+			t.Errorf("BreakEvenStop(%v) = %.4f, want above %.4f", bps, be, prev)
+		}
+		prev = be
+
+		// Stopped out at the break-even trigger nets zero
+		plan.StopLoss.Price = be
+		if pnl := GrossPnLAtStop(plan, bps); math.Abs(pnl) > 1e-9 {
+			t.Errorf("GrossPnLAtStop() at break-even stop = %.12f, want 0", pnl)
+		}
+	}
+
+	short := testPlan()
+	short.Side = SideShort
+	if got := BreakEvenStop(short, 10); got >= short.EntryPrice {
+		t.Errorf("SHORT BreakEvenStop() = %.4f, want below entry", got)
+	}
+}
+
+func TestBreakEvenWinRate(t *testing.T) {
+	// Without slippage: 20 / (40 + 20) = 1/3
+	base := BreakEvenWinRate(testPlan(), 0, 0)
+	if math.Abs(base-1.0/3) > 1e-9 {
+		t.Errorf("BreakEvenWinRate() = %.6f, want 0.333333", base)
+	}
+	if worse := BreakEvenWinRate(testPlan(), 20, 0); worse <= base {
+		t.Errorf("BreakEvenWinRate() with stop slippage = %.6f, want above %.6f", worse, base)
+	}
+
+	noStop := testPlan()
+	noStop.StopLoss = nil
+	if got := BreakEvenWinRate(noStop, 0, 0); got != 0 {
+		t.Errorf("BreakEvenWinRate() without stop = %v, want 0", got)
+	}
+}
+
+func TestWorstCaseLoss(t *testing.T) {
+	short := testPlan()
+	short.Side = SideShort
+	short.StopLoss.Price = 45500.0
+
+	noStop := testPlan()
+	noStop.StopLoss = nil
+
+	tests := []struct {
+		name        string
+		plan        *PositionPlan
+		slippageBps float64
+		feeBps      float64
+		want        float64
+	}{
+		{name: "Idealized matches RiskAmount", plan: testPlan(), want: 20.0},
+		// Fills at 44455.5; fees 0.04 * (45000 + 44455.5) * 0.0005
+		{name: "LONG 10bps slip, 5bps fees", plan: testPlan(), slippageBps: 10, feeBps: 5, want: 0.04*544.5 + 0.04*89455.5*0.0005},
+		{name: "LONG fees only", plan: testPlan(), feeBps: 5, want: 20.0 + 0.04*89500*0.0005},
+		// Buys back at 45545.5
+		{name: "SHORT 10bps slip, 5bps fees", plan: short, slippageBps: 10, feeBps: 5, want: 0.04*545.5 + 0.04*90545.5*0.0005},
+		{name: "No stop", plan: noStop, slippageBps: 10, feeBps: 5, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WorstCaseLoss(tt.plan, tt.slippageBps, tt.feeBps)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("WorstCaseLoss() = %.6f, want %.6f", got, tt.want)
+			}
+			if tt.want > 0 && tt.slippageBps+tt.feeBps > 0 && got <= tt.plan.RiskAmount {
+				t.Errorf("WorstCaseLoss() = %.6f, want above RiskAmount %.2f", got, tt.plan.RiskAmount)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package strategy
+
+import (
+	"sync"
+)
+
+// StatefulStrategy is an embeddable base for strategies that keep per-symbol
+// state between callbacks (trailing stops, pyramiding, ...).
+// All access goes through a mutex, so callbacks for different symbols may run
+// concurrently from multiple goroutines. The zero value is ready to use.
+type StatefulStrategy[T any] struct {
+	mu    sync.Mutex
+	state map[string]*T
+}
+
+// getOrInit returns the state for symbol, creating it with init when absent.
+// Callers must hold s.mu.
+func (s *StatefulStrategy[T]) getOrInit(symbol string, init func() T) *T {
+	if s.state == nil {
+		s.state = make(map[string]*T)
+	}
+	st, ok := s.state[symbol]
+	if !ok {
+		v := init()
+		st = &v
+		s.state[symbol] = st
+	}
+	return st
+}
+
+// GetOrInit returns a copy of the state for symbol, storing init() first if
+// the symbol has no state yet
+func (s *StatefulStrategy[T]) GetOrInit(symbol string, init func() T) T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.getOrInit(symbol, init)
+}
+
+// Lookup returns a copy of the state for symbol and whether it exists
+func (s *StatefulStrategy[T]) Lookup(symbol string) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.state[symbol]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return *st, true
+}
+
+// Set replaces the state for symbol
+func (s *StatefulStrategy[T]) Set(symbol string, value T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		s.state = make(map[string]*T)
+	}
+	s.state[symbol] = &value
+}
+
+// Update runs fn with exclusive access to the state for symbol, initializing
+// it with init when absent. fn must not retain the pointer after returning.
+func (s *StatefulStrategy[T]) Update(symbol string, init func() T, fn func(state *T)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.getOrInit(symbol, init))
+}
+
+// Clear removes the state for symbol (e.g. once its position is closed)
+func (s *StatefulStrategy[T]) Clear(symbol string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.state, symbol)
+}
+
+// ClearAll removes the state for every symbol (e.g. at the start of a new session)
+func (s *StatefulStrategy[T]) ClearAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = nil
+}
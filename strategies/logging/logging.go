@@ -0,0 +1,149 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agatticelli/strategy-go"
+)
+
+// Event names passed to the Logger
+const (
+	EventCalculatePosition = "calculate_position"
+	EventPositionOpened    = "position_opened"
+	EventPriceAction       = "price_action"
+	EventShouldClose       = "should_close"
+)
+
+// Logger receives one structured event per strategy decision. Adapt it to
+// any structured logger, e.g. slog via a loop over fields.
+type Logger func(event string, fields map[string]any)
+
+// LoggingStrategy wraps another strategy and reports its decisions to a
+// Logger without changing them: every result and error from the wrapped
+// strategy is returned as is. Price updates and close checks run on every
+// tick, so they are only logged when they act: a non-None action, an
+// error, or a close.
+type LoggingStrategy struct {
+	inner strategy.Strategy
+	log   Logger
+}
+
+// New wraps inner, sending events to log. A nil log discards them.
+func New(inner strategy.Strategy, log Logger) *LoggingStrategy {
+	if log == nil {
+		log = func(string, map[string]any) {}
+	}
+	return &LoggingStrategy{
+		inner: inner,
+		log:   log,
+	}
+}
+
+// Name returns the wrapped strategy name
+func (s *LoggingStrategy) Name() string {
+	return s.inner.Name()
+}
+
+// Description returns a human-readable description
+func (s *LoggingStrategy) Description() string {
+	return fmt.Sprintf("%s (logged)", s.inner.Description())
+}
+
+// ValidateParams validates the wrapped strategy's params
+func (s *LoggingStrategy) ValidateParams(params strategy.StrategyParams) error {
+	return s.inner.ValidateParams(params)
+}
+
+// Parameters returns the wrapped strategy's parameters
+func (s *LoggingStrategy) Parameters() []strategy.ParamSpec {
+	return strategy.Parameters(s.inner)
+}
+
+// CalculatePosition delegates to the wrapped strategy and logs the inputs
+// with either the resulting plan or the error
+func (s *LoggingStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	plan, err := s.inner.CalculatePosition(ctx, params)
+
+	fields := map[string]any{
+		"strategy":   s.inner.Name(),
+		"symbol":     params.Symbol,
+		"side":       params.Side,
+		"entryPrice": params.EntryPrice,
+		"stopLoss":   params.StopLoss,
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	if plan != nil {
+		fields["size"] = plan.Size
+		fields["leverage"] = plan.Leverage
+		fields["riskAmount"] = plan.RiskAmount
+		fields["takeProfits"] = len(plan.TakeProfits)
+	}
+	s.log(EventCalculatePosition, fields)
+
+	return plan, err
+}
+
+// OnPositionOpened forwards to the wrapped strategy and logs the position
+func (s *LoggingStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	err := s.inner.OnPositionOpened(ctx, position)
+
+	fields := positionFields(s.inner.Name(), position)
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	s.log(EventPositionOpened, fields)
+
+	return err
+}
+
+// OnPriceUpdate forwards to the wrapped strategy and logs actions other
+// than ActionTypeNone, and errors
+func (s *LoggingStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	action, err := s.inner.OnPriceUpdate(ctx, position, currentPrice)
+	if err == nil && (action == nil || action.Type == strategy.ActionTypeNone) {
+		return action, err
+	}
+
+	fields := positionFields(s.inner.Name(), position)
+	fields["currentPrice"] = currentPrice
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	if action != nil {
+		fields["action"] = action.Type
+		fields["newPrice"] = action.NewPrice
+	}
+	s.log(EventPriceAction, fields)
+
+	return action, err
+}
+
+// ShouldClose forwards to the wrapped strategy and logs when it closes
+func (s *LoggingStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	shouldClose, reason := s.inner.ShouldClose(ctx, position, currentPrice)
+	if !shouldClose {
+		return shouldClose, reason
+	}
+
+	fields := positionFields(s.inner.Name(), position)
+	fields["currentPrice"] = currentPrice
+	fields["reason"] = reason
+	s.log(EventShouldClose, fields)
+
+	return shouldClose, reason
+}
+
+// positionFields returns the fields shared by position events
+func positionFields(name string, position *strategy.Position) map[string]any {
+	fields := map[string]any{"strategy": name}
+	if position != nil {
+		fields["symbol"] = position.Symbol
+		fields["side"] = position.Side
+		fields["size"] = position.Size
+		fields["entryPrice"] = position.EntryPrice
+	}
+	return fields
+}
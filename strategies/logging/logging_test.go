@@ -0,0 +1,177 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/strategy-go"
+	"github.com/agatticelli/strategy-go/strategies/riskratio"
+	"github.com/agatticelli/trading-common-types"
+)
+
+// event is one captured Logger call
+type event struct {
+	name   string
+	fields map[string]any
+}
+
+// capture returns a Logger appending to events
+func capture(events *[]event) Logger {
+	return func(name string, fields map[string]any) {
+		*events = append(*events, event{name: name, fields: fields})
+	}
+}
+
+// stubStrategy returns fixed price-update and close decisions
+type stubStrategy struct {
+	strategy.NoParameters
+
+	action    *strategy.StrategyAction
+	updateErr error
+	close     bool
+	reason    string
+}
+
+func (s *stubStrategy) Name() string                                        { return "stub" }
+func (s *stubStrategy) Description() string                                 { return "Test stub strategy" }
+func (s *stubStrategy) ValidateParams(params strategy.StrategyParams) error { return nil }
+
+func (s *stubStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	return nil
+}
+
+func (s *stubStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	return s.action, s.updateErr
+}
+
+func (s *stubStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	return s.close, s.reason
+}
+
+func testPosition() *strategy.Position {
+	return &strategy.Position{Symbol: "BTC-USDT", Side: types.SideLong, Size: 0.04, EntryPrice: 45000.0}
+}
+
+func TestCalculatePosition_Logged(t *testing.T) {
+	var events []event
+	inner := riskratio.New(2.0)
+	strat := New(inner, capture(&events))
+
+	params := strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           types.SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44500.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+	}
+	plan, err := strat.CalculatePosition(context.Background(), params)
+	if err != nil {
+		t.Fatalf("CalculatePosition() error = %v", err)
+	}
+	want, _ := inner.CalculatePosition(context.Background(), params)
+	if plan.Size != want.Size || plan.Leverage != want.Leverage || plan.StopLoss.Price != want.StopLoss.Price {
+		t.Errorf("plan = %+v, want the wrapped strategy's %+v", plan, want)
+	}
+
+	if len(events) != 1 || events[0].name != EventCalculatePosition {
+		t.Fatalf("events = %+v, want one %s", events, EventCalculatePosition)
+	}
+	fields := events[0].fields
+	if fields["strategy"] != "risk-ratio" || fields["symbol"] != "BTC-USDT" || fields["size"] != plan.Size || fields["riskAmount"] != 20.0 {
+		t.Errorf("fields = %v, want strategy, symbol, size and riskAmount of the plan", fields)
+	}
+	if _, ok := fields["error"]; ok {
+		t.Errorf("fields[error] = %v, want no error field on success", fields["error"])
+	}
+
+	// Errors are logged and returned unchanged
+	params.StopLoss = 45500.0
+	if _, err := strat.CalculatePosition(context.Background(), params); err == nil {
+		t.Fatal("CalculatePosition() error = nil, want error for a LONG stop above entry")
+	}
+	if len(events) != 2 || events[1].fields["error"] == nil || events[1].fields["size"] != nil {
+		t.Errorf("events[1] = %+v, want an error field and no plan fields", events[1])
+	}
+}
+
+func TestOnPriceUpdate_Logged(t *testing.T) {
+	updateErr := errors.New("feed stale")
+
+	tests := []struct {
+		name      string
+		inner     *stubStrategy
+		wantEvent bool
+	}{
+		{name: "None is not logged", inner: &stubStrategy{action: &strategy.StrategyAction{Type: types.ActionTypeNone}}},
+		{name: "Adjust SL is logged", inner: &stubStrategy{action: &strategy.StrategyAction{Type: types.ActionTypeAdjustSL, NewPrice: 44800.0}}, wantEvent: true},
+		{name: "Error is logged", inner: &stubStrategy{updateErr: updateErr}, wantEvent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []event
+			strat := New(tt.inner, capture(&events))
+
+			action, err := strat.OnPriceUpdate(context.Background(), testPosition(), 45300.0)
+			if action != tt.inner.action || !errors.Is(err, tt.inner.updateErr) {
+				t.Errorf("OnPriceUpdate() = %v, %v, want the wrapped %v, %v", action, err, tt.inner.action, tt.inner.updateErr)
+			}
+
+			if !tt.wantEvent {
+				if len(events) != 0 {
+					t.Errorf("events = %+v, want none", events)
+				}
+				return
+			}
+			if len(events) != 1 || events[0].name != EventPriceAction {
+				t.Fatalf("events = %+v, want one %s", events, EventPriceAction)
+			}
+			fields := events[0].fields
+			if fields["symbol"] != "BTC-USDT" || fields["currentPrice"] != 45300.0 {
+				t.Errorf("fields = %v, want symbol and currentPrice", fields)
+			}
+			if tt.inner.action != nil && (fields["action"] != tt.inner.action.Type || fields["newPrice"] != tt.inner.action.NewPrice) {
+				t.Errorf("fields = %v, want action %v at %v", fields, tt.inner.action.Type, tt.inner.action.NewPrice)
+			}
+			if tt.inner.updateErr != nil && fields["error"] != updateErr.Error() {
+				t.Errorf("fields[error] = %v, want %q", fields["error"], updateErr.Error())
+			}
+		})
+	}
+}
+
+func TestShouldClose_Logged(t *testing.T) {
+	var events []event
+	inner := &stubStrategy{}
+	strat := New(inner, capture(&events))
+
+	if shouldClose, _ := strat.ShouldClose(context.Background(), testPosition(), 45000.0); shouldClose || len(events) != 0 {
+		t.Errorf("ShouldClose() = %v with %d events, want false and none", shouldClose, len(events))
+	}
+
+	inner.close, inner.reason = true, "session ended"
+	shouldClose, reason := strat.ShouldClose(context.Background(), testPosition(), 45000.0)
+	if !shouldClose || reason != "session ended" {
+		t.Errorf("ShouldClose() = %v, %q, want true, %q", shouldClose, reason, "session ended")
+	}
+	if len(events) != 1 || events[0].name != EventShouldClose || events[0].fields["reason"] != "session ended" {
+		t.Errorf("events = %+v, want one %s with the reason", events, EventShouldClose)
+	}
+}
+
+func TestNilLogger(t *testing.T) {
+	strat := New(&stubStrategy{close: true}, nil)
+	if shouldClose, _ := strat.ShouldClose(context.Background(), testPosition(), 45000.0); !shouldClose {
+		t.Error("ShouldClose() = false, want the wrapped true")
+	}
+	if err := strat.OnPositionOpened(context.Background(), testPosition()); err != nil {
+		t.Errorf("OnPositionOpened() error = %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package maxpositions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agatticelli/strategy-go"
+)
+
+// OpenPositionsParam is the PositionParams.Params key holding the number of
+// currently open positions (int)
+const OpenPositionsParam = "openPositions"
+
+// ErrMaxPositions is returned by CalculatePosition when the open-position cap is reached
+var ErrMaxPositions = fmt.Errorf("%w: max concurrent positions reached", strategy.ErrNoTrade)
+
+// MaxPositionsStrategy wraps another strategy and refuses to plan new
+// positions once the portfolio already holds maxPositions open positions.
+// All other callbacks are forwarded unchanged.
+type MaxPositionsStrategy struct {
+	inner        strategy.Strategy
+	maxPositions int
+}
+
+// New wraps inner with a max-concurrent-positions limit
+func New(inner strategy.Strategy, maxPositions int) *MaxPositionsStrategy {
+	return &MaxPositionsStrategy{
+		inner:        inner,
+		maxPositions: maxPositions,
+	}
+}
+
+// Name returns the wrapped strategy name
+func (s *MaxPositionsStrategy) Name() string {
+	return s.inner.Name()
+}
+
+// Description returns a human-readable description
+func (s *MaxPositionsStrategy) Description() string {
+	return fmt.Sprintf("%s (max %d open positions)", s.inner.Description(), s.maxPositions)
+}
+
+// ValidateParams validates the openPositions param and the wrapped strategy's params
+func (s *MaxPositionsStrategy) ValidateParams(params strategy.StrategyParams) error {
+	if _, _, err := params.Int(OpenPositionsParam); err != nil {
+		return err
+	}
+	return s.inner.ValidateParams(params)
+}
+
+// Parameters returns the wrapped strategy's parameters plus openPositions
+func (s *MaxPositionsStrategy) Parameters() []strategy.ParamSpec {
+	specs := append([]strategy.ParamSpec(nil), strategy.Parameters(s.inner)...)
+	return append(specs, strategy.ParamSpec{
+		Name:        OpenPositionsParam,
+		Type:        strategy.ParamTypeInt,
+		Description: "Number of currently open positions",
+		Default:     0,
+		Min:         strategy.Bound(0),
+	})
+}
+
+// CalculatePosition errors with ErrMaxPositions when Params["openPositions"]
+// is at or above the cap. A missing openPositions param counts as zero.
+func (s *MaxPositionsStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	open, _, err := strategy.StrategyParams(params.Params).Int(OpenPositionsParam)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if open >= s.maxPositions {
+		return nil, fmt.Errorf("%w: %d open, max %d", ErrMaxPositions, open, s.maxPositions)
+	}
+
+	return s.inner.CalculatePosition(ctx, params)
+}
+
+// OnPositionOpened forwards to the wrapped strategy
+func (s *MaxPositionsStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	return s.inner.OnPositionOpened(ctx, position)
+}
+
+// OnPriceUpdate forwards to the wrapped strategy
+func (s *MaxPositionsStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	return s.inner.OnPriceUpdate(ctx, position, currentPrice)
+}
+
+// ShouldClose forwards to the wrapped strategy
+func (s *MaxPositionsStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	return s.inner.ShouldClose(ctx, position, currentPrice)
+}
@@ -0,0 +1,103 @@
+package maxpositions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/strategy-go"
+	"github.com/agatticelli/strategy-go/strategies/riskratio"
+	"github.com/agatticelli/trading-common-types"
+)
+
+func testParams(openPositions interface{}) strategy.PositionParams {
+	params := strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           types.SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44500.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+	}
+	if openPositions != nil {
+		params.Params = map[string]interface{}{OpenPositionsParam: openPositions}
+	}
+	return params
+}
+
+func TestName(t *testing.T) {
+	strat := New(riskratio.New(2.0), 3)
+	if name := strat.Name(); name != "risk-ratio" {
+		t.Errorf("Name() = %q, want %q", name, "risk-ratio")
+	}
+	want := "Fixed risk-reward ratio strategy (2.0:1) (max 3 open positions)"
+	if desc := strat.Description(); desc != want {
+		t.Errorf("Description() = %q, want %q", desc, want)
+	}
+}
+
+func TestCalculatePosition(t *testing.T) {
+	tests := []struct {
+		name          string
+		openPositions interface{}
+		wantErr       error
+	}{
+		{name: "No param counts as zero", openPositions: nil, wantErr: nil},
+		{name: "One below cap", openPositions: 2, wantErr: nil},
+		{name: "At cap", openPositions: 3, wantErr: ErrMaxPositions},
+		{name: "One above cap", openPositions: 4, wantErr: ErrMaxPositions},
+		{name: "JSON-decoded float below cap", openPositions: 2.0, wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(riskratio.New(2.0), 3)
+
+			plan, err := strat.CalculatePosition(context.Background(), testParams(tt.openPositions))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CalculatePosition() error = %v, want %v", err, tt.wantErr)
+				}
+				if plan != nil {
+					t.Error("CalculatePosition() returned a plan alongside the error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v, want nil", err)
+			}
+			if plan.StrategyName != "risk-ratio" {
+				t.Errorf("StrategyName = %q, want %q", plan.StrategyName, "risk-ratio")
+			}
+		})
+	}
+}
+
+func TestCalculatePosition_InvalidParam(t *testing.T) {
+	strat := New(riskratio.New(2.0), 3)
+
+	_, err := strat.CalculatePosition(context.Background(), testParams("two"))
+	if err == nil {
+		t.Fatal("CalculatePosition() error = nil, want error for non-integer openPositions")
+	}
+	if errors.Is(err, ErrMaxPositions) {
+		t.Errorf("CalculatePosition() error = %v, should not be ErrMaxPositions", err)
+	}
+}
+
+func TestParameters(t *testing.T) {
+	inner := riskratio.New(2.0)
+	specs := New(inner, 3).Parameters()
+	want := len(inner.Parameters()) + 1
+	if len(specs) != want {
+		t.Fatalf("len(Parameters()) = %d, want %d", len(specs), want)
+	}
+	if specs[0].Name != inner.Parameters()[0].Name {
+		t.Errorf("specs[0].Name = %q, want wrapped %q", specs[0].Name, inner.Parameters()[0].Name)
+	}
+	if last := specs[want-1]; last.Name != OpenPositionsParam || last.Type != strategy.ParamTypeInt {
+		t.Errorf("last spec = %+v, want int %q spec", last, OpenPositionsParam)
+	}
+}
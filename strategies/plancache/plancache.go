@@ -0,0 +1,147 @@
+package plancache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/strategy-go"
+)
+
+// entry is a cached plan and when it was computed
+type entry struct {
+	plan     *strategy.PositionPlan
+	cachedAt time.Time
+}
+
+// CachedStrategy wraps another strategy and memoizes CalculatePosition for
+// identical PositionParams, for scanners that re-evaluate the same setup many
+// times per second. Hits return a copy of the cached plan with a refreshed
+// timestamp; errors are never cached. It is safe for concurrent use.
+type CachedStrategy struct {
+	inner strategy.Strategy
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]entry
+	lastSweep time.Time
+}
+
+// New wraps inner with a plan cache whose entries expire after ttl. Expired
+// entries are swept on insert at most once per ttl, so keys that are never
+// looked up again (a scanner whose prices keep moving) don't pile up.
+//
+// A cache hit skips inner entirely. Stateful inner strategies lose their
+// per-call side effects on hits: trailing doesn't record the position
+// state again and symbolrisk doesn't spend budget, so only wrap strategies
+// whose CalculatePosition is a pure function of its params.
+func New(inner strategy.Strategy, ttl time.Duration) *CachedStrategy {
+	return &CachedStrategy{
+		inner:   inner,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]entry),
+	}
+}
+
+// cacheKey identifies params by value. %#v prints map keys in sorted order,
+// so equal Params maps produce equal keys.
+func cacheKey(params strategy.PositionParams) string {
+	return fmt.Sprintf("%#v", params)
+}
+
+// Name returns the wrapped strategy name
+func (s *CachedStrategy) Name() string {
+	return s.inner.Name()
+}
+
+// Description returns a human-readable description
+func (s *CachedStrategy) Description() string {
+	return fmt.Sprintf("%s (cached %s)", s.inner.Description(), s.ttl)
+}
+
+// ValidateParams validates the wrapped strategy's params
+func (s *CachedStrategy) ValidateParams(params strategy.StrategyParams) error {
+	return s.inner.ValidateParams(params)
+}
+
+// Parameters returns the wrapped strategy's parameters
+func (s *CachedStrategy) Parameters() []strategy.ParamSpec {
+	return strategy.Parameters(s.inner)
+}
+
+// CalculatePosition returns the cached plan for params if it is younger than
+// the TTL, otherwise computes, caches and returns a fresh one
+func (s *CachedStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	key := cacheKey(params)
+	now := s.now()
+
+	s.mu.Lock()
+	e, ok := s.entries[key]
+	if ok && now.Sub(e.cachedAt) >= s.ttl {
+		delete(s.entries, key)
+		ok = false
+	}
+	s.mu.Unlock()
+
+	if ok {
+		plan := strategy.ClonePlan(e.plan)
+		plan.Timestamp = now.UTC()
+		return plan, nil
+	}
+
+	plan, err := s.inner.CalculatePosition(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) >= s.ttl {
+		s.sweep(now)
+	}
+	s.entries[key] = entry{plan: strategy.ClonePlan(plan), cachedAt: now}
+	s.mu.Unlock()
+
+	return plan, nil
+}
+
+// sweep drops every entry older than the TTL. Callers must hold s.mu.
+func (s *CachedStrategy) sweep(now time.Time) {
+	for key, e := range s.entries {
+		if now.Sub(e.cachedAt) >= s.ttl {
+			delete(s.entries, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// Len returns the number of cached plans, including expired ones not yet swept
+func (s *CachedStrategy) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Purge drops every cached plan
+func (s *CachedStrategy) Purge() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = make(map[string]entry)
+}
+
+// OnPositionOpened forwards to the wrapped strategy
+func (s *CachedStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	return s.inner.OnPositionOpened(ctx, position)
+}
+
+// OnPriceUpdate forwards to the wrapped strategy
+func (s *CachedStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	return s.inner.OnPriceUpdate(ctx, position, currentPrice)
+}
+
+// ShouldClose forwards to the wrapped strategy
+func (s *CachedStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	return s.inner.ShouldClose(ctx, position, currentPrice)
+}
@@ -0,0 +1,175 @@
+package plancache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/strategy-go"
+	"github.com/agatticelli/strategy-go/strategies/riskratio"
+	"github.com/agatticelli/trading-common-types"
+)
+
+// countingStrategy counts calls that reach the wrapped risk-ratio strategy
+type countingStrategy struct {
+	*riskratio.RiskRatioStrategy
+	calls int
+}
+
+func (c *countingStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	c.calls++
+	return c.RiskRatioStrategy.CalculatePosition(ctx, params)
+}
+
+func testParams() strategy.PositionParams {
+	return strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           types.SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44500.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+		Params:         map[string]interface{}{"a": 1, "b": 2.0},
+	}
+}
+
+// newTestCache returns a cache over a counting strategy with a settable clock
+func newTestCache(ttl time.Duration) (*CachedStrategy, *countingStrategy, *time.Time) {
+	inner := &countingStrategy{RiskRatioStrategy: riskratio.New(2.0)}
+	clock := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	cache := New(inner, ttl)
+	cache.now = func() time.Time { return clock }
+	return cache, inner, &clock
+}
+
+func TestCalculatePosition_Hit(t *testing.T) {
+	cache, inner, clock := newTestCache(time.Second)
+	ctx := context.Background()
+
+	first, err := cache.CalculatePosition(ctx, testParams())
+	if err != nil {
+		t.Fatalf("CalculatePosition() error = %v", err)
+	}
+
+	*clock = clock.Add(500 * time.Millisecond)
+	second, err := cache.CalculatePosition(ctx, testParams())
+	if err != nil {
+		t.Fatalf("CalculatePosition() error = %v", err)
+	}
+
+	if inner.calls != 1 {
+		t.Errorf("inner calls = %d, want 1 (cache hit)", inner.calls)
+	}
+	if second == first {
+		t.Error("cache hit should return a copy, not the original plan")
+	}
+	if second.Size != first.Size || second.TakeProfits[0].Price != first.TakeProfits[0].Price {
+		t.Errorf("cached plan = %+v, want same figures as %+v", second, first)
+	}
+	if !second.Timestamp.Equal(*clock) {
+		t.Errorf("cached plan Timestamp = %v, want refreshed %v", second.Timestamp, *clock)
+	}
+}
+
+func TestCalculatePosition_Miss(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(p *strategy.PositionParams)
+	}{
+		{name: "Symbol", modify: func(p *strategy.PositionParams) { p.Symbol = "ETH-USDT" }},
+		{name: "Side", modify: func(p *strategy.PositionParams) { p.Side = types.SideShort; p.StopLoss = 45500.0 }},
+		{name: "EntryPrice", modify: func(p *strategy.PositionParams) { p.EntryPrice = 45001.0 }},
+		{name: "StopLoss", modify: func(p *strategy.PositionParams) { p.StopLoss = 44499.0 }},
+		{name: "AccountBalance", modify: func(p *strategy.PositionParams) { p.AccountBalance = 1001.0 }},
+		{name: "RiskPercent", modify: func(p *strategy.PositionParams) { p.RiskPercent = 1.5 }},
+		{name: "MaxLeverage", modify: func(p *strategy.PositionParams) { p.MaxLeverage = 50 }},
+		{name: "Params value", modify: func(p *strategy.PositionParams) { p.Params["b"] = 3.0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, inner, _ := newTestCache(time.Minute)
+			ctx := context.Background()
+
+			if _, err := cache.CalculatePosition(ctx, testParams()); err != nil {
+				t.Fatalf("CalculatePosition() error = %v", err)
+			}
+			params := testParams()
+			tt.modify(&params)
+			if _, err := cache.CalculatePosition(ctx, params); err != nil {
+				t.Fatalf("CalculatePosition() modified error = %v", err)
+			}
+
+			if inner.calls != 2 {
+				t.Errorf("inner calls = %d, want 2 (cache miss)", inner.calls)
+			}
+		})
+	}
+}
+
+func TestCalculatePosition_Expired(t *testing.T) {
+	cache, inner, clock := newTestCache(time.Second)
+	ctx := context.Background()
+
+	if _, err := cache.CalculatePosition(ctx, testParams()); err != nil {
+		t.Fatalf("CalculatePosition() error = %v", err)
+	}
+	*clock = clock.Add(time.Second)
+	if _, err := cache.CalculatePosition(ctx, testParams()); err != nil {
+		t.Fatalf("CalculatePosition() error = %v", err)
+	}
+
+	if inner.calls != 2 {
+		t.Errorf("inner calls = %d, want 2 after TTL expiry", inner.calls)
+	}
+}
+
+func TestCalculatePosition_EvictsExpiredKeys(t *testing.T) {
+	cache, _, clock := newTestCache(time.Second)
+	ctx := context.Background()
+
+	// A scanner whose entry moves every tick never repeats a key
+	params := testParams()
+	for i := 0; i < 10; i++ {
+		params.EntryPrice = 45000.0 + float64(i)
+		if _, err := cache.CalculatePosition(ctx, params); err != nil {
+			t.Fatalf("CalculatePosition() error = %v", err)
+		}
+		*clock = clock.Add(250 * time.Millisecond)
+	}
+
+	// Keys older than the TTL are swept; only the last second's survive
+	if got := cache.Len(); got > 5 {
+		t.Errorf("Len() = %d after 10 one-off keys, want expired keys evicted", got)
+	}
+
+	*clock = clock.Add(time.Second)
+	params.EntryPrice = 46000.0
+	if _, err := cache.CalculatePosition(ctx, params); err != nil {
+		t.Fatalf("CalculatePosition() error = %v", err)
+	}
+	if got := cache.Len(); got != 1 {
+		t.Errorf("Len() = %d, want 1 once every older key has expired", got)
+	}
+}
+
+func TestCalculatePosition_ErrorsNotCached(t *testing.T) {
+	cache, inner, _ := newTestCache(time.Minute)
+	ctx := context.Background()
+
+	params := testParams()
+	params.StopLoss = 45500.0 // Wrong side for LONG
+	for i := 0; i < 2; i++ {
+		if _, err := cache.CalculatePosition(ctx, params); err == nil {
+			t.Fatal("CalculatePosition() error = nil, want validation error")
+		}
+	}
+
+	if inner.calls != 2 {
+		t.Errorf("inner calls = %d, want 2 (errors are not cached)", inner.calls)
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", cache.Len())
+	}
+}
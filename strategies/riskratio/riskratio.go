@@ -3,27 +3,162 @@ package riskratio
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/agatticelli/calculator-go"
 	"github.com/agatticelli/strategy-go"
 )
 
+// Optional PositionParams.Params keys
+const (
+	// PriceDecimalsParam switches sizing to scaled-integer math with prices
+	// quantized to this many decimals (int). Use it for very small-priced
+	// assets where float rounding makes the notional drift.
+	PriceDecimalsParam = "priceDecimals"
+
+	// SizeDecimalsParam is the size precision used with PriceDecimalsParam (int, default 8)
+	SizeDecimalsParam = "sizeDecimals"
+
+	// MarginBufferPercentParam keeps this percent of the balance as a cash
+	// cushion (float, 0-100). Leverage is computed against the remaining
+	// balance, so it comes out slightly higher than with no buffer.
+	MarginBufferPercentParam = "marginBufferPercent"
+
+	// MaxNotionalMultipleParam caps notional at this multiple of the balance
+	// (float, > 0). When the risk-based size exceeds it, the size is shrunk to
+	// fit and the plan's RiskAmount/RiskPercent report the reduced risk.
+	MaxNotionalMultipleParam = "maxNotionalMultiple"
+
+	// AllowedLeveragesParam restricts leverage to the exchange's discrete
+	// settings ([]int); the computed leverage snaps up to the nearest one
+	AllowedLeveragesParam = "allowedLeverages"
+
+	// CurrentPriceParam is the market price for limit entries (float). When
+	// set, an entry on the wrong side of it is rejected.
+	CurrentPriceParam = "currentPrice"
+
+	// TargetPriceParam is a fixed take-profit price (float), e.g. a known
+	// resistance level. When set it replaces the RR-based TP; the resulting
+	// ratio can be read back with strategy.ImpliedRR.
+	TargetPriceParam = strategy.TargetPriceParam
+
+	// TPTicksParam places the take profit this many ticks from entry (int,
+	// > 0) instead of at the RR multiple; requires TickSizeParam
+	TPTicksParam = "tpTicks"
+
+	// TickSizeParam is the instrument's minimum price increment (float, > 0)
+	TickSizeParam = "tickSize"
+
+	// ExistingSizeParam is the size of an open same-side position in the
+	// symbol (float, >= 0); requires ExistingStopParam. Its open risk, the
+	// loss from entry down (or up) to its stop, counts against the risk cap,
+	// so the new size shrinks until existing + new risk fits.
+	ExistingSizeParam = "existingSize"
+
+	// ExistingStopParam is the stop price of the ExistingSizeParam position (float)
+	ExistingStopParam = "existingStop"
+
+	// ClampPolicyParam selects what happens when the max-leverage clamp
+	// leaves the full size unaffordable (string, a ClampPolicy; default ClampError)
+	ClampPolicyParam = "clampPolicy"
+)
+
+// ClampPolicy is how a plan is resolved when leverage is clamped below what
+// the risk-based size requires
+type ClampPolicy string
+
+const (
+	ClampError  ClampPolicy = "error"  // Reject the plan
+	ClampShrink ClampPolicy = "shrink" // Shrink size to what max leverage can fund, keeping the stop (less risk)
+	ClampKeep   ClampPolicy = "keep"   // Keep the full size; the caller must supply the extra margin
+)
+
+// defaultSizeDecimals is the size precision for scaled sizing when none is given
+const defaultSizeDecimals = 8
+
+// riskTolerance is the relative float noise within which a size is treated
+// as risking exactly the requested amount
+const riskTolerance = 1e-9
+
+// planBlock holds a single-TP plan together with its stop, TP level and TP
+// slice backing array, so building a new plan costs one allocation instead of four
+type planBlock struct {
+	plan strategy.PositionPlan
+	stop strategy.StopLossLevel
+	tp   strategy.TakeProfitLevel
+	tps  [1]*strategy.TakeProfitLevel
+}
+
 // RiskRatioStrategy implements fixed risk-reward ratio strategy
 // This is the current default strategy from the CLI
 type RiskRatioStrategy struct {
-	calculator *calculator.Calculator
-	rrRatio    float64 // Default RR ratio (e.g., 2.0 for 2:1)
+	calculator  *calculator.Calculator
+	rrRatio     float64 // Default RR ratio (e.g., 2.0 for 2:1)
+	maxLeverage int     // Hard leverage ceiling, applied even if params.MaxLeverage is higher
+
+	sizing      strategy.SizingModel        // nil = built-in fixed-fractional sizing
+	constraints strategy.ConstraintProvider // nil = no exchange constraints
+	defaultRisk float64                     // RiskPercent used when params leave it 0; 0 = none
 }
 
-// New creates a new risk-ratio strategy
-func New(rrRatio float64) *RiskRatioStrategy {
-	return &RiskRatioStrategy{
-		calculator: calculator.New(125), // Max leverage 125x
-		rrRatio:    rrRatio,
+// Option configures a RiskRatioStrategy
+type Option func(*RiskRatioStrategy)
+
+// WithMaxLeverage caps leverage at maxLeverage regardless of params.MaxLeverage
+func WithMaxLeverage(maxLeverage int) Option {
+	return func(s *RiskRatioStrategy) {
+		s.maxLeverage = maxLeverage
 	}
 }
 
+// WithSizingModel replaces the built-in fixed-fractional sizing with model.
+// Params are still validated as usual, so RiskPercent must stay positive
+// even for models that ignore it; the plan's RiskAmount and RiskPercent
+// report what the model's size actually risks.
+func WithSizingModel(model strategy.SizingModel) Option {
+	return func(s *RiskRatioStrategy) {
+		s.sizing = model
+	}
+}
+
+// WithConstraints rounds plans to the exchange constraints provider reports
+// for the plan's symbol: the size down to the lot size and the take profit
+// to the tick size, rejecting plans below the minimum notional. Symbols the
+// provider doesn't know are planned unconstrained.
+func WithConstraints(provider strategy.ConstraintProvider) Option {
+	return func(s *RiskRatioStrategy) {
+		s.constraints = provider
+	}
+}
+
+// WithDefaultRisk applies riskPercent when params.RiskPercent is 0, for
+// hands-off callers that don't set it per trade. An explicit RiskPercent
+// always overrides it, and a negative one is still rejected.
+func WithDefaultRisk(riskPercent float64) Option {
+	return func(s *RiskRatioStrategy) {
+		s.defaultRisk = riskPercent
+	}
+}
+
+// New creates a new risk-ratio strategy. It panics if rrRatio is not a
+// positive finite number, since such a ratio puts the TP at or behind entry.
+func New(rrRatio float64, opts ...Option) *RiskRatioStrategy {
+	if !(rrRatio > 0) || math.IsInf(rrRatio, 1) {
+		panic(fmt.Sprintf("riskratio: rrRatio must be positive, got %v", rrRatio))
+	}
+
+	s := &RiskRatioStrategy{
+		rrRatio:     rrRatio,
+		maxLeverage: strategy.DefaultMaxLeverage, // Unless WithMaxLeverage is given
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.calculator = calculator.New(s.maxLeverage)
+	return s
+}
+
 // Name returns the strategy name
 func (s *RiskRatioStrategy) Name() string {
 	return "risk-ratio"
@@ -31,7 +166,7 @@ func (s *RiskRatioStrategy) Name() string {
 
 // Description returns a human-readable description
 func (s *RiskRatioStrategy) Description() string {
-	return fmt.Sprintf("Fixed risk-reward ratio strategy (%.1f:1)", s.rrRatio)
+	return fmt.Sprintf("Fixed risk-reward ratio strategy (%s:1)", strategy.FormatRatio(s.rrRatio))
 }
 
 // ValidateParams validates strategy parameters
@@ -40,65 +175,547 @@ func (s *RiskRatioStrategy) ValidateParams(params strategy.StrategyParams) error
 	return nil
 }
 
+// Parameters describes the optional params keys the strategy reads. The
+// AllowedLeveragesParam list has no ParamType and is documented on its key
+// instead.
+func (s *RiskRatioStrategy) Parameters() []strategy.ParamSpec {
+	return []strategy.ParamSpec{
+		{
+			Name:        PriceDecimalsParam,
+			Type:        strategy.ParamTypeInt,
+			Description: "Price decimals for scaled-integer sizing",
+			Min:         strategy.Bound(0),
+		},
+		{
+			Name:        SizeDecimalsParam,
+			Type:        strategy.ParamTypeInt,
+			Description: "Size decimals for scaled-integer sizing",
+			Default:     defaultSizeDecimals,
+			Min:         strategy.Bound(0),
+		},
+		{
+			Name:         MarginBufferPercentParam,
+			Type:         strategy.ParamTypeFloat,
+			Description:  "Percent of the balance kept as a cash cushion",
+			Min:          strategy.Bound(0),
+			Max:          strategy.Bound(100),
+			ExclusiveMax: true,
+		},
+		{
+			Name:         MaxNotionalMultipleParam,
+			Type:         strategy.ParamTypeFloat,
+			Description:  "Notional cap as a multiple of the balance",
+			Min:          strategy.Bound(0),
+			ExclusiveMin: true,
+		},
+		{
+			Name:         CurrentPriceParam,
+			Type:         strategy.ParamTypeFloat,
+			Description:  "Market price a limit entry must rest behind",
+			Min:          strategy.Bound(0),
+			ExclusiveMin: true,
+		},
+		{
+			Name:         TargetPriceParam,
+			Type:         strategy.ParamTypeFloat,
+			Description:  "Fixed take-profit price replacing the RR-based one",
+			Min:          strategy.Bound(0),
+			ExclusiveMin: true,
+		},
+		{
+			Name:         TPTicksParam,
+			Type:         strategy.ParamTypeInt,
+			Description:  "Take profit distance from entry in ticks; requires tickSize",
+			Min:          strategy.Bound(0),
+			ExclusiveMin: true,
+		},
+		{
+			Name:         TickSizeParam,
+			Type:         strategy.ParamTypeFloat,
+			Description:  "Instrument's minimum price increment",
+			Min:          strategy.Bound(0),
+			ExclusiveMin: true,
+		},
+		{
+			Name:        ExistingSizeParam,
+			Type:        strategy.ParamTypeFloat,
+			Description: "Size of an open same-side position; requires existingStop",
+			Min:         strategy.Bound(0),
+		},
+		{
+			Name:         ExistingStopParam,
+			Type:         strategy.ParamTypeFloat,
+			Description:  "Stop price of the existing position",
+			Min:          strategy.Bound(0),
+			ExclusiveMin: true,
+		},
+		{
+			Name:        ClampPolicyParam,
+			Type:        strategy.ParamTypeString,
+			Description: "What to do when the leverage clamp leaves the size unaffordable: error, shrink or keep",
+			Default:     string(ClampError),
+		},
+	}
+}
+
 // CalculatePosition calculates position size, leverage, and TP/SL
 func (s *RiskRatioStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	plan, _, err := s.CalculatePositionWithWarnings(ctx, params)
+	return plan, err
+}
+
+// CalculatePositionWithWarnings is CalculatePosition that also reports the
+// adjustments made along the way (notional capped, leverage clamped or
+// snapped). Warnings are returned even with an error, since an adjustment is
+// often what led to the rejection.
+func (s *RiskRatioStrategy) CalculatePositionWithWarnings(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, []strategy.Warning, error) {
+	// The plan, its stop and its TP share one allocation
+	block := &planBlock{}
+	block.plan.StopLoss = &block.stop
+	block.plan.TakeProfits = block.tps[:0]
+	block.tps[0] = &block.tp
+
+	warnings, err := s.calculateInto(params, &block.plan)
+	if err != nil {
+		return nil, warnings, err
+	}
+	return &block.plan, warnings, nil
+}
+
+// CalculatePositionInto is CalculatePosition writing into a caller-supplied
+// plan, for allocation-sensitive loops such as backtests. out is overwritten
+// entirely; its StopLoss, its TakeProfits backing array and the first TP
+// level are reused when present, so a plan reused across calls allocates
+// nothing. Don't hold on to those pointers between calls. On error out's
+// contents are unspecified.
+func (s *RiskRatioStrategy) CalculatePositionInto(ctx context.Context, params strategy.PositionParams, out *strategy.PositionPlan) error {
+	_, err := s.calculateInto(params, out)
+	return err
+}
+
+// calculateInto validates params and writes the resulting plan into out,
+// reusing out's stop, TP slice and first TP level when present
+func (s *RiskRatioStrategy) calculateInto(params strategy.PositionParams, out *strategy.PositionPlan) ([]strategy.Warning, error) {
+	var warnings []strategy.Warning
+
+	if params.RiskPercent == 0 {
+		params.RiskPercent = s.defaultRisk
+	}
+
 	// Validate inputs
+	if err := strategy.ValidateSide(params.Side); err != nil {
+		return warnings, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := strategy.ValidateFiniteParams(params); err != nil {
+		return warnings, fmt.Errorf("validation failed: %w", err)
+	}
+	// Reject effectively-equal stops first so float noise can't slip past the strict check
+	if err := strategy.ValidateStopLoss(params.Side, params.EntryPrice, params.StopLoss, strategy.DefaultPriceEpsilon); err != nil {
+		return warnings, fmt.Errorf("validation failed: %w", err)
+	}
 	if err := s.calculator.ValidateInputs(params.Side, params.EntryPrice, params.StopLoss, params.RiskPercent, params.AccountBalance); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+		return warnings, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := validateLimitEntry(params); err != nil {
+		return warnings, fmt.Errorf("validation failed: %w", err)
 	}
 
 	// 1. Calculate position size based on risk
 	// Formula: size = (balance * risk%) / (entry - sl)
-	size := s.calculator.CalculateSize(
-		params.AccountBalance,
-		params.RiskPercent,
-		params.EntryPrice,
-		params.StopLoss,
-		params.Side,
-	)
+	size, notional, err := s.calculateSize(params)
+	if err != nil {
+		return warnings, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Risk already open in the symbol shares the cap with the new trade
+	exposureSize, err := capExposure(params, size)
+	if err != nil {
+		return warnings, err
+	}
+	if exposureSize < size {
+		warnings = append(warnings, strategy.NewWarning(strategy.WarningExposureCapped,
+			"size reduced from %v to %v so existing and new risk stay within %v%%", size, exposureSize, params.RiskPercent))
+		size = exposureSize
+		notional = size * params.EntryPrice
+	}
+
+	// Hard notional ceiling, applied before leverage so leverage follows the capped size
+	capped, err := capNotional(params, size, notional)
+	if err != nil {
+		return warnings, fmt.Errorf("validation failed: %w", err)
+	}
+	if capped < size {
+		warnings = append(warnings, strategy.NewWarning(strategy.WarningNotionalCapped,
+			"size reduced from %v to %v to keep notional within the cap", size, capped))
+		size = capped
+		notional = size * params.EntryPrice
+	}
+
+	// Exchange constraints, also before leverage
+	info, hasInfo := s.symbolInfo(params.Symbol)
+	if hasInfo && info.LotSize > 0 {
+		rounded := strategy.RoundDownToStep(size, info.LotSize)
+		if rounded <= 0 {
+			return warnings, fmt.Errorf("validation failed: size %v is below lot size %v", size, info.LotSize)
+		}
+		if rounded < size {
+			size = rounded
+			notional = size * params.EntryPrice
+		}
+	}
+	if hasInfo && notional < info.MinNotional {
+		return warnings, fmt.Errorf("validation failed: notional %v is below the exchange minimum %v", notional, info.MinNotional)
+	}
 
 	// 2. Calculate required leverage
-	// Formula: leverage = ceil(notional / balance)
+	// Formula: leverage = ceil(notional / (balance * (1 - buffer%)))
+	margin, err := availableMargin(params)
+	if err != nil {
+		return warnings, fmt.Errorf("validation failed: %w", err)
+	}
+	maxLeverage := s.effectiveMaxLeverage(params)
 	leverage := s.calculator.CalculateLeverage(
 		size,
 		params.EntryPrice,
-		params.AccountBalance,
-		params.MaxLeverage,
+		margin,
+		maxLeverage,
 	)
+	if required := math.Ceil(notional / margin); required > float64(maxLeverage) {
+		warnings = append(warnings, strategy.NewWarning(strategy.WarningLeverageClamped,
+			"required leverage %.0fx clamped to max %dx", required, maxLeverage))
+	}
+	allowed, _, err := strategy.StrategyParams(params.Params).Ints(AllowedLeveragesParam)
+	if err != nil {
+		return warnings, fmt.Errorf("validation failed: %w", err)
+	}
+	snapped, err := strategy.SnapLeverage(leverage, allowed, maxLeverage)
+	if err != nil {
+		return warnings, fmt.Errorf("validation failed: %w", err)
+	}
+	if snapped != leverage {
+		warnings = append(warnings, strategy.NewWarning(strategy.WarningLeverageSnapped,
+			"leverage %dx snapped to allowed %dx", leverage, snapped))
+		leverage = snapped
+	}
+	// The max-leverage clamp can leave the full size unaffordable
+	policy, err := clampPolicy(params)
+	if err != nil {
+		return warnings, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := strategy.ValidateMargin(notional, leverage, margin); err != nil {
+		switch policy {
+		case ClampError:
+			return warnings, fmt.Errorf("validation failed: %w", err)
+		case ClampShrink:
+			shrunk := float64(leverage) * margin / params.EntryPrice
+			if hasInfo {
+				shrunk = strategy.RoundDownToStep(shrunk, info.LotSize)
+				if !(shrunk > 0) || shrunk*params.EntryPrice < info.MinNotional {
+					return warnings, fmt.Errorf("validation failed: size shrunk to %v for %dx leverage is below the exchange minimum", shrunk, leverage)
+				}
+			}
+			warnings = append(warnings, strategy.NewWarning(strategy.WarningMarginShrunk,
+				"size reduced from %v to %v to fit %dx leverage", size, shrunk, leverage))
+			size = shrunk
+			notional = size * params.EntryPrice
+		}
+	}
 
-	// 3. Calculate TP based on RR ratio
+	// 3. Calculate TP based on RR ratio, unless a target price or tick distance is given
 	// Formula: tp = entry + (sl_distance * rr_ratio)
-	tpPrice := s.calculator.CalculateRRTakeProfit(
+	tpPrice, fixedTP, err := fixedTakeProfit(params)
+	if err != nil {
+		return warnings, fmt.Errorf("validation failed: %w", err)
+	}
+	if !fixedTP {
+		tpPrice = s.calculator.CalculateRRTakeProfit(
+			params.EntryPrice,
+			params.StopLoss,
+			s.rrRatio,
+			params.Side,
+		)
+		if err := strategy.ValidateTakeProfit(params.Side, params.EntryPrice, tpPrice, strategy.DefaultPriceEpsilon); err != nil {
+			return warnings, fmt.Errorf("calculation failed: %w", err)
+		}
+	}
+	if hasInfo && info.TickSize > 0 {
+		tpPrice = strategy.RoundToTick(tpPrice, info.TickSize)
+		if err := strategy.ValidateTakeProfit(params.Side, params.EntryPrice, tpPrice, strategy.DefaultPriceEpsilon); err != nil {
+			return warnings, fmt.Errorf("calculation failed: take profit rounded to tick %v: %w", info.TickSize, err)
+		}
+	}
+
+	// Build position plan
+	stop := out.StopLoss
+	if stop == nil {
+		stop = &strategy.StopLossLevel{}
+	}
+	*stop = strategy.StopLossLevel{
+		Price: params.StopLoss,
+		Type:  strategy.StopLossTypeFixed,
+	}
+
+	tps := out.TakeProfits[:0]
+	var tp *strategy.TakeProfitLevel
+	if cap(tps) > 0 {
+		tp = tps[:1][0]
+	}
+	if tp == nil {
+		tp = &strategy.TakeProfitLevel{}
+	}
+	*tp = strategy.TakeProfitLevel{
+		Price:      tpPrice,
+		Percentage: 100,
+		Type:       strategy.TakeProfitTypeLimit,
+	}
+
+	// Report what the final size actually loses at the stop
+	riskAmount, riskPercent := reportedRisk(params, size)
+
+	*out = strategy.PositionPlan{
+		Symbol:        params.Symbol,
+		Side:          params.Side,
+		Size:          size,
+		EntryPrice:    params.EntryPrice,
+		Leverage:      leverage,
+		StopLoss:      stop,
+		TakeProfits:   append(tps, tp),
+		RiskAmount:    riskAmount,
+		RiskPercent:   riskPercent,
+		NotionalValue: notional,
+		StrategyName:  s.Name(),
+		Timestamp:     time.Now().UTC(),
+	}
+
+	// Extreme inputs can overflow even when each one is individually valid
+	if err := strategy.ValidatePlanFinite(out); err != nil {
+		return warnings, fmt.Errorf("calculation failed: %w", err)
+	}
+
+	return warnings, nil
+}
+
+// fixedTakeProfit returns the TP from TargetPriceParam or TPTicksParam, with
+// ok false when neither is set and the RR-based TP applies
+func fixedTakeProfit(params strategy.PositionParams) (float64, bool, error) {
+	p := strategy.StrategyParams(params.Params)
+
+	tpPrice, hasTarget, err := p.Float(TargetPriceParam)
+	if err != nil {
+		return 0, false, err
+	}
+	ticks, hasTicks, err := p.Int(TPTicksParam)
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch {
+	case hasTarget && hasTicks:
+		return 0, false, fmt.Errorf("%s and %s are mutually exclusive", TargetPriceParam, TPTicksParam)
+	case hasTicks:
+		tickSize, ok, err := p.Float(TickSizeParam)
+		if err != nil {
+			return 0, false, err
+		}
+		if !ok || tickSize <= 0 {
+			return 0, false, fmt.Errorf("%s requires a positive %s", TPTicksParam, TickSizeParam)
+		}
+		if ticks <= 0 {
+			return 0, false, fmt.Errorf("%s must be positive, got %d", TPTicksParam, ticks)
+		}
+
+		distance := float64(ticks) * tickSize
+		if params.Side == strategy.SideShort {
+			distance = -distance
+		}
+		tpPrice = params.EntryPrice + distance
+		if tpPrice <= 0 {
+			return 0, false, fmt.Errorf("%d ticks of %v from entry %v gives a non-positive take profit", ticks, tickSize, params.EntryPrice)
+		}
+	case !hasTarget:
+		return 0, false, nil
+	}
+
+	if err := strategy.ValidateTakeProfit(params.Side, params.EntryPrice, tpPrice, strategy.DefaultPriceEpsilon); err != nil {
+		return 0, false, err
+	}
+	return tpPrice, true, nil
+}
+
+// symbolInfo returns the provider's constraints for symbol, if any
+func (s *RiskRatioStrategy) symbolInfo(symbol string) (strategy.SymbolInfo, bool) {
+	if s.constraints == nil {
+		return strategy.SymbolInfo{}, false
+	}
+	return s.constraints.Constraints(symbol)
+}
+
+// effectiveMaxLeverage returns params.MaxLeverage capped at the strategy's
+// own ceiling; a non-positive params value means the ceiling alone
+func (s *RiskRatioStrategy) effectiveMaxLeverage(params strategy.PositionParams) int {
+	return strategy.EffectiveMaxLeverage(params.MaxLeverage, s.maxLeverage)
+}
+
+// calculateSize returns the size and notional from the sizing model when one
+// is set, and otherwise the fixed-fractional risk-based size, using
+// scaled-integer math when PriceDecimalsParam is set
+func (s *RiskRatioStrategy) calculateSize(params strategy.PositionParams) (float64, float64, error) {
+	if s.sizing != nil {
+		size, err := s.sizing.Size(params)
+		if err != nil {
+			return 0, 0, err
+		}
+		if !(size > 0) || math.IsInf(size, 0) {
+			return 0, 0, fmt.Errorf("sizing model returned invalid size %v", size)
+		}
+		return size, size * params.EntryPrice, nil
+	}
+
+	p := strategy.StrategyParams(params.Params)
+
+	priceDecimals, ok, err := p.Int(PriceDecimalsParam)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !ok {
+		size := s.calculator.CalculateSize(
+			params.AccountBalance,
+			params.RiskPercent,
+			params.EntryPrice,
+			params.StopLoss,
+			params.Side,
+		)
+		return size, size * params.EntryPrice, nil
+	}
+
+	sizeDecimals, ok, err := p.Int(SizeDecimalsParam)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !ok {
+		sizeDecimals = defaultSizeDecimals
+	}
+
+	return strategy.ScaledSize(
+		params.AccountBalance,
+		params.RiskPercent,
 		params.EntryPrice,
 		params.StopLoss,
-		s.rrRatio,
-		params.Side,
+		priceDecimals,
+		sizeDecimals,
 	)
+}
 
-	// Build position plan
-	return &strategy.PositionPlan{
-		Symbol:     params.Symbol,
-		Side:       params.Side,
-		Size:       size,
-		EntryPrice: params.EntryPrice,
-		Leverage:   leverage,
-		StopLoss: &strategy.StopLossLevel{
-			Price: params.StopLoss,
-			Type:  strategy.StopLossTypeFixed,
-		},
-		TakeProfits: []*strategy.TakeProfitLevel{
-			{
-				Price:      tpPrice,
-				Percentage: 100,
-				Type:       strategy.TakeProfitTypeLimit,
-			},
-		},
-		RiskAmount:    params.AccountBalance * params.RiskPercent / 100,
-		RiskPercent:   params.RiskPercent,
-		NotionalValue: size * params.EntryPrice,
-		StrategyName:  s.Name(),
-		Timestamp:     time.Now(),
-	}, nil
+// capNotional returns size shrunk so notional stays within
+// MaxNotionalMultipleParam times the balance, or size unchanged when no cap is set
+func capNotional(params strategy.PositionParams, size, notional float64) (float64, error) {
+	multiple, ok, err := strategy.StrategyParams(params.Params).Float(MaxNotionalMultipleParam)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return size, nil
+	}
+	if multiple <= 0 {
+		return 0, fmt.Errorf("max notional multiple must be positive, got %.2f", multiple)
+	}
+
+	maxNotional := params.AccountBalance * multiple
+	if notional <= maxNotional {
+		return size, nil
+	}
+	return maxNotional / params.EntryPrice, nil
+}
+
+// capExposure returns size shrunk so the open risk of the ExistingSizeParam
+// position plus the new trade's risk stays within balance * risk%, or size
+// unchanged when there is no existing position. The existing risk is
+// measured from the new entry to its stop, and a stop already past entry
+// in the position's favour counts as no risk. Errors wrap ErrNoTrade when
+// the existing risk leaves no room.
+func capExposure(params strategy.PositionParams, size float64) (float64, error) {
+	p := strategy.StrategyParams(params.Params)
+
+	existingSize, ok, err := p.Float(ExistingSizeParam)
+	if err != nil {
+		return 0, fmt.Errorf("validation failed: %w", err)
+	}
+	if !ok || existingSize == 0 {
+		return size, nil
+	}
+	if existingSize < 0 {
+		return 0, fmt.Errorf("validation failed: existing size must not be negative, got %v", existingSize)
+	}
+	existingStop, ok, err := p.Float(ExistingStopParam)
+	if err != nil {
+		return 0, fmt.Errorf("validation failed: %w", err)
+	}
+	if !ok || existingStop <= 0 {
+		return 0, fmt.Errorf("validation failed: %s requires a positive %s", ExistingSizeParam, ExistingStopParam)
+	}
+
+	open := existingSize * (params.EntryPrice - existingStop)
+	if params.Side == strategy.SideShort {
+		open = -open
+	}
+	open = math.Max(open, 0)
+
+	riskCap := strategy.RiskAmount(params.AccountBalance, params.RiskPercent)
+	budget := riskCap - open
+	if budget <= 0 {
+		return 0, fmt.Errorf("%w: existing %s position already risks %v of the %v cap",
+			strategy.ErrNoTrade, params.Symbol, open, riskCap)
+	}
+	return math.Min(size, budget/math.Abs(params.EntryPrice-params.StopLoss)), nil
+}
+
+// reportedRisk returns the plan's risk amount and percent for size: the
+// requested balance * risk% when size risks exactly that, otherwise the
+// actual stop-out loss, so RiskAmount always matches size * stop distance
+// after a model, rounding, cap or clamp changed the size
+func reportedRisk(params strategy.PositionParams, size float64) (float64, float64) {
+	nominal := strategy.RiskAmount(params.AccountBalance, params.RiskPercent)
+	actual := size * math.Abs(params.EntryPrice-params.StopLoss)
+	if math.Abs(actual-nominal) <= riskTolerance*nominal {
+		return nominal, params.RiskPercent
+	}
+	return actual, strategy.RiskPercent(actual, params.AccountBalance)
+}
+
+// clampPolicy returns ClampPolicyParam, defaulting to ClampError
+func clampPolicy(params strategy.PositionParams) (ClampPolicy, error) {
+	raw, ok, err := strategy.StrategyParams(params.Params).String(ClampPolicyParam)
+	if err != nil || !ok {
+		return ClampError, err
+	}
+	switch policy := ClampPolicy(raw); policy {
+	case ClampError, ClampShrink, ClampKeep:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown clamp policy %q", raw)
+	}
+}
+
+// validateLimitEntry checks the entry against CurrentPriceParam; a missing or
+// zero current price skips the check
+func validateLimitEntry(params strategy.PositionParams) error {
+	current, _, err := strategy.StrategyParams(params.Params).Float(CurrentPriceParam)
+	if err != nil || current == 0 {
+		return err
+	}
+	return strategy.ValidateLimitEntry(params.Side, params.EntryPrice, current)
+}
+
+// availableMargin returns the balance usable as margin after MarginBufferPercentParam
+func availableMargin(params strategy.PositionParams) (float64, error) {
+	buffer, _, err := strategy.StrategyParams(params.Params).Float(MarginBufferPercentParam)
+	if err != nil {
+		return 0, err
+	}
+	if buffer < 0 || buffer >= 100 {
+		return 0, fmt.Errorf("margin buffer must be in [0, 100), got %.2f", buffer)
+	}
+	return params.AccountBalance * (1 - buffer/100), nil
 }
 
 // OnPositionOpened callback after position is opened
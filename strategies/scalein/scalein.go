@@ -0,0 +1,246 @@
+package scalein
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/agatticelli/calculator-go"
+	"github.com/agatticelli/strategy-go"
+)
+
+// ScaleInStrategy takes part of the position at market and ladders the rest
+// with limit orders further from the market (below it for LONG, above for
+// SHORT). Size, stop and TP are all computed from the blended average entry
+// of every leg, so the full ladder risks exactly the requested amount.
+//
+// Parameters:
+//   - rrRatio: TP distance as a multiple of the blended entry's stop distance
+//   - marketPercent: percentage of the position filled at market (0-100]
+//   - limitOffsets: distance of each limit leg from the market price, in %,
+//     strictly increasing; the remainder is split equally across them
+//
+// Example:
+//
+//	// 50% at market, 25% each at 0.5% and 1% below it
+//	strat := New(2.0, 50, 0.5, 1.0)
+//	plan, err := strat.CalculatePosition(ctx, params)
+//	orders, err := strat.PlanOrders(plan, strategy.StopOrderConfig{})
+type ScaleInStrategy struct {
+	calculator    *calculator.Calculator
+	rrRatio       float64
+	marketPercent float64
+	limitOffsets  []float64
+}
+
+// New creates a new scale-in strategy
+func New(rrRatio, marketPercent float64, limitOffsets ...float64) *ScaleInStrategy {
+	return &ScaleInStrategy{
+		calculator:    calculator.New(strategy.DefaultMaxLeverage),
+		rrRatio:       rrRatio,
+		marketPercent: marketPercent,
+		limitOffsets:  append([]float64(nil), limitOffsets...),
+	}
+}
+
+// Name returns the strategy name
+func (s *ScaleInStrategy) Name() string {
+	return "scale-in"
+}
+
+// Description returns a human-readable description
+func (s *ScaleInStrategy) Description() string {
+	return fmt.Sprintf("Scale-in strategy (%.0f%% at market, %d limit legs, RR %s:1 from blended entry)",
+		s.marketPercent, len(s.limitOffsets), strategy.FormatRatio(s.rrRatio))
+}
+
+// ValidateParams validates strategy parameters
+func (s *ScaleInStrategy) ValidateParams(params strategy.StrategyParams) error {
+	if s.rrRatio <= 0 {
+		return fmt.Errorf("RR ratio must be positive, got %.2f", s.rrRatio)
+	}
+	if s.marketPercent <= 0 || s.marketPercent > 100 {
+		return fmt.Errorf("market percentage must be in (0, 100], got %.2f", s.marketPercent)
+	}
+	if s.marketPercent < 100 && len(s.limitOffsets) == 0 {
+		return fmt.Errorf("%.2f%% left for limit legs but no limit offsets given", 100-s.marketPercent)
+	}
+	if s.marketPercent == 100 && len(s.limitOffsets) > 0 {
+		return fmt.Errorf("limit offsets given but nothing left for them at 100%% market")
+	}
+	for i, offset := range s.limitOffsets {
+		if offset <= 0 || offset >= 100 {
+			return fmt.Errorf("limit offset %d must be in (0, 100), got %.2f", i+1, offset)
+		}
+		if i > 0 && offset <= s.limitOffsets[i-1] {
+			return fmt.Errorf("limit offset %d (%.2f) must be further than offset %d (%.2f)", i+1, offset, i, s.limitOffsets[i-1])
+		}
+	}
+	return nil
+}
+
+// CalculatePosition sizes the whole ladder from its blended entry.
+// params.EntryPrice is the market price; the plan's EntryPrice is the
+// blended average. Use PlanOrders to turn the plan into the entry legs.
+func (s *ScaleInStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	if err := s.ValidateParams(params.Params); err != nil {
+		return nil, fmt.Errorf("invalid strategy config: %w", err)
+	}
+
+	// Validate inputs
+	if err := strategy.ValidateSide(params.Side); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.calculator.ValidateInputs(params.Side, params.EntryPrice, params.StopLoss, params.RiskPercent, params.AccountBalance); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	prices := s.legPrices(params.Side, params.EntryPrice)
+	fractions := s.legFractions()
+
+	// Every leg must fill before the stop, or the stop would sit inside the
+	// ladder. Offsets are strictly increasing, so the last leg is the deepest.
+	deepest := prices[len(prices)-1]
+	if err := strategy.ValidateStopLoss(params.Side, deepest, params.StopLoss, strategy.DefaultPriceEpsilon); err != nil {
+		return nil, fmt.Errorf("validation failed: deepest limit leg: %w", err)
+	}
+
+	var blended float64
+	for i, price := range prices {
+		blended += price * fractions[i]
+	}
+
+	size := s.calculator.CalculateSize(
+		params.AccountBalance,
+		params.RiskPercent,
+		blended,
+		params.StopLoss,
+		params.Side,
+	)
+
+	leverage := s.calculator.CalculateLeverage(
+		size,
+		blended,
+		params.AccountBalance,
+		strategy.EffectiveMaxLeverage(params.MaxLeverage, strategy.DefaultMaxLeverage),
+	)
+
+	tpPrice := s.calculator.CalculateRRTakeProfit(blended, params.StopLoss, s.rrRatio, params.Side)
+
+	return &strategy.PositionPlan{
+		Symbol:     params.Symbol,
+		Side:       params.Side,
+		Size:       size,
+		EntryPrice: blended,
+		Leverage:   leverage,
+		StopLoss: &strategy.StopLossLevel{
+			Price: params.StopLoss,
+			Type:  strategy.StopLossTypeFixed,
+		},
+		TakeProfits: []*strategy.TakeProfitLevel{
+			{
+				Price:      tpPrice,
+				Percentage: 100,
+				Type:       strategy.TakeProfitTypeLimit,
+			},
+		},
+		RiskAmount:    strategy.RiskAmount(params.AccountBalance, params.RiskPercent),
+		RiskPercent:   params.RiskPercent,
+		NotionalValue: size * blended,
+		StrategyName:  s.Name(),
+		Timestamp:     time.Now().UTC(),
+	}, nil
+}
+
+// EntryLegs returns the entry legs of a plan from CalculatePosition: one
+// market order followed by the limit ladder, nearest first. PositionPlan
+// only carries the blended entry, so the market price is recovered from it
+// with this strategy's offsets; plans from another strategy are rejected.
+func (s *ScaleInStrategy) EntryLegs(plan *strategy.PositionPlan) ([]strategy.EntryLeg, error) {
+	if plan == nil || plan.StrategyName != s.Name() {
+		return nil, fmt.Errorf("not a %s plan", s.Name())
+	}
+	if err := s.ValidateParams(nil); err != nil {
+		return nil, fmt.Errorf("invalid strategy config: %w", err)
+	}
+	if err := strategy.ValidateSide(plan.Side); err != nil {
+		return nil, err
+	}
+
+	fractions := s.legFractions()
+	multipliers := s.legMultipliers(plan.Side)
+	var blend float64
+	for i, m := range multipliers {
+		blend += m * fractions[i]
+	}
+	prices := s.legPrices(plan.Side, plan.EntryPrice/blend)
+
+	legs := make([]strategy.EntryLeg, len(prices))
+	for i, price := range prices {
+		legs[i] = strategy.EntryLeg{Kind: strategy.EntryLimit, Price: price, Percentage: fractions[i] * 100}
+	}
+	legs[0] = strategy.EntryLeg{Kind: strategy.EntryMarket, Percentage: fractions[0] * 100}
+	return legs, nil
+}
+
+// PlanOrders translates a plan from CalculatePosition into orders with
+// strategy.PlanOrders, entering through EntryLegs, so every leg gets a
+// client order ID, lot rounding and the WithMaxOrders cap
+func (s *ScaleInStrategy) PlanOrders(plan *strategy.PositionPlan, stopCfg strategy.StopOrderConfig, opts ...strategy.OrderOption) (*strategy.OrderSet, error) {
+	legs, err := s.EntryLegs(plan)
+	if err != nil {
+		return nil, err
+	}
+	return strategy.PlanOrders(plan, strategy.EntryMarket, stopCfg, append(opts, strategy.WithEntryLegs(legs...))...)
+}
+
+// legMultipliers returns each leg's price as a multiple of the market price,
+// matching legPrices
+func (s *ScaleInStrategy) legMultipliers(side strategy.Side) []float64 {
+	multipliers := make([]float64, 0, 1+len(s.limitOffsets))
+	multipliers = append(multipliers, 1)
+	for _, offset := range s.limitOffsets {
+		if side == strategy.SideShort {
+			multipliers = append(multipliers, 1+offset/100)
+		} else {
+			multipliers = append(multipliers, 1-offset/100)
+		}
+	}
+	return multipliers
+}
+
+// legPrices returns the market price followed by each limit leg's price
+func (s *ScaleInStrategy) legPrices(side strategy.Side, market float64) []float64 {
+	prices := s.legMultipliers(side)
+	for i := range prices {
+		prices[i] *= market
+	}
+	return prices
+}
+
+// legFractions returns each leg's share of the position, matching legPrices
+func (s *ScaleInStrategy) legFractions() []float64 {
+	fractions := make([]float64, 0, 1+len(s.limitOffsets))
+	fractions = append(fractions, s.marketPercent/100)
+	for range s.limitOffsets {
+		fractions = append(fractions, (100-s.marketPercent)/100/float64(len(s.limitOffsets)))
+	}
+	return fractions
+}
+
+// OnPositionOpened callback after position is opened
+func (s *ScaleInStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	// Legs are planned by CalculatePosition
+	return nil
+}
+
+// OnPriceUpdate is a no-op; resting limit legs fill on the exchange
+func (s *ScaleInStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	return &strategy.StrategyAction{Type: strategy.ActionTypeNone}, nil
+}
+
+// ShouldClose determines if position should be closed
+func (s *ScaleInStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	// Let TP/SL orders handle closing
+	return false, ""
+}
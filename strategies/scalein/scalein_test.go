@@ -0,0 +1,263 @@
+package scalein
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/agatticelli/strategy-go"
+	"github.com/agatticelli/trading-common-types"
+)
+
+func TestName(t *testing.T) {
+	strat := New(2.0, 50, 1.0, 2.0)
+	if name := strat.Name(); name != "scale-in" {
+		t.Errorf("Name() = %q, want %q", name, "scale-in")
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		strat   *ScaleInStrategy
+		wantErr bool
+	}{
+		{name: "Valid ladder", strat: New(2.0, 50, 1.0, 2.0), wantErr: false},
+		{name: "Valid all at market", strat: New(2.0, 100), wantErr: false},
+		{name: "Invalid: zero market percent", strat: New(2.0, 0, 1.0), wantErr: true},
+		{name: "Invalid: remainder without limit legs", strat: New(2.0, 50), wantErr: true},
+		{name: "Invalid: limit legs with nothing left", strat: New(2.0, 100, 1.0), wantErr: true},
+		{name: "Invalid: non-positive offset", strat: New(2.0, 50, 0), wantErr: true},
+		{name: "Invalid: unordered offsets", strat: New(2.0, 50, 1.0, 0.5), wantErr: true},
+		{name: "Invalid: repeated offset", strat: New(2.0, 50, 1.0, 1.0), wantErr: true},
+		{name: "Invalid: non-positive RR", strat: New(0, 50, 1.0), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.strat.ValidateParams(strategy.StrategyParams{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalculatePosition(t *testing.T) {
+	tests := []struct {
+		name        string
+		strat       *ScaleInStrategy
+		side        types.Side
+		stopLoss    float64
+		wantBlended float64
+		wantTP      float64
+		wantPrices  []float64 // Limit leg prices, nearest first
+	}{
+		{
+			name:        "LONG 50% market, two legs below",
+			strat:       New(2.0, 50, 1.0, 2.0),
+			side:        types.SideLong,
+			stopLoss:    44000.0,
+			wantBlended: 44662.5, // 0.5*45000 + 0.25*44550 + 0.25*44100
+			wantTP:      45987.5, // blended + 2 * 662.5
+			wantPrices:  []float64{44550.0, 44100.0},
+		},
+		{
+			name:        "SHORT 60% market, one leg above",
+			strat:       New(1.5, 60, 2.0),
+			side:        types.SideShort,
+			stopLoss:    46500.0,
+			wantBlended: 45360.0, // 0.6*45000 + 0.4*45900
+			wantTP:      43650.0, // blended - 1.5 * 1140
+			wantPrices:  []float64{45900.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := strategy.PositionParams{
+				Symbol:         "BTC-USDT",
+				Side:           tt.side,
+				EntryPrice:     45000.0,
+				StopLoss:       tt.stopLoss,
+				AccountBalance: 1000.0,
+				RiskPercent:    2.0,
+				MaxLeverage:    125,
+			}
+
+			plan, err := tt.strat.CalculatePosition(context.Background(), params)
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v", err)
+			}
+
+			if math.Abs(plan.EntryPrice-tt.wantBlended) > 1e-6 {
+				t.Errorf("EntryPrice = %.4f, want blended %.4f", plan.EntryPrice, tt.wantBlended)
+			}
+			if plan.StopLoss.Price != tt.stopLoss {
+				t.Errorf("StopLoss = %.2f, want %.2f", plan.StopLoss.Price, tt.stopLoss)
+			}
+			if len(plan.TakeProfits) != 1 || math.Abs(plan.TakeProfits[0].Price-tt.wantTP) > 1e-6 {
+				t.Errorf("TakeProfits = %+v, want single TP at %.2f", plan.TakeProfits, tt.wantTP)
+			}
+
+			set, err := tt.strat.PlanOrders(plan, strategy.StopOrderConfig{})
+			if err != nil {
+				t.Fatalf("PlanOrders() error = %v", err)
+			}
+			orders := set.Entries
+			if len(orders) != 1+len(tt.wantPrices) {
+				t.Fatalf("len(Entries) = %d, want %d", len(orders), 1+len(tt.wantPrices))
+			}
+			if orders[0].Kind != strategy.EntryMarket || orders[0].Price != 0 {
+				t.Errorf("orders[0] = %+v, want market order", orders[0])
+			}
+
+			var totalSize, stopOutLoss float64
+			for i, order := range orders {
+				if order.Symbol != params.Symbol || order.Side != tt.side {
+					t.Errorf("orders[%d] = %+v, want %s %s", i, order, params.Symbol, tt.side)
+				}
+				fill := params.EntryPrice
+				if i > 0 {
+					if order.Kind != strategy.EntryLimit || math.Abs(order.Price-tt.wantPrices[i-1]) > 1e-6 {
+						t.Errorf("orders[%d] = %+v, want limit at %.2f", i, order, tt.wantPrices[i-1])
+					}
+					fill = order.Price
+				}
+				totalSize += order.Size
+				stopOutLoss += order.Size * math.Abs(fill-tt.stopLoss)
+			}
+
+			if math.Abs(totalSize-plan.Size) > 1e-9 {
+				t.Errorf("sum of leg sizes = %.6f, want plan size %.6f", totalSize, plan.Size)
+			}
+			if math.Abs(orders[0].Size-plan.Size*tt.strat.marketPercent/100) > 1e-9 {
+				t.Errorf("market leg size = %.6f, want %.0f%% of %.6f", orders[0].Size, tt.strat.marketPercent, plan.Size)
+			}
+			// Sized from the blended entry, the full ladder risks exactly the requested amount
+			if math.Abs(stopOutLoss-plan.RiskAmount) > 1e-6 {
+				t.Errorf("stop-out loss = %.4f, want %.4f", stopOutLoss, plan.RiskAmount)
+			}
+		})
+	}
+}
+
+func TestCalculatePosition_StopInsideLadder(t *testing.T) {
+	strat := New(2.0, 50, 1.0, 2.0)
+
+	// Deepest leg at 44100 sits below the stop
+	plan, err := strat.CalculatePosition(context.Background(), strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           types.SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44200.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+	})
+	if err == nil {
+		t.Error("CalculatePosition() error = nil, want error for stop inside the ladder")
+	}
+	if plan != nil {
+		t.Errorf("CalculatePosition() plan = %+v, want nil", plan)
+	}
+}
+
+func TestCalculatePosition_UnorderedOffsets(t *testing.T) {
+	// The 1% leg at 99 sits below the stop even though the last leg (99.5) doesn't
+	strat := New(2.0, 50, 1.0, 0.5)
+
+	plan, err := strat.CalculatePosition(context.Background(), strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           types.SideLong,
+		EntryPrice:     100.0,
+		StopLoss:       99.2,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+	})
+	if err == nil {
+		t.Error("CalculatePosition() error = nil, want error for unordered limit offsets")
+	}
+	if plan != nil {
+		t.Errorf("CalculatePosition() plan = %+v, want nil", plan)
+	}
+}
+
+func TestPlanOrders_IndependentPlans(t *testing.T) {
+	strat := New(2.0, 50, 1.0, 2.0)
+	params := func(market, stop float64) strategy.PositionParams {
+		return strategy.PositionParams{
+			Symbol:         "BTC-USDT",
+			Side:           types.SideLong,
+			EntryPrice:     market,
+			StopLoss:       stop,
+			AccountBalance: 1000.0,
+			RiskPercent:    2.0,
+			MaxLeverage:    125,
+		}
+	}
+
+	// Two plans for the same symbol each keep their own ladder
+	first, err := strat.CalculatePosition(context.Background(), params(45000.0, 44000.0))
+	if err != nil {
+		t.Fatalf("CalculatePosition() error = %v", err)
+	}
+	second, err := strat.CalculatePosition(context.Background(), params(40000.0, 39000.0))
+	if err != nil {
+		t.Fatalf("CalculatePosition() error = %v", err)
+	}
+
+	set, err := strat.PlanOrders(first, strategy.StopOrderConfig{})
+	if err != nil {
+		t.Fatalf("PlanOrders() error = %v", err)
+	}
+	if got := set.Entries[2].Price; math.Abs(got-44100.0) > 1e-6 {
+		t.Errorf("first plan deepest leg = %.4f, want 44100 after a later plan", got)
+	}
+	for i, entry := range set.Entries {
+		if entry.ClientOrderID == "" {
+			t.Errorf("Entries[%d] has no ClientOrderID", i)
+		}
+	}
+
+	set, err = strat.PlanOrders(second, strategy.StopOrderConfig{})
+	if err != nil {
+		t.Fatalf("PlanOrders() error = %v", err)
+	}
+	if got := set.Entries[2].Price; math.Abs(got-39200.0) > 1e-6 {
+		t.Errorf("second plan deepest leg = %.4f, want 39200", got)
+	}
+
+	// Plans from another strategy carry no ladder to recover
+	other := *first
+	other.StrategyName = "risk-ratio"
+	if _, err := strat.PlanOrders(&other, strategy.StopOrderConfig{}); err == nil {
+		t.Error("PlanOrders() error = nil, want error for a foreign plan")
+	}
+}
+
+func TestPlanOrders_MaxOrders(t *testing.T) {
+	// 1 market + 4 limit legs, 1 stop, 1 TP
+	strat := New(2.0, 20, 0.5, 1.0, 1.5, 2.0)
+	plan, err := strat.CalculatePosition(context.Background(), strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           types.SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44000.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+	})
+	if err != nil {
+		t.Fatalf("CalculatePosition() error = %v", err)
+	}
+
+	if _, err := strat.PlanOrders(plan, strategy.StopOrderConfig{}, strategy.WithMaxOrders(7)); err != nil {
+		t.Errorf("PlanOrders() at the cap error = %v", err)
+	}
+	if _, err := strat.PlanOrders(plan, strategy.StopOrderConfig{}, strategy.WithMaxOrders(6)); !errors.Is(err, strategy.ErrTooManyOrders) {
+		t.Errorf("PlanOrders() over the cap error = %v, want ErrTooManyOrders", err)
+	}
+}
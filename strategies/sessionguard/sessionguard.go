@@ -0,0 +1,141 @@
+package sessionguard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agatticelli/strategy-go"
+)
+
+// ErrOutsideSession is returned by CalculatePosition outside every allowed window
+var ErrOutsideSession = fmt.Errorf("%w: outside trading session", strategy.ErrNoTrade)
+
+// day is the length of the daily session cycle
+const day = 24 * time.Hour
+
+// Window is a daily UTC time range, as offsets from midnight. Start is
+// inclusive and End exclusive; an End before Start wraps past midnight
+// (e.g. 22:00-02:00).
+type Window struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// contains reports whether offset since midnight falls inside the window
+func (w Window) contains(offset time.Duration) bool {
+	if w.Start <= w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// String formats the window as "HH:MM-HH:MM"
+func (w Window) String() string {
+	return fmt.Sprintf("%s-%s", clock(w.Start), clock(w.End))
+}
+
+// clock formats an offset since midnight as HH:MM
+func clock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+// SessionGuardStrategy wraps another strategy and refuses to plan positions
+// outside the allowed daily UTC windows. All other callbacks are forwarded
+// unchanged, so open positions keep being managed after the session ends.
+type SessionGuardStrategy struct {
+	inner   strategy.Strategy
+	windows []Window
+	now     func() time.Time
+}
+
+// Option configures a SessionGuardStrategy
+type Option func(*SessionGuardStrategy)
+
+// WithClock replaces time.Now as the source of the current time
+func WithClock(now func() time.Time) Option {
+	return func(s *SessionGuardStrategy) {
+		s.now = now
+	}
+}
+
+// New wraps inner so plans are only produced inside windows
+func New(inner strategy.Strategy, windows []Window, opts ...Option) *SessionGuardStrategy {
+	s := &SessionGuardStrategy{
+		inner:   inner,
+		windows: append([]Window(nil), windows...),
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Name returns the wrapped strategy name
+func (s *SessionGuardStrategy) Name() string {
+	return s.inner.Name()
+}
+
+// Description returns a human-readable description
+func (s *SessionGuardStrategy) Description() string {
+	sessions := make([]string, len(s.windows))
+	for i, w := range s.windows {
+		sessions[i] = w.String()
+	}
+	return fmt.Sprintf("%s (sessions %s UTC)", s.inner.Description(), strings.Join(sessions, ", "))
+}
+
+// ValidateParams validates the windows and the wrapped strategy's params
+func (s *SessionGuardStrategy) ValidateParams(params strategy.StrategyParams) error {
+	if len(s.windows) == 0 {
+		return fmt.Errorf("at least one trading session window is required")
+	}
+	for i, w := range s.windows {
+		if w.Start < 0 || w.Start >= day || w.End < 0 || w.End >= day {
+			return fmt.Errorf("session window %d (%s) must lie within a single day", i+1, w)
+		}
+		if w.Start == w.End {
+			return fmt.Errorf("session window %d (%s) is empty", i+1, w)
+		}
+	}
+	return s.inner.ValidateParams(params)
+}
+
+// Parameters returns the wrapped strategy's parameters
+func (s *SessionGuardStrategy) Parameters() []strategy.ParamSpec {
+	return strategy.Parameters(s.inner)
+}
+
+// CalculatePosition errors with ErrOutsideSession when the current UTC time
+// is outside every window, and otherwise forwards to the wrapped strategy
+func (s *SessionGuardStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	if err := s.ValidateParams(params.Params); err != nil {
+		return nil, fmt.Errorf("invalid strategy config: %w", err)
+	}
+
+	now := s.now().UTC()
+	offset := now.Sub(now.Truncate(day))
+	for _, w := range s.windows {
+		if w.contains(offset) {
+			return s.inner.CalculatePosition(ctx, params)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s UTC", ErrOutsideSession, now.Format("15:04"))
+}
+
+// OnPositionOpened forwards to the wrapped strategy
+func (s *SessionGuardStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	return s.inner.OnPositionOpened(ctx, position)
+}
+
+// OnPriceUpdate forwards to the wrapped strategy
+func (s *SessionGuardStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	return s.inner.OnPriceUpdate(ctx, position, currentPrice)
+}
+
+// ShouldClose forwards to the wrapped strategy
+func (s *SessionGuardStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	return s.inner.ShouldClose(ctx, position, currentPrice)
+}
@@ -0,0 +1,141 @@
+package sessionguard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/strategy-go"
+	"github.com/agatticelli/strategy-go/strategies/riskratio"
+	"github.com/agatticelli/trading-common-types"
+)
+
+func testParams() strategy.PositionParams {
+	return strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           types.SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44500.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+	}
+}
+
+// fixedClock returns a clock stuck at hh:mm UTC
+func fixedClock(hh, mm int) func() time.Time {
+	return func() time.Time {
+		return time.Date(2025, 1, 2, hh, mm, 0, 0, time.UTC)
+	}
+}
+
+func TestName(t *testing.T) {
+	strat := New(riskratio.New(2.0), []Window{{Start: 8 * time.Hour, End: 12 * time.Hour}, {Start: 13*time.Hour + 30*time.Minute, End: 20 * time.Hour}})
+	if name := strat.Name(); name != "risk-ratio" {
+		t.Errorf("Name() = %q, want %q", name, "risk-ratio")
+	}
+	want := "Fixed risk-reward ratio strategy (2.0:1) (sessions 08:00-12:00, 13:30-20:00 UTC)"
+	if desc := strat.Description(); desc != want {
+		t.Errorf("Description() = %q, want %q", desc, want)
+	}
+}
+
+func TestCalculatePosition(t *testing.T) {
+	london := Window{Start: 8 * time.Hour, End: 12 * time.Hour}
+	overnight := Window{Start: 22 * time.Hour, End: 2 * time.Hour}
+
+	tests := []struct {
+		name    string
+		windows []Window
+		clock   func() time.Time
+		wantErr bool
+	}{
+		{name: "Inside window", windows: []Window{london}, clock: fixedClock(9, 30), wantErr: false},
+		{name: "At window start", windows: []Window{london}, clock: fixedClock(8, 0), wantErr: false},
+		{name: "At window end is outside", windows: []Window{london}, clock: fixedClock(12, 0), wantErr: true},
+		{name: "Before window", windows: []Window{london}, clock: fixedClock(7, 59), wantErr: true},
+		{name: "Overnight window before midnight", windows: []Window{overnight}, clock: fixedClock(23, 0), wantErr: false},
+		{name: "Overnight window after midnight", windows: []Window{overnight}, clock: fixedClock(1, 0), wantErr: false},
+		{name: "Outside overnight window", windows: []Window{overnight}, clock: fixedClock(12, 0), wantErr: true},
+		{name: "Second of two windows", windows: []Window{london, overnight}, clock: fixedClock(22, 15), wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(riskratio.New(2.0), tt.windows, WithClock(tt.clock))
+
+			plan, err := strat.CalculatePosition(context.Background(), testParams())
+			if tt.wantErr {
+				if !errors.Is(err, ErrOutsideSession) {
+					t.Errorf("CalculatePosition() error = %v, want ErrOutsideSession", err)
+				}
+				if plan != nil {
+					t.Error("CalculatePosition() returned a plan alongside the error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v, want nil", err)
+			}
+			if plan.StrategyName != "risk-ratio" {
+				t.Errorf("StrategyName = %q, want %q", plan.StrategyName, "risk-ratio")
+			}
+		})
+	}
+}
+
+func TestCalculatePosition_NonUTCClock(t *testing.T) {
+	// 10:00 in UTC+3 is 07:00 UTC, before the 08:00 UTC window
+	zone := time.FixedZone("UTC+3", 3*60*60)
+	clock := func() time.Time { return time.Date(2025, 1, 2, 10, 0, 0, 0, zone) }
+	strat := New(riskratio.New(2.0), []Window{{Start: 8 * time.Hour, End: 12 * time.Hour}}, WithClock(clock))
+
+	if _, err := strat.CalculatePosition(context.Background(), testParams()); !errors.Is(err, ErrOutsideSession) {
+		t.Errorf("CalculatePosition() error = %v, want ErrOutsideSession", err)
+	}
+}
+
+func TestCalculatePosition_NoTradeVsBadInput(t *testing.T) {
+	london := []Window{{Start: 8 * time.Hour, End: 12 * time.Hour}}
+
+	outside := New(riskratio.New(2.0), london, WithClock(fixedClock(14, 0)))
+	if _, err := outside.CalculatePosition(context.Background(), testParams()); !errors.Is(err, strategy.ErrNoTrade) {
+		t.Errorf("outside session error = %v, want ErrNoTrade", err)
+	}
+
+	badStop := testParams()
+	badStop.StopLoss = 45500.0
+	inside := New(riskratio.New(2.0), london, WithClock(fixedClock(9, 0)))
+	_, err := inside.CalculatePosition(context.Background(), badStop)
+	if err == nil || errors.Is(err, strategy.ErrNoTrade) {
+		t.Errorf("bad stop error = %v, want a non-ErrNoTrade error", err)
+	}
+
+	noWindows := New(riskratio.New(2.0), nil)
+	if _, err := noWindows.CalculatePosition(context.Background(), testParams()); err == nil || errors.Is(err, strategy.ErrNoTrade) {
+		t.Errorf("bad config error = %v, want a non-ErrNoTrade error", err)
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		windows []Window
+		wantErr bool
+	}{
+		{name: "Valid", windows: []Window{{Start: 8 * time.Hour, End: 12 * time.Hour}}, wantErr: false},
+		{name: "Invalid: no windows", windows: nil, wantErr: true},
+		{name: "Invalid: empty window", windows: []Window{{Start: 8 * time.Hour, End: 8 * time.Hour}}, wantErr: true},
+		{name: "Invalid: end past a day", windows: []Window{{Start: 8 * time.Hour, End: 25 * time.Hour}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(riskratio.New(2.0), tt.windows).ValidateParams(strategy.StrategyParams{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
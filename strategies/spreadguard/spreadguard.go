@@ -0,0 +1,123 @@
+package spreadguard
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agatticelli/strategy-go"
+)
+
+// SpreadBpsParam is the PositionParams.Params key holding the current
+// bid-ask spread in basis points of the mid price (float)
+const SpreadBpsParam = "spreadBps"
+
+// ErrSpreadTooWide is returned by CalculatePosition when the spread exceeds the max
+var ErrSpreadTooWide = fmt.Errorf("%w: spread too wide", strategy.ErrNoTrade)
+
+// SpreadGuardStrategy wraps another strategy and refuses to plan positions
+// while the bid-ask spread is wider than maxSpreadBps, since sizing off the
+// mid of an illiquid book misstates the real entry. The spreadBps param is
+// required, so a feed that drops it fails closed. All other callbacks are
+// forwarded unchanged.
+type SpreadGuardStrategy struct {
+	inner        strategy.Strategy
+	maxSpreadBps float64
+	allowMissing bool
+}
+
+// Option configures a SpreadGuardStrategy
+type Option func(*SpreadGuardStrategy)
+
+// WithAllowMissingSpread lets plans without a spreadBps param through
+// unchecked, for callers that knowingly have no spread feed
+func WithAllowMissingSpread() Option {
+	return func(s *SpreadGuardStrategy) {
+		s.allowMissing = true
+	}
+}
+
+// New wraps inner with a max-spread limit in basis points
+func New(inner strategy.Strategy, maxSpreadBps float64, opts ...Option) *SpreadGuardStrategy {
+	s := &SpreadGuardStrategy{
+		inner:        inner,
+		maxSpreadBps: maxSpreadBps,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Name returns the wrapped strategy name
+func (s *SpreadGuardStrategy) Name() string {
+	return s.inner.Name()
+}
+
+// Description returns a human-readable description
+func (s *SpreadGuardStrategy) Description() string {
+	return fmt.Sprintf("%s (max spread %g bps)", s.inner.Description(), s.maxSpreadBps)
+}
+
+// ValidateParams validates the spreadBps param and the wrapped strategy's params
+func (s *SpreadGuardStrategy) ValidateParams(params strategy.StrategyParams) error {
+	spread, ok, err := params.Float(SpreadBpsParam)
+	if err != nil {
+		return err
+	}
+	if !ok && !s.allowMissing {
+		return fmt.Errorf("%s param is required", SpreadBpsParam)
+	}
+	if spread < 0 {
+		return fmt.Errorf("spread must not be negative, got %.2f bps", spread)
+	}
+	return s.inner.ValidateParams(params)
+}
+
+// Parameters returns the wrapped strategy's parameters plus spreadBps
+func (s *SpreadGuardStrategy) Parameters() []strategy.ParamSpec {
+	specs := append([]strategy.ParamSpec(nil), strategy.Parameters(s.inner)...)
+	return append(specs, strategy.ParamSpec{
+		Name:        SpreadBpsParam,
+		Type:        strategy.ParamTypeFloat,
+		Description: "Current bid-ask spread in basis points of mid",
+		Required:    !s.allowMissing,
+		Min:         strategy.Bound(0),
+	})
+}
+
+// CalculatePosition errors with ErrSpreadTooWide when Params["spreadBps"] is
+// above the max. A missing spreadBps param is a validation error unless
+// WithAllowMissingSpread is given, in which case the check is skipped.
+func (s *SpreadGuardStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	spread, ok, err := strategy.StrategyParams(params.Params).Float(SpreadBpsParam)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if !ok && !s.allowMissing {
+		return nil, fmt.Errorf("validation failed: %s param is required", SpreadBpsParam)
+	}
+	if spread < 0 {
+		return nil, fmt.Errorf("validation failed: spread must not be negative, got %.2f bps", spread)
+	}
+
+	if ok && spread > s.maxSpreadBps {
+		return nil, fmt.Errorf("%w: %g bps, max %g bps", ErrSpreadTooWide, spread, s.maxSpreadBps)
+	}
+
+	return s.inner.CalculatePosition(ctx, params)
+}
+
+// OnPositionOpened forwards to the wrapped strategy
+func (s *SpreadGuardStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	return s.inner.OnPositionOpened(ctx, position)
+}
+
+// OnPriceUpdate forwards to the wrapped strategy
+func (s *SpreadGuardStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	return s.inner.OnPriceUpdate(ctx, position, currentPrice)
+}
+
+// ShouldClose forwards to the wrapped strategy
+func (s *SpreadGuardStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	return s.inner.ShouldClose(ctx, position, currentPrice)
+}
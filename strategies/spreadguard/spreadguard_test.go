@@ -0,0 +1,133 @@
+package spreadguard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/strategy-go"
+	"github.com/agatticelli/strategy-go/strategies/riskratio"
+	"github.com/agatticelli/trading-common-types"
+)
+
+func testParams(spreadBps interface{}) strategy.PositionParams {
+	params := strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           types.SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44500.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+	}
+	if spreadBps != nil {
+		params.Params = map[string]interface{}{SpreadBpsParam: spreadBps}
+	}
+	return params
+}
+
+func TestName(t *testing.T) {
+	strat := New(riskratio.New(2.0), 5)
+	if name := strat.Name(); name != "risk-ratio" {
+		t.Errorf("Name() = %q, want %q", name, "risk-ratio")
+	}
+	want := "Fixed risk-reward ratio strategy (2.0:1) (max spread 5 bps)"
+	if desc := strat.Description(); desc != want {
+		t.Errorf("Description() = %q, want %q", desc, want)
+	}
+}
+
+func TestCalculatePosition(t *testing.T) {
+	tests := []struct {
+		name      string
+		spreadBps interface{}
+		wantErr   error
+	}{
+		{name: "Below limit", spreadBps: 2.5, wantErr: nil},
+		{name: "At limit", spreadBps: 5.0, wantErr: nil},
+		{name: "Above limit", spreadBps: 5.1, wantErr: ErrSpreadTooWide},
+		{name: "Integer spread above limit", spreadBps: 12, wantErr: ErrSpreadTooWide},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(riskratio.New(2.0), 5)
+
+			plan, err := strat.CalculatePosition(context.Background(), testParams(tt.spreadBps))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !errors.Is(err, strategy.ErrNoTrade) {
+					t.Errorf("CalculatePosition() error = %v, want %v wrapping ErrNoTrade", err, tt.wantErr)
+				}
+				if plan != nil {
+					t.Error("CalculatePosition() returned a plan alongside the error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v, want nil", err)
+			}
+			if plan.StrategyName != "risk-ratio" {
+				t.Errorf("StrategyName = %q, want %q", plan.StrategyName, "risk-ratio")
+			}
+		})
+	}
+}
+
+func TestCalculatePosition_InvalidParam(t *testing.T) {
+	strat := New(riskratio.New(2.0), 5)
+
+	for _, spread := range []interface{}{"wide", -1.0} {
+		_, err := strat.CalculatePosition(context.Background(), testParams(spread))
+		if err == nil {
+			t.Errorf("CalculatePosition(spreadBps=%v) error = nil, want error", spread)
+		}
+		if errors.Is(err, ErrSpreadTooWide) || errors.Is(err, strategy.ErrNoTrade) {
+			t.Errorf("CalculatePosition(spreadBps=%v) error = %v, should not be a no-trade decision", spread, err)
+		}
+	}
+}
+
+func TestCalculatePosition_MissingSpread(t *testing.T) {
+	// A feed that drops the field must not let every trade through
+	strat := New(riskratio.New(2.0), 5)
+	plan, err := strat.CalculatePosition(context.Background(), testParams(nil))
+	if err == nil {
+		t.Fatalf("CalculatePosition() error = nil, want error for missing %s", SpreadBpsParam)
+	}
+	if errors.Is(err, strategy.ErrNoTrade) {
+		t.Errorf("CalculatePosition() error = %v, should be a validation error, not a no-trade decision", err)
+	}
+	if plan != nil {
+		t.Error("CalculatePosition() returned a plan alongside the error")
+	}
+	if err := strat.ValidateParams(strategy.StrategyParams{}); err == nil {
+		t.Error("ValidateParams() error = nil, want error for missing spreadBps")
+	}
+
+	// Failing open is opt-in
+	open := New(riskratio.New(2.0), 5, WithAllowMissingSpread())
+	if _, err := open.CalculatePosition(context.Background(), testParams(nil)); err != nil {
+		t.Errorf("CalculatePosition() with WithAllowMissingSpread error = %v, want nil", err)
+	}
+	if err := open.ValidateParams(strategy.StrategyParams{}); err != nil {
+		t.Errorf("ValidateParams() with WithAllowMissingSpread error = %v, want nil", err)
+	}
+	if _, err := open.CalculatePosition(context.Background(), testParams(12.0)); !errors.Is(err, ErrSpreadTooWide) {
+		t.Errorf("CalculatePosition() with WithAllowMissingSpread error = %v, want ErrSpreadTooWide for a wide spread", err)
+	}
+}
+
+func TestParameters(t *testing.T) {
+	specs := New(riskratio.New(2.0), 5).Parameters()
+	want := len(riskratio.New(2.0).Parameters()) + 1
+	if len(specs) != want {
+		t.Fatalf("len(Parameters()) = %d, want %d", len(specs), want)
+	}
+	if last := specs[want-1]; last.Name != SpreadBpsParam || last.Type != strategy.ParamTypeFloat || !last.Required {
+		t.Errorf("last spec = %+v, want required float %q spec", last, SpreadBpsParam)
+	}
+	if spec := New(riskratio.New(2.0), 5, WithAllowMissingSpread()).Parameters()[want-1]; spec.Required {
+		t.Errorf("spec with WithAllowMissingSpread = %+v, want optional", spec)
+	}
+}
@@ -0,0 +1,221 @@
+package stagedstop
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/agatticelli/calculator-go"
+	"github.com/agatticelli/strategy-go"
+)
+
+// StagedStopStrategy takes a partial profit at a first TP and then tightens
+// the stop for the remaining runner.
+//
+// Parameters:
+//   - firstRR: RR ratio of the first (partial) take profit
+//   - firstPercent: percentage of the position closed at the first TP
+//   - finalRR: RR ratio of the final take profit
+//   - stopAfterRR: where the stop moves once the first TP is crossed,
+//     in R from entry (0 = break-even, 0.5 = lock in half the risk)
+//
+// Example:
+//
+//	strat := New(1.0, 50, 3.0, 0)
+//	plan, err := strat.CalculatePosition(ctx, params)
+type StagedStopStrategy struct {
+	strategy.StatefulStrategy[stageState]
+
+	calculator   *calculator.Calculator
+	firstRR      float64
+	firstPercent float64
+	finalRR      float64
+	stopAfterRR  float64
+}
+
+// stageState tracks a single symbol's stop tiers
+type stageState struct {
+	side        strategy.Side
+	firstTP     float64
+	finalTP     float64
+	stops       []float64 // Stop tiers, loosest first
+	activeStage int       // Index into stops of the stop currently in force
+}
+
+// New creates a new staged-stop strategy
+func New(firstRR, firstPercent, finalRR, stopAfterRR float64) *StagedStopStrategy {
+	return &StagedStopStrategy{
+		calculator:   calculator.New(strategy.DefaultMaxLeverage),
+		firstRR:      firstRR,
+		firstPercent: firstPercent,
+		finalRR:      finalRR,
+		stopAfterRR:  stopAfterRR,
+	}
+}
+
+// Name returns the strategy name
+func (s *StagedStopStrategy) Name() string {
+	return "staged-stop"
+}
+
+// Description returns a human-readable description
+func (s *StagedStopStrategy) Description() string {
+	return fmt.Sprintf("Staged stop strategy (%.0f%% at %s:1, rest at %s:1, stop to %.1fR after first TP)",
+		s.firstPercent, strategy.FormatRatio(s.firstRR), strategy.FormatRatio(s.finalRR), s.stopAfterRR)
+}
+
+// ValidateParams validates strategy parameters
+func (s *StagedStopStrategy) ValidateParams(params strategy.StrategyParams) error {
+	if s.firstPercent <= 0 || s.firstPercent >= 100 {
+		return fmt.Errorf("first TP percentage must be between 0 and 100, got %.2f", s.firstPercent)
+	}
+	if s.firstRR <= 0 || s.finalRR <= s.firstRR {
+		return fmt.Errorf("final RR (%.2f) must be greater than first RR (%.2f) and both positive", s.finalRR, s.firstRR)
+	}
+	if s.stopAfterRR < 0 || s.stopAfterRR >= s.firstRR {
+		return fmt.Errorf("stop after first TP (%.2fR) must be in [0, %.2fR)", s.stopAfterRR, s.firstRR)
+	}
+	return nil
+}
+
+// CalculatePosition calculates position size, leverage, both TPs and the stop tiers
+func (s *StagedStopStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	if err := s.ValidateParams(params.Params); err != nil {
+		return nil, fmt.Errorf("invalid strategy config: %w", err)
+	}
+
+	// Validate inputs
+	if err := strategy.ValidateSide(params.Side); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.calculator.ValidateInputs(params.Side, params.EntryPrice, params.StopLoss, params.RiskPercent, params.AccountBalance); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	size := s.calculator.CalculateSize(
+		params.AccountBalance,
+		params.RiskPercent,
+		params.EntryPrice,
+		params.StopLoss,
+		params.Side,
+	)
+
+	leverage := s.calculator.CalculateLeverage(
+		size,
+		params.EntryPrice,
+		params.AccountBalance,
+		strategy.EffectiveMaxLeverage(params.MaxLeverage, strategy.DefaultMaxLeverage),
+	)
+
+	firstTP := s.calculator.CalculateRRTakeProfit(params.EntryPrice, params.StopLoss, s.firstRR, params.Side)
+	finalTP := s.calculator.CalculateRRTakeProfit(params.EntryPrice, params.StopLoss, s.finalRR, params.Side)
+	// A stop at N R from entry is the same formula as a TP at N R
+	stagedStop := s.calculator.CalculateRRTakeProfit(params.EntryPrice, params.StopLoss, s.stopAfterRR, params.Side)
+
+	s.Set(params.Symbol, stageState{
+		side:    params.Side,
+		firstTP: firstTP,
+		finalTP: finalTP,
+		stops:   []float64{params.StopLoss, stagedStop},
+	})
+
+	return &strategy.PositionPlan{
+		Symbol:     params.Symbol,
+		Side:       params.Side,
+		Size:       size,
+		EntryPrice: params.EntryPrice,
+		Leverage:   leverage,
+		StopLoss: &strategy.StopLossLevel{
+			Price: params.StopLoss,
+			Type:  strategy.StopLossTypeFixed,
+		},
+		TakeProfits: []*strategy.TakeProfitLevel{
+			{
+				Price:      firstTP,
+				Percentage: s.firstPercent,
+				Type:       strategy.TakeProfitTypeLimit,
+			},
+			{
+				Price:      finalTP,
+				Percentage: 100 - s.firstPercent,
+				Type:       strategy.TakeProfitTypeLimit,
+			},
+		},
+		RiskAmount:    strategy.RiskAmount(params.AccountBalance, params.RiskPercent),
+		RiskPercent:   params.RiskPercent,
+		NotionalValue: size * params.EntryPrice,
+		StrategyName:  s.Name(),
+		Timestamp:     time.Now().UTC(),
+	}, nil
+}
+
+// StopLevels returns the stop tiers planned for symbol, loosest first.
+// PositionPlan only carries the initial stop; later tiers are applied through
+// OnPriceUpdate.
+func (s *StagedStopStrategy) StopLevels(symbol string) []*strategy.StopLossLevel {
+	st, ok := s.Lookup(symbol)
+	if !ok {
+		return nil
+	}
+	levels := make([]*strategy.StopLossLevel, len(st.stops))
+	for i, price := range st.stops {
+		levels[i] = &strategy.StopLossLevel{Price: price, Type: strategy.StopLossTypeFixed}
+	}
+	return levels
+}
+
+// OnPositionOpened callback after position is opened
+func (s *StagedStopStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	// Tiers are set up by CalculatePosition
+	return nil
+}
+
+// OnPriceUpdate tightens the stop once price crosses the first TP
+func (s *StagedStopStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	st, ok := s.Lookup(position.Symbol)
+	if !ok || st.activeStage >= len(st.stops)-1 {
+		// Untracked position or already at the last tier
+		return &strategy.StrategyAction{Type: strategy.ActionTypeNone}, nil
+	}
+
+	crossed := currentPrice >= st.firstTP
+	if st.side == strategy.SideShort {
+		crossed = currentPrice <= st.firstTP
+	}
+	if !crossed {
+		return &strategy.StrategyAction{Type: strategy.ActionTypeNone}, nil
+	}
+
+	action := &strategy.StrategyAction{Type: strategy.ActionTypeNone}
+	s.Update(position.Symbol, func() stageState { return st }, func(cur *stageState) {
+		// Another goroutine may have advanced the stage meanwhile
+		if cur.activeStage >= len(cur.stops)-1 {
+			return
+		}
+		cur.activeStage++
+		action = &strategy.StrategyAction{
+			Type:     strategy.ActionTypeAdjustSL,
+			NewPrice: cur.stops[cur.activeStage],
+		}
+	})
+	return action, nil
+}
+
+// ShouldClose leaves closing to the TP/SL orders, but drops the symbol's
+// tiers once price reaches the active stop or the final TP so a reopened
+// position doesn't inherit them
+func (s *StagedStopStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	st, ok := s.Lookup(position.Symbol)
+	if !ok {
+		return false, ""
+	}
+	stop := st.stops[st.activeStage]
+	closed := currentPrice <= stop || currentPrice >= st.finalTP
+	if st.side == strategy.SideShort {
+		closed = currentPrice >= stop || currentPrice <= st.finalTP
+	}
+	if closed {
+		s.Clear(position.Symbol)
+	}
+	return false, ""
+}
@@ -0,0 +1,271 @@
+package stagedstop
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/strategy-go"
+	"github.com/agatticelli/trading-common-types"
+)
+
+func TestName(t *testing.T) {
+	strat := New(1.0, 50, 3.0, 0)
+	if name := strat.Name(); name != "staged-stop" {
+		t.Errorf("Name() = %q, want %q", name, "staged-stop")
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		strat   *StagedStopStrategy
+		wantErr bool
+	}{
+		{name: "Valid break-even config", strat: New(1.0, 50, 3.0, 0), wantErr: false},
+		{name: "Valid lock-in config", strat: New(1.5, 30, 3.0, 0.5), wantErr: false},
+		{name: "Invalid: first percent 100", strat: New(1.0, 100, 3.0, 0), wantErr: true},
+		{name: "Invalid: final RR below first", strat: New(2.0, 50, 1.0, 0), wantErr: true},
+		{name: "Invalid: staged stop beyond first TP", strat: New(1.0, 50, 3.0, 1.0), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.strat.ValidateParams(strategy.StrategyParams{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalculatePosition(t *testing.T) {
+	strat := New(1.0, 50, 3.0, 0.5)
+
+	plan, err := strat.CalculatePosition(context.Background(), strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           types.SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44500.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+	})
+	if err != nil {
+		t.Fatalf("CalculatePosition() error = %v, want nil", err)
+	}
+
+	if len(plan.TakeProfits) != 2 {
+		t.Fatalf("len(TakeProfits) = %d, want 2", len(plan.TakeProfits))
+	}
+	if math.Abs(plan.TakeProfits[0].Price-45500.0) > 0.01 || plan.TakeProfits[0].Percentage != 50 {
+		t.Errorf("TP1 = %.2f@%.0f%%, want 45500.00@50%%", plan.TakeProfits[0].Price, plan.TakeProfits[0].Percentage)
+	}
+	if math.Abs(plan.TakeProfits[1].Price-46500.0) > 0.01 || plan.TakeProfits[1].Percentage != 50 {
+		t.Errorf("TP2 = %.2f@%.0f%%, want 46500.00@50%%", plan.TakeProfits[1].Price, plan.TakeProfits[1].Percentage)
+	}
+	if plan.Timestamp.Location() != time.UTC {
+		t.Errorf("Timestamp location = %v, want UTC", plan.Timestamp.Location())
+	}
+
+	stops := strat.StopLevels("BTC-USDT")
+	if len(stops) != 2 {
+		t.Fatalf("len(StopLevels) = %d, want 2", len(stops))
+	}
+	if stops[0].Price != 44500.0 {
+		t.Errorf("initial stop = %.2f, want 44500.00", stops[0].Price)
+	}
+	if math.Abs(stops[1].Price-45250.0) > 0.01 {
+		t.Errorf("staged stop = %.2f, want 45250.00", stops[1].Price)
+	}
+}
+
+func TestOnPriceUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		side      strategy.Side
+		entry     float64
+		stopLoss  float64
+		prices    []float64
+		wantTypes []strategy.ActionType
+		wantStop  float64
+	}{
+		{
+			name:     "LONG tightens only after first TP",
+			side:     types.SideLong,
+			entry:    45000.0,
+			stopLoss: 44500.0,
+			prices:   []float64{45200.0, 45499.0, 45500.0, 46000.0},
+			wantTypes: []strategy.ActionType{
+				types.ActionTypeNone,
+				types.ActionTypeNone,
+				types.ActionTypeAdjustSL,
+				types.ActionTypeNone, // Already at the last tier
+			},
+			wantStop: 45000.0, // break-even
+		},
+		{
+			name:     "SHORT tightens only after first TP",
+			side:     types.SideShort,
+			entry:    3000.0,
+			stopLoss: 3100.0,
+			prices:   []float64{2950.0, 2900.0, 2850.0},
+			wantTypes: []strategy.ActionType{
+				types.ActionTypeNone,
+				types.ActionTypeAdjustSL,
+				types.ActionTypeNone,
+			},
+			wantStop: 3000.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(1.0, 50, 3.0, 0)
+			ctx := context.Background()
+
+			_, err := strat.CalculatePosition(ctx, strategy.PositionParams{
+				Symbol:         "BTC-USDT",
+				Side:           tt.side,
+				EntryPrice:     tt.entry,
+				StopLoss:       tt.stopLoss,
+				AccountBalance: 1000.0,
+				RiskPercent:    2.0,
+				MaxLeverage:    125,
+			})
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v", err)
+			}
+
+			position := &strategy.Position{Symbol: "BTC-USDT", Side: tt.side, Size: 0.1, EntryPrice: tt.entry}
+			for i, price := range tt.prices {
+				action, err := strat.OnPriceUpdate(ctx, position, price)
+				if err != nil {
+					t.Fatalf("OnPriceUpdate(%.2f) error = %v", price, err)
+				}
+				if action.Type != tt.wantTypes[i] {
+					t.Errorf("update %d at %.2f: Action.Type = %v, want %v", i, price, action.Type, tt.wantTypes[i])
+				}
+				if action.Type == types.ActionTypeAdjustSL && math.Abs(action.NewPrice-tt.wantStop) > 0.01 {
+					t.Errorf("update %d: NewPrice = %.2f, want %.2f", i, action.NewPrice, tt.wantStop)
+				}
+			}
+		})
+	}
+}
+
+func TestOnPriceUpdate_Untracked(t *testing.T) {
+	strat := New(1.0, 50, 3.0, 0)
+	position := &strategy.Position{Symbol: "ETH-USDT", Side: types.SideLong, EntryPrice: 3000.0}
+
+	action, err := strat.OnPriceUpdate(context.Background(), position, 5000.0)
+	if err != nil {
+		t.Fatalf("OnPriceUpdate() error = %v", err)
+	}
+	if action.Type != types.ActionTypeNone {
+		t.Errorf("Action.Type = %v, want %v", action.Type, types.ActionTypeNone)
+	}
+}
+
+func TestShouldClose_ClearsOnStop(t *testing.T) {
+	strat := New(1.0, 50, 3.0, 0)
+	ctx := context.Background()
+
+	_, err := strat.CalculatePosition(ctx, strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           types.SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44500.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+	})
+	if err != nil {
+		t.Fatalf("CalculatePosition() error = %v", err)
+	}
+
+	position := &strategy.Position{Symbol: "BTC-USDT", Side: types.SideLong, Size: 0.04, EntryPrice: 45000.0}
+	if _, err := strat.OnPriceUpdate(ctx, position, 45500.0); err != nil { // Stop to break-even
+		t.Fatalf("OnPriceUpdate() error = %v", err)
+	}
+	if shouldClose, _ := strat.ShouldClose(ctx, position, 45100.0); shouldClose {
+		t.Error("ShouldClose() = true, want the SL order to close")
+	}
+	if _, ok := strat.Lookup("BTC-USDT"); !ok {
+		t.Fatal("tiers dropped above the break-even stop")
+	}
+	strat.ShouldClose(ctx, position, 45000.0)
+	if _, ok := strat.Lookup("BTC-USDT"); ok {
+		t.Fatal("tiers still tracked after the stop was reached")
+	}
+
+	// A position reopened without a new plan must not inherit the old tiers
+	action, err := strat.OnPriceUpdate(ctx, position, 45500.0)
+	if err != nil || action.Type != types.ActionTypeNone {
+		t.Errorf("OnPriceUpdate() after reopen = %+v, %v, want no action", action, err)
+	}
+}
+
+func TestShouldClose_ClearsOnFinalTP(t *testing.T) {
+	strat := New(1.0, 50, 3.0, 0)
+	ctx := context.Background()
+
+	_, err := strat.CalculatePosition(ctx, strategy.PositionParams{
+		Symbol:         "ETH-USDT",
+		Side:           types.SideShort,
+		EntryPrice:     3000.0,
+		StopLoss:       3100.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+	})
+	if err != nil {
+		t.Fatalf("CalculatePosition() error = %v", err)
+	}
+
+	position := &strategy.Position{Symbol: "ETH-USDT", Side: types.SideShort, Size: 0.2, EntryPrice: 3000.0}
+	strat.ShouldClose(ctx, position, 2750.0)
+	if _, ok := strat.Lookup("ETH-USDT"); !ok {
+		t.Fatal("tiers dropped before the final TP")
+	}
+	strat.ShouldClose(ctx, position, 2700.0) // 3R
+	if _, ok := strat.Lookup("ETH-USDT"); ok {
+		t.Error("tiers still tracked after the final TP was reached")
+	}
+}
+
+func TestCalculatePosition_LeverageCap(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxLeverage  int
+		wantLeverage int
+	}{
+		{name: "Params above strategy cap", maxLeverage: 200, wantLeverage: strategy.DefaultMaxLeverage},
+		{name: "Params below strategy cap", maxLeverage: 10, wantLeverage: 10},
+		{name: "Params unset", maxLeverage: 0, wantLeverage: strategy.DefaultMaxLeverage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(1.0, 50, 3.0, 0)
+
+			// 1-point stop needs 900x, so the cap always binds
+			plan, err := strat.CalculatePosition(context.Background(), strategy.PositionParams{
+				Symbol:         "BTC-USDT",
+				Side:           types.SideLong,
+				EntryPrice:     45000.0,
+				StopLoss:       44999.0,
+				AccountBalance: 1000.0,
+				RiskPercent:    2.0,
+				MaxLeverage:    tt.maxLeverage,
+			})
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v", err)
+			}
+			if plan.Leverage != tt.wantLeverage {
+				t.Errorf("Leverage = %d, want %d", plan.Leverage, tt.wantLeverage)
+			}
+		})
+	}
+}
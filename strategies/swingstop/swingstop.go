@@ -0,0 +1,148 @@
+package swingstop
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agatticelli/strategy-go"
+)
+
+const (
+	// SwingLowsParam is the PositionParams.Params key holding recent swing
+	// low prices (list of floats), used to place LONG stops
+	SwingLowsParam = "swingLows"
+
+	// SwingHighsParam is the PositionParams.Params key holding recent swing
+	// high prices (list of floats), used to place SHORT stops
+	SwingHighsParam = "swingHighs"
+)
+
+// SwingStopStrategy wraps another strategy and replaces the caller's stop
+// loss with one just beyond the nearest swing level: below the highest
+// swing low under entry for LONG, above the lowest swing high over entry for
+// SHORT. The wrapped strategy sizes from that stop, so structure decides the
+// stop distance and the dollar risk per trade stays the same.
+type SwingStopStrategy struct {
+	inner         strategy.Strategy
+	bufferPercent float64 // Distance beyond the swing level in % of its price
+}
+
+// New wraps inner with a swing-level stop placed bufferPercent beyond the
+// chosen level
+func New(inner strategy.Strategy, bufferPercent float64) *SwingStopStrategy {
+	return &SwingStopStrategy{
+		inner:         inner,
+		bufferPercent: bufferPercent,
+	}
+}
+
+// StopPrice returns the stop bufferPercent beyond the swing level nearest
+// to entry on its losing side. Levels on the wrong side of entry are
+// ignored; it fails when none is left or the buffered stop crosses entry.
+func StopPrice(side strategy.Side, entry float64, levels []float64, bufferPercent float64) (float64, error) {
+	var nearest float64
+	found := false
+	for _, level := range levels {
+		if level <= 0 {
+			continue
+		}
+		switch side {
+		case strategy.SideLong:
+			if level < entry && (!found || level > nearest) {
+				nearest, found = level, true
+			}
+		case strategy.SideShort:
+			if level > entry && (!found || level < nearest) {
+				nearest, found = level, true
+			}
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("no swing level on the losing side of entry %v for %s", entry, side)
+	}
+
+	stop := nearest * (1 - bufferPercent/100)
+	if side == strategy.SideShort {
+		stop = nearest * (1 + bufferPercent/100)
+	}
+	if err := strategy.ValidateStopLoss(side, entry, stop, strategy.DefaultPriceEpsilon); err != nil {
+		return 0, err
+	}
+	return stop, nil
+}
+
+// Name returns the wrapped strategy name
+func (s *SwingStopStrategy) Name() string {
+	return s.inner.Name()
+}
+
+// Description returns a human-readable description
+func (s *SwingStopStrategy) Description() string {
+	return fmt.Sprintf("%s (swing stop, %.2f%% buffer)", s.inner.Description(), s.bufferPercent)
+}
+
+// ValidateParams validates the swing level params and the wrapped strategy's params
+func (s *SwingStopStrategy) ValidateParams(params strategy.StrategyParams) error {
+	if s.bufferPercent < 0 || s.bufferPercent >= 100 {
+		return fmt.Errorf("buffer must be in [0, 100)%%, got %.2f%%", s.bufferPercent)
+	}
+	for _, key := range []string{SwingLowsParam, SwingHighsParam} {
+		if _, _, err := params.Floats(key); err != nil {
+			return err
+		}
+	}
+	return s.inner.ValidateParams(params)
+}
+
+// Parameters returns the wrapped strategy's parameters. The swing level
+// lists have no ParamType and are documented on their keys instead.
+func (s *SwingStopStrategy) Parameters() []strategy.ParamSpec {
+	return strategy.Parameters(s.inner)
+}
+
+// CalculatePosition overrides params.StopLoss with the swing-level stop and
+// delegates sizing to the wrapped strategy
+func (s *SwingStopStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	if err := strategy.ValidateSide(params.Side); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	key := SwingLowsParam
+	if params.Side == strategy.SideShort {
+		key = SwingHighsParam
+	}
+
+	levels, ok, err := strategy.StrategyParams(params.Params).Floats(key)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("validation failed: %s param is required for %s", key, params.Side)
+	}
+	if s.bufferPercent < 0 || s.bufferPercent >= 100 {
+		return nil, fmt.Errorf("validation failed: buffer must be in [0, 100)%%, got %.2f%%", s.bufferPercent)
+	}
+
+	stop, err := StopPrice(params.Side, params.EntryPrice, levels, s.bufferPercent)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	params.StopLoss = stop
+	return s.inner.CalculatePosition(ctx, params)
+}
+
+// OnPositionOpened forwards to the wrapped strategy
+func (s *SwingStopStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	return s.inner.OnPositionOpened(ctx, position)
+}
+
+// OnPriceUpdate forwards to the wrapped strategy
+func (s *SwingStopStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	return s.inner.OnPriceUpdate(ctx, position, currentPrice)
+}
+
+// ShouldClose forwards to the wrapped strategy
+func (s *SwingStopStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	return s.inner.ShouldClose(ctx, position, currentPrice)
+}
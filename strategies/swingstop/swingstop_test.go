@@ -0,0 +1,120 @@
+package swingstop
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/strategy-go"
+	"github.com/agatticelli/strategy-go/strategies/riskratio"
+	"github.com/agatticelli/trading-common-types"
+)
+
+func testParams(side types.Side, entry float64, params map[string]interface{}) strategy.PositionParams {
+	return strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           side,
+		EntryPrice:     entry,
+		StopLoss:       entry * 0.999, // Ignored, replaced by the swing stop
+		AccountBalance: 1000.0,
+		RiskPercent:    1.0,
+		MaxLeverage:    125,
+		Params:         params,
+	}
+}
+
+func TestCalculatePosition_NearestSwing(t *testing.T) {
+	tests := []struct {
+		name     string
+		side     types.Side
+		entry    float64
+		params   map[string]interface{}
+		wantStop float64
+	}{
+		{
+			// 45200 is above entry and 44000/43500 are further away
+			name:     "LONG below highest swing low under entry",
+			side:     types.SideLong,
+			entry:    45000.0,
+			params:   map[string]interface{}{SwingLowsParam: []float64{44000, 44600, 45200, 43500}},
+			wantStop: 44600 * 0.999,
+		},
+		{
+			name:     "SHORT above lowest swing high over entry",
+			side:     types.SideShort,
+			entry:    3000.0,
+			params:   map[string]interface{}{SwingHighsParam: []float64{3300, 3100, 2950, 3050}},
+			wantStop: 3050 * 1.001,
+		},
+		{
+			name:  "JSON-decoded levels",
+			side:  types.SideLong,
+			entry: 45000.0,
+			params: map[string]interface{}{
+				SwingLowsParam:  []interface{}{44600.0, 44200.0},
+				SwingHighsParam: []interface{}{45500.0}, // Only used for SHORT
+			},
+			wantStop: 44600 * 0.999,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(riskratio.New(2.0), 0.1)
+
+			plan, err := strat.CalculatePosition(context.Background(), testParams(tt.side, tt.entry, tt.params))
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v", err)
+			}
+			if math.Abs(plan.StopLoss.Price-tt.wantStop) > 1e-6 {
+				t.Errorf("StopLoss = %.4f, want %.4f", plan.StopLoss.Price, tt.wantStop)
+			}
+
+			// Sized off the swing stop, so the dollar risk is unchanged
+			loss := plan.Size * math.Abs(plan.EntryPrice-plan.StopLoss.Price)
+			if math.Abs(loss-10.0) > 1e-6 {
+				t.Errorf("loss at stop = %.4f, want 10.00", loss)
+			}
+		})
+	}
+}
+
+func TestCalculatePosition_InvalidSwings(t *testing.T) {
+	tests := []struct {
+		name    string
+		side    types.Side
+		params  map[string]interface{}
+		wantErr string
+	}{
+		{name: "Missing", side: types.SideLong, params: nil, wantErr: "swingLows param is required"},
+		{name: "Only highs for LONG", side: types.SideLong, params: map[string]interface{}{SwingHighsParam: []float64{46000}}, wantErr: "swingLows"},
+		{name: "All lows above entry", side: types.SideLong, params: map[string]interface{}{SwingLowsParam: []float64{45100, 46000}}, wantErr: "no swing level"},
+		{name: "All highs below entry", side: types.SideShort, params: map[string]interface{}{SwingHighsParam: []float64{44000}}, wantErr: "no swing level"},
+		{name: "Wrong type", side: types.SideLong, params: map[string]interface{}{SwingLowsParam: 44600.0}, wantErr: "list of numbers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(riskratio.New(2.0), 0.1)
+
+			_, err := strat.CalculatePosition(context.Background(), testParams(tt.side, 45000.0, tt.params))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CalculatePosition() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	strat := New(riskratio.New(2.0), 0.1)
+	if err := strat.ValidateParams(strategy.StrategyParams{SwingLowsParam: []float64{44600}}); err != nil {
+		t.Errorf("ValidateParams() error = %v, want nil", err)
+	}
+	if err := strat.ValidateParams(strategy.StrategyParams{SwingHighsParam: "high"}); err == nil {
+		t.Error("ValidateParams() error = nil, want error for a non-list swingHighs")
+	}
+	if err := New(riskratio.New(2.0), -1).ValidateParams(nil); err == nil {
+		t.Error("ValidateParams() error = nil, want error for a negative buffer")
+	}
+}
@@ -0,0 +1,103 @@
+package symbolrisk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agatticelli/strategy-go"
+)
+
+// ErrSymbolRiskCap is returned by CalculatePosition when a plan would push the
+// symbol's cumulative session risk over the cap
+var ErrSymbolRiskCap = fmt.Errorf("%w: per-symbol risk cap reached", strategy.ErrNoTrade)
+
+// riskTolerance absorbs float noise when summing risk percentages
+const riskTolerance = 1e-9
+
+// SymbolRiskStrategy wraps another strategy and caps the cumulative risk
+// planned per symbol within a session, so repeated re-entries after stop-outs
+// can't exceed the intended daily limit. Every plan it returns counts as
+// spent; call Reset at the start of each session.
+type SymbolRiskStrategy struct {
+	strategy.StatefulStrategy[float64] // Risk percent spent per symbol
+
+	inner          strategy.Strategy
+	maxRiskPercent float64
+}
+
+// New wraps inner with a per-symbol cumulative risk cap (percent of balance)
+func New(inner strategy.Strategy, maxRiskPercent float64) *SymbolRiskStrategy {
+	return &SymbolRiskStrategy{
+		inner:          inner,
+		maxRiskPercent: maxRiskPercent,
+	}
+}
+
+// Name returns the wrapped strategy name
+func (s *SymbolRiskStrategy) Name() string {
+	return s.inner.Name()
+}
+
+// Description returns a human-readable description
+func (s *SymbolRiskStrategy) Description() string {
+	return fmt.Sprintf("%s (max %.2f%% risk per symbol per session)", s.inner.Description(), s.maxRiskPercent)
+}
+
+// ValidateParams validates the wrapped strategy's params
+func (s *SymbolRiskStrategy) ValidateParams(params strategy.StrategyParams) error {
+	return s.inner.ValidateParams(params)
+}
+
+// Parameters returns the wrapped strategy's parameters
+func (s *SymbolRiskStrategy) Parameters() []strategy.ParamSpec {
+	return strategy.Parameters(s.inner)
+}
+
+// CalculatePosition plans with the wrapped strategy and rejects the plan with
+// ErrSymbolRiskCap if its risk would take the symbol over the session cap
+func (s *SymbolRiskStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	plan, err := s.inner.CalculatePosition(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Update(params.Symbol, func() float64 { return 0 }, func(spent *float64) {
+		if *spent+plan.RiskPercent > s.maxRiskPercent+riskTolerance {
+			err = fmt.Errorf("%w: %s has %.2f%% spent, plan adds %.2f%%, max %.2f%%",
+				ErrSymbolRiskCap, params.Symbol, *spent, plan.RiskPercent, s.maxRiskPercent)
+			return
+		}
+		*spent += plan.RiskPercent
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// SpentRisk returns the risk percent already planned for symbol this session
+func (s *SymbolRiskStrategy) SpentRisk(symbol string) float64 {
+	spent, _ := s.Lookup(symbol)
+	return spent
+}
+
+// Reset starts a new session, forgetting the risk spent on every symbol
+func (s *SymbolRiskStrategy) Reset() {
+	s.ClearAll()
+}
+
+// OnPositionOpened forwards to the wrapped strategy
+func (s *SymbolRiskStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	return s.inner.OnPositionOpened(ctx, position)
+}
+
+// OnPriceUpdate forwards to the wrapped strategy
+func (s *SymbolRiskStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	return s.inner.OnPriceUpdate(ctx, position, currentPrice)
+}
+
+// ShouldClose forwards to the wrapped strategy
+func (s *SymbolRiskStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	return s.inner.ShouldClose(ctx, position, currentPrice)
+}
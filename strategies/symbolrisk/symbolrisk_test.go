@@ -0,0 +1,107 @@
+package symbolrisk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/strategy-go"
+	"github.com/agatticelli/strategy-go/strategies/riskratio"
+	"github.com/agatticelli/trading-common-types"
+)
+
+func testParams(symbol string, riskPercent float64) strategy.PositionParams {
+	return strategy.PositionParams{
+		Symbol:         symbol,
+		Side:           types.SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44500.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    riskPercent,
+		MaxLeverage:    125,
+	}
+}
+
+func TestCalculatePosition_ReEntries(t *testing.T) {
+	tests := []struct {
+		name      string
+		first     float64
+		second    float64
+		wantErr   error
+		wantSpent float64
+	}{
+		{name: "Two entries under cap", first: 1.0, second: 1.5, wantErr: nil, wantSpent: 2.5},
+		{name: "Two entries exactly at cap", first: 1.5, second: 1.5, wantErr: nil, wantSpent: 3.0},
+		{name: "Second entry over cap", first: 2.0, second: 1.5, wantErr: ErrSymbolRiskCap, wantSpent: 2.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(riskratio.New(2.0), 3.0)
+			ctx := context.Background()
+
+			if _, err := strat.CalculatePosition(ctx, testParams("BTC-USDT", tt.first)); err != nil {
+				t.Fatalf("first CalculatePosition() error = %v", err)
+			}
+
+			plan, err := strat.CalculatePosition(ctx, testParams("BTC-USDT", tt.second))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("second CalculatePosition() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && plan != nil {
+				t.Error("CalculatePosition() returned a plan alongside the error")
+			}
+
+			if spent := strat.SpentRisk("BTC-USDT"); spent != tt.wantSpent {
+				t.Errorf("SpentRisk() = %.2f, want %.2f", spent, tt.wantSpent)
+			}
+		})
+	}
+}
+
+func TestCalculatePosition_PerSymbol(t *testing.T) {
+	strat := New(riskratio.New(2.0), 3.0)
+	ctx := context.Background()
+
+	if _, err := strat.CalculatePosition(ctx, testParams("BTC-USDT", 3.0)); err != nil {
+		t.Fatalf("BTC CalculatePosition() error = %v", err)
+	}
+	// Another symbol has its own budget
+	if _, err := strat.CalculatePosition(ctx, testParams("ETH-USDT", 3.0)); err != nil {
+		t.Errorf("ETH CalculatePosition() error = %v, want nil", err)
+	}
+	if _, err := strat.CalculatePosition(ctx, testParams("BTC-USDT", 0.5)); !errors.Is(err, ErrSymbolRiskCap) {
+		t.Errorf("BTC re-entry error = %v, want ErrSymbolRiskCap", err)
+	}
+}
+
+func TestCalculatePosition_InnerErrorNotCounted(t *testing.T) {
+	strat := New(riskratio.New(2.0), 3.0)
+
+	params := testParams("BTC-USDT", 2.0)
+	params.StopLoss = 45500.0 // Wrong side for LONG
+	if _, err := strat.CalculatePosition(context.Background(), params); err == nil {
+		t.Fatal("CalculatePosition() error = nil, want validation error")
+	}
+	if spent := strat.SpentRisk("BTC-USDT"); spent != 0 {
+		t.Errorf("SpentRisk() = %.2f after failed plan, want 0", spent)
+	}
+}
+
+func TestReset(t *testing.T) {
+	strat := New(riskratio.New(2.0), 3.0)
+	ctx := context.Background()
+
+	if _, err := strat.CalculatePosition(ctx, testParams("BTC-USDT", 3.0)); err != nil {
+		t.Fatalf("CalculatePosition() error = %v", err)
+	}
+
+	strat.Reset()
+
+	if spent := strat.SpentRisk("BTC-USDT"); spent != 0 {
+		t.Errorf("SpentRisk() after Reset = %.2f, want 0", spent)
+	}
+	if _, err := strat.CalculatePosition(ctx, testParams("BTC-USDT", 3.0)); err != nil {
+		t.Errorf("CalculatePosition() after Reset error = %v, want nil", err)
+	}
+}
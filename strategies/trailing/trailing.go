@@ -0,0 +1,305 @@
+package trailing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/agatticelli/calculator-go"
+	"github.com/agatticelli/strategy-go"
+)
+
+// ATRParam is the PositionParams.Params key holding the symbol's current
+// average true range in price units (float), required with WithATRMultiplier
+const ATRParam = "atr"
+
+// TrailingStrategy sizes positions like risk-ratio but ratchets the stop loss
+// behind the best price reached since entry
+type TrailingStrategy struct {
+	strategy.StatefulStrategy[positionState]
+
+	calculator    *calculator.Calculator
+	rrRatio       float64 // Initial RR ratio for the take profit
+	trailPercent  float64 // Trailing distance as a fraction (e.g., 0.01 = 1%)
+	atrMultiplier float64 // Trailing distance in ATRs; 0 trails by trailPercent
+	activationRR  float64 // Profit in R before trailing starts; 0 trails from entry
+}
+
+// Option configures a TrailingStrategy
+type Option func(*TrailingStrategy)
+
+// WithATRMultiplier trails the stop multiplier * Params["atr"] behind the best
+// price instead of a fixed percentage, so the trail widens with volatility.
+// The ATR is read once per CalculatePosition; positions the strategy did not
+// plan itself fall back to trailPercent.
+func WithATRMultiplier(multiplier float64) Option {
+	return func(s *TrailingStrategy) {
+		s.atrMultiplier = multiplier
+	}
+}
+
+// positionState tracks a single symbol's trailing stop
+type positionState struct {
+	side       strategy.Side
+	stopLoss   float64 // Current stop loss (0 = unknown)
+	bestPrice  float64 // Highest price for LONG, lowest for SHORT
+	trailDist  float64 // Trailing distance in price units (0 = use trailPercent)
+	activation float64 // Best price at which trailing starts (0 = immediately)
+}
+
+// WithActivationRR holds the stop at its initial price until the best price
+// is rr R in profit, so noise right after entry can't trail it out. The
+// threshold is reported as the plan's StopLoss.ActivationPrice.
+func WithActivationRR(rr float64) Option {
+	return func(s *TrailingStrategy) {
+		s.activationRR = rr
+	}
+}
+
+// New creates a new trailing stop strategy
+func New(rrRatio, trailPercent float64, opts ...Option) *TrailingStrategy {
+	s := &TrailingStrategy{
+		calculator:   calculator.New(strategy.DefaultMaxLeverage),
+		rrRatio:      rrRatio,
+		trailPercent: trailPercent,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Name returns the strategy name
+func (s *TrailingStrategy) Name() string {
+	return "trailing"
+}
+
+// Description returns a human-readable description
+func (s *TrailingStrategy) Description() string {
+	trail := fmt.Sprintf("%.1f%%", s.trailPercent*100)
+	if s.atrMultiplier > 0 {
+		trail = fmt.Sprintf("%gx ATR", s.atrMultiplier)
+	}
+	if s.activationRR > 0 {
+		trail += fmt.Sprintf(" from %sR", strategy.FormatRatio(s.activationRR))
+	}
+	return fmt.Sprintf("Trailing stop strategy (RR: %s:1, Trail: %s)", strategy.FormatRatio(s.rrRatio), trail)
+}
+
+// ValidateParams validates strategy parameters
+func (s *TrailingStrategy) ValidateParams(params strategy.StrategyParams) error {
+	if s.activationRR < 0 {
+		return fmt.Errorf("activation RR must not be negative, got %v", s.activationRR)
+	}
+	_, err := s.atrDistance(params)
+	return err
+}
+
+// Parameters describes the params keys the strategy reads: the atr value
+// when an ATR multiplier is configured, otherwise none
+func (s *TrailingStrategy) Parameters() []strategy.ParamSpec {
+	if s.atrMultiplier <= 0 {
+		return nil
+	}
+	return []strategy.ParamSpec{
+		{
+			Name:         ATRParam,
+			Type:         strategy.ParamTypeFloat,
+			Description:  "Current average true range, scaled by the ATR multiplier into the trailing distance",
+			Required:     true,
+			Min:          strategy.Bound(0),
+			ExclusiveMin: true,
+		},
+	}
+}
+
+// CalculatePosition calculates position size, leverage, and TP/SL
+func (s *TrailingStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	// Validate inputs
+	if err := strategy.ValidateSide(params.Side); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.calculator.ValidateInputs(params.Side, params.EntryPrice, params.StopLoss, params.RiskPercent, params.AccountBalance); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.ValidateParams(params.Params); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	trailDist, _ := s.atrDistance(params.Params)
+	callbackRate := s.trailPercent * 100
+	if trailDist > 0 {
+		callbackRate = trailDist / params.EntryPrice * 100
+	}
+
+	size := s.calculator.CalculateSize(
+		params.AccountBalance,
+		params.RiskPercent,
+		params.EntryPrice,
+		params.StopLoss,
+		params.Side,
+	)
+
+	leverage := s.calculator.CalculateLeverage(
+		size,
+		params.EntryPrice,
+		params.AccountBalance,
+		strategy.EffectiveMaxLeverage(params.MaxLeverage, strategy.DefaultMaxLeverage),
+	)
+
+	tpPrice := s.calculator.CalculateRRTakeProfit(
+		params.EntryPrice,
+		params.StopLoss,
+		s.rrRatio,
+		params.Side,
+	)
+
+	var activation float64
+	if s.activationRR > 0 {
+		activation = s.calculator.CalculateRRTakeProfit(params.EntryPrice, params.StopLoss, s.activationRR, params.Side)
+	}
+
+	// Remember the initial stop so trailing only ever tightens it
+	s.Set(params.Symbol, positionState{
+		side:       params.Side,
+		stopLoss:   params.StopLoss,
+		bestPrice:  params.EntryPrice,
+		trailDist:  trailDist,
+		activation: activation,
+	})
+
+	return &strategy.PositionPlan{
+		Symbol:     params.Symbol,
+		Side:       params.Side,
+		Size:       size,
+		EntryPrice: params.EntryPrice,
+		Leverage:   leverage,
+		StopLoss: &strategy.StopLossLevel{
+			Price:           params.StopLoss,
+			Type:            strategy.StopLossTypeTrailing,
+			ActivationPrice: activation,
+			CallbackRate:    callbackRate,
+		},
+		TakeProfits: []*strategy.TakeProfitLevel{
+			{
+				Price:      tpPrice,
+				Percentage: 100,
+				Type:       strategy.TakeProfitTypeLimit,
+			},
+		},
+		RiskAmount:    strategy.RiskAmount(params.AccountBalance, params.RiskPercent),
+		RiskPercent:   params.RiskPercent,
+		NotionalValue: size * params.EntryPrice,
+		StrategyName:  s.Name(),
+		Timestamp:     time.Now().UTC(),
+	}, nil
+}
+
+// OnPositionOpened starts tracking the best price from the actual entry
+func (s *TrailingStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	s.Update(position.Symbol, initState(position), func(st *positionState) {
+		st.side = position.Side
+		st.bestPrice = position.EntryPrice
+	})
+	return nil
+}
+
+// OnPriceUpdate moves the stop loss behind the best price, never loosening it
+func (s *TrailingStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	action := &strategy.StrategyAction{Type: strategy.ActionTypeNone}
+
+	s.Update(position.Symbol, initState(position), func(st *positionState) {
+		var newSL float64
+		if st.side == strategy.SideLong {
+			if currentPrice > st.bestPrice {
+				st.bestPrice = currentPrice
+			}
+			newSL = st.bestPrice * (1 - s.trailPercent)
+			if st.trailDist > 0 {
+				newSL = st.bestPrice - st.trailDist
+			}
+			// Hold the initial stop until the activation price is reached
+			if st.bestPrice < st.activation {
+				return
+			}
+			// Only move SL up, never down
+			if newSL <= st.stopLoss {
+				return
+			}
+		} else {
+			if currentPrice < st.bestPrice {
+				st.bestPrice = currentPrice
+			}
+			newSL = st.bestPrice * (1 + s.trailPercent)
+			if st.trailDist > 0 {
+				newSL = st.bestPrice + st.trailDist
+			}
+			if st.activation > 0 && st.bestPrice > st.activation {
+				return
+			}
+			// Only move SL down, never up
+			if st.stopLoss != 0 && newSL >= st.stopLoss {
+				return
+			}
+		}
+
+		st.stopLoss = newSL
+		action = &strategy.StrategyAction{
+			Type:     strategy.ActionTypeAdjustSL,
+			NewPrice: newSL,
+		}
+	})
+
+	return action, nil
+}
+
+// ShouldClose leaves closing to the trailing SL order, but drops the
+// symbol's state once price reaches the stop so a reopened position trails
+// from scratch
+func (s *TrailingStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	st, ok := s.Lookup(position.Symbol)
+	if !ok || st.stopLoss == 0 {
+		return false, ""
+	}
+	stopped := currentPrice <= st.stopLoss
+	if st.side == strategy.SideShort {
+		stopped = currentPrice >= st.stopLoss
+	}
+	if stopped {
+		s.Clear(position.Symbol)
+	}
+	return false, ""
+}
+
+// atrDistance returns atrMultiplier * Params["atr"], or 0 when trailing by
+// percentage
+func (s *TrailingStrategy) atrDistance(params strategy.StrategyParams) (float64, error) {
+	if s.atrMultiplier == 0 {
+		return 0, nil
+	}
+	if s.atrMultiplier < 0 {
+		return 0, fmt.Errorf("ATR multiplier must be positive, got %v", s.atrMultiplier)
+	}
+
+	atr, ok, err := params.Float(ATRParam)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, fmt.Errorf("%s param is required", ATRParam)
+	}
+	if atr <= 0 {
+		return 0, fmt.Errorf("ATR must be positive, got %v", atr)
+	}
+	return atr * s.atrMultiplier, nil
+}
+
+// initState seeds tracking for a position the strategy did not plan itself
+func initState(position *strategy.Position) func() positionState {
+	return func() positionState {
+		return positionState{
+			side:      position.Side,
+			bestPrice: position.EntryPrice,
+		}
+	}
+}
@@ -0,0 +1,527 @@
+package trailing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/strategy-go"
+	"github.com/agatticelli/trading-common-types"
+)
+
+func TestName(t *testing.T) {
+	strat := New(2.0, 0.01)
+	if name := strat.Name(); name != "trailing" {
+		t.Errorf("Name() = %q, want %q", name, "trailing")
+	}
+}
+
+func TestDescription(t *testing.T) {
+	strat := New(2.0, 0.01)
+	want := "Trailing stop strategy (RR: 2.0:1, Trail: 1.0%)"
+	if desc := strat.Description(); desc != want {
+		t.Errorf("Description() = %q, want %q", desc, want)
+	}
+}
+
+func TestCalculatePosition(t *testing.T) {
+	strat := New(2.0, 0.01)
+	ctx := context.Background()
+
+	plan, err := strat.CalculatePosition(ctx, strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           types.SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44500.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+	})
+	if err != nil {
+		t.Fatalf("CalculatePosition() error = %v, want nil", err)
+	}
+
+	if math.Abs(plan.Size-0.04) > 0.0001 {
+		t.Errorf("Size = %.4f, want 0.0400", plan.Size)
+	}
+	if plan.StopLoss == nil {
+		t.Fatal("StopLoss is nil")
+	}
+	if plan.StopLoss.Type != types.StopLossTypeTrailing {
+		t.Errorf("StopLoss.Type = %v, want %v", plan.StopLoss.Type, types.StopLossTypeTrailing)
+	}
+	if plan.StopLoss.CallbackRate != 1.0 {
+		t.Errorf("StopLoss.CallbackRate = %.2f, want 1.00", plan.StopLoss.CallbackRate)
+	}
+	if len(plan.TakeProfits) != 1 || math.Abs(plan.TakeProfits[0].Price-46000.0) > 0.01 {
+		t.Errorf("TakeProfits = %+v, want single TP at 46000", plan.TakeProfits)
+	}
+	if plan.Timestamp.Location() != time.UTC {
+		t.Errorf("Timestamp location = %v, want UTC", plan.Timestamp.Location())
+	}
+
+	st, ok := strat.Lookup("BTC-USDT")
+	if !ok {
+		t.Fatal("state not tracked after CalculatePosition")
+	}
+	if st.stopLoss != 44500.0 {
+		t.Errorf("tracked stopLoss = %.2f, want 44500.00", st.stopLoss)
+	}
+}
+
+func TestOnPriceUpdate(t *testing.T) {
+	tests := []struct {
+		name       string
+		side       strategy.Side
+		entry      float64
+		stopLoss   float64
+		prices     []float64
+		wantTypes  []strategy.ActionType
+		wantPrices []float64
+	}{
+		{
+			name:     "LONG ratchets up, never down",
+			side:     types.SideLong,
+			entry:    45000.0,
+			stopLoss: 44500.0,
+			prices:   []float64{45000.0, 46000.0, 45500.0, 47000.0},
+			wantTypes: []strategy.ActionType{
+				types.ActionTypeAdjustSL, // 45000 * 0.99 = 44550 > 44500
+				types.ActionTypeAdjustSL, // 46000 * 0.99 = 45540
+				types.ActionTypeNone,     // pullback keeps 45540
+				types.ActionTypeAdjustSL, // 47000 * 0.99 = 46530
+			},
+			wantPrices: []float64{44550.0, 45540.0, 0, 46530.0},
+		},
+		{
+			name:     "SHORT ratchets down, never up",
+			side:     types.SideShort,
+			entry:    3000.0,
+			stopLoss: 3100.0,
+			prices:   []float64{2900.0, 2950.0, 2800.0},
+			wantTypes: []strategy.ActionType{
+				types.ActionTypeAdjustSL, // 2900 * 1.01 = 2929
+				types.ActionTypeNone,
+				types.ActionTypeAdjustSL, // 2800 * 1.01 = 2828
+			},
+			wantPrices: []float64{2929.0, 0, 2828.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(2.0, 0.01)
+			ctx := context.Background()
+
+			_, err := strat.CalculatePosition(ctx, strategy.PositionParams{
+				Symbol:         "BTC-USDT",
+				Side:           tt.side,
+				EntryPrice:     tt.entry,
+				StopLoss:       tt.stopLoss,
+				AccountBalance: 1000.0,
+				RiskPercent:    2.0,
+				MaxLeverage:    125,
+			})
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v", err)
+			}
+
+			position := &strategy.Position{
+				Symbol:     "BTC-USDT",
+				Side:       tt.side,
+				Size:       0.1,
+				EntryPrice: tt.entry,
+			}
+
+			for i, price := range tt.prices {
+				action, err := strat.OnPriceUpdate(ctx, position, price)
+				if err != nil {
+					t.Fatalf("OnPriceUpdate(%.2f) error = %v", price, err)
+				}
+				if action.Type != tt.wantTypes[i] {
+					t.Errorf("update %d: Action.Type = %v, want %v", i, action.Type, tt.wantTypes[i])
+					continue
+				}
+				if action.Type == types.ActionTypeAdjustSL && math.Abs(action.NewPrice-tt.wantPrices[i]) > 0.01 {
+					t.Errorf("update %d: NewPrice = %.2f, want %.2f", i, action.NewPrice, tt.wantPrices[i])
+				}
+			}
+		})
+	}
+}
+
+func TestClear(t *testing.T) {
+	strat := New(2.0, 0.01)
+	position := &strategy.Position{Symbol: "BTC-USDT", Side: types.SideLong, EntryPrice: 45000.0}
+
+	if err := strat.OnPositionOpened(context.Background(), position); err != nil {
+		t.Fatalf("OnPositionOpened() error = %v", err)
+	}
+	if _, ok := strat.Lookup("BTC-USDT"); !ok {
+		t.Fatal("state not tracked after OnPositionOpened")
+	}
+
+	strat.Clear("BTC-USDT")
+	if _, ok := strat.Lookup("BTC-USDT"); ok {
+		t.Error("state still tracked after Clear")
+	}
+}
+
+func TestShouldClose_ClearsOnStop(t *testing.T) {
+	strat := New(2.0, 0.01)
+	ctx := context.Background()
+	position := &strategy.Position{Symbol: "BTC-USDT", Side: types.SideLong, EntryPrice: 45000.0}
+
+	if err := strat.OnPositionOpened(ctx, position); err != nil {
+		t.Fatalf("OnPositionOpened() error = %v", err)
+	}
+	if _, err := strat.OnPriceUpdate(ctx, position, 47000.0); err != nil { // SL 46530
+		t.Fatalf("OnPriceUpdate() error = %v", err)
+	}
+	if shouldClose, _ := strat.ShouldClose(ctx, position, 46600.0); shouldClose {
+		t.Error("ShouldClose() = true, want the SL order to close")
+	}
+	if _, ok := strat.Lookup("BTC-USDT"); !ok {
+		t.Fatal("state dropped above the stop")
+	}
+	strat.ShouldClose(ctx, position, 46500.0)
+	if _, ok := strat.Lookup("BTC-USDT"); ok {
+		t.Fatal("state still tracked after the stop was reached")
+	}
+
+	// Reopened lower: the old 46530 stop must not block trailing
+	reopened := &strategy.Position{Symbol: "BTC-USDT", Side: types.SideLong, EntryPrice: 40000.0}
+	if err := strat.OnPositionOpened(ctx, reopened); err != nil {
+		t.Fatalf("OnPositionOpened() error = %v", err)
+	}
+	action, err := strat.OnPriceUpdate(ctx, reopened, 40000.0)
+	if err != nil {
+		t.Fatalf("OnPriceUpdate() error = %v", err)
+	}
+	if action.Type != types.ActionTypeAdjustSL || math.Abs(action.NewPrice-39600.0) > 0.01 {
+		t.Errorf("OnPriceUpdate() after reopen = %+v, want SL 39600", action)
+	}
+}
+
+// TestOnPriceUpdate_Concurrent is meant to be run with -race
+func TestOnPriceUpdate_Concurrent(t *testing.T) {
+	strat := New(2.0, 0.01)
+	ctx := context.Background()
+
+	const symbols = 8
+	const updates = 200
+
+	var wg sync.WaitGroup
+	for i := 0; i < symbols; i++ {
+		position := &strategy.Position{
+			Symbol:     fmt.Sprintf("SYM%d-USDT", i),
+			Side:       types.SideLong,
+			Size:       1.0,
+			EntryPrice: 100.0,
+		}
+		if err := strat.OnPositionOpened(ctx, position); err != nil {
+			t.Fatalf("OnPositionOpened() error = %v", err)
+		}
+
+		// Two goroutines per symbol so the same state is contended too
+		for g := 0; g < 2; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < updates; j++ {
+					if _, err := strat.OnPriceUpdate(ctx, position, 100.0+float64(j)); err != nil {
+						t.Errorf("OnPriceUpdate() error = %v", err)
+						return
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	for i := 0; i < symbols; i++ {
+		st, ok := strat.Lookup(fmt.Sprintf("SYM%d-USDT", i))
+		if !ok {
+			t.Fatalf("SYM%d-USDT state missing", i)
+		}
+		if st.bestPrice != 100.0+updates-1 {
+			t.Errorf("SYM%d-USDT bestPrice = %.2f, want %.2f", i, st.bestPrice, 100.0+updates-1)
+		}
+	}
+}
+
+func TestCalculatePosition_LeverageCap(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxLeverage  int
+		wantLeverage int
+	}{
+		{name: "Params above strategy cap", maxLeverage: 200, wantLeverage: strategy.DefaultMaxLeverage},
+		{name: "Params below strategy cap", maxLeverage: 10, wantLeverage: 10},
+		{name: "Params unset", maxLeverage: 0, wantLeverage: strategy.DefaultMaxLeverage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(2.0, 0.01)
+
+			// 1-point stop needs 900x, so the cap always binds
+			plan, err := strat.CalculatePosition(context.Background(), strategy.PositionParams{
+				Symbol:         "BTC-USDT",
+				Side:           types.SideLong,
+				EntryPrice:     45000.0,
+				StopLoss:       44999.0,
+				AccountBalance: 1000.0,
+				RiskPercent:    2.0,
+				MaxLeverage:    tt.maxLeverage,
+			})
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v", err)
+			}
+			if plan.Leverage != tt.wantLeverage {
+				t.Errorf("Leverage = %d, want %d", plan.Leverage, tt.wantLeverage)
+			}
+		})
+	}
+}
+
+func TestCalculatePosition_UnknownSide(t *testing.T) {
+	for _, side := range []strategy.Side{"", "long", "short"} {
+		t.Run(fmt.Sprintf("%q", side), func(t *testing.T) {
+			strat := New(2.0, 0.01)
+
+			// Stop above entry would be a valid SHORT; it must not be read as one
+			plan, err := strat.CalculatePosition(context.Background(), strategy.PositionParams{
+				Symbol:         "BTC-USDT",
+				Side:           side,
+				EntryPrice:     45000.0,
+				StopLoss:       45500.0,
+				AccountBalance: 1000.0,
+				RiskPercent:    2.0,
+				MaxLeverage:    125,
+			})
+			if !errors.Is(err, strategy.ErrInvalidSide) {
+				t.Fatalf("CalculatePosition() error = %v, want ErrInvalidSide", err)
+			}
+			if plan != nil {
+				t.Errorf("CalculatePosition() plan = %+v, want nil", plan)
+			}
+			if _, ok := strat.Lookup("BTC-USDT"); ok {
+				t.Error("rejected plan left trailing state behind")
+			}
+		})
+	}
+}
+
+func TestOnPriceUpdate_ATRTrail(t *testing.T) {
+	// Same LONG path at two volatilities: the trail sits 2 ATRs behind the best price
+	prices := []float64{45000.0, 46000.0, 45500.0, 47000.0}
+
+	tests := []struct {
+		name       string
+		atr        float64
+		wantTypes  []strategy.ActionType
+		wantPrices []float64
+		wantRate   float64
+	}{
+		{
+			name: "Calm market, 100 ATR",
+			atr:  100.0,
+			wantTypes: []strategy.ActionType{
+				types.ActionTypeAdjustSL, // 45000 - 200 = 44800 > 44500
+				types.ActionTypeAdjustSL, // 46000 - 200
+				types.ActionTypeNone,
+				types.ActionTypeAdjustSL, // 47000 - 200
+			},
+			wantPrices: []float64{44800.0, 45800.0, 0, 46800.0},
+			wantRate:   200.0 / 45000.0 * 100,
+		},
+		{
+			name: "Volatile market, 300 ATR",
+			atr:  300.0,
+			wantTypes: []strategy.ActionType{
+				types.ActionTypeNone,     // 45000 - 600 = 44400 is looser than 44500
+				types.ActionTypeAdjustSL, // 46000 - 600
+				types.ActionTypeNone,
+				types.ActionTypeAdjustSL, // 47000 - 600
+			},
+			wantPrices: []float64{0, 45400.0, 0, 46400.0},
+			wantRate:   600.0 / 45000.0 * 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(2.0, 0.01, WithATRMultiplier(2))
+			ctx := context.Background()
+
+			plan, err := strat.CalculatePosition(ctx, strategy.PositionParams{
+				Symbol:         "BTC-USDT",
+				Side:           types.SideLong,
+				EntryPrice:     45000.0,
+				StopLoss:       44500.0,
+				AccountBalance: 1000.0,
+				RiskPercent:    2.0,
+				MaxLeverage:    125,
+				Params:         strategy.StrategyParams{ATRParam: tt.atr},
+			})
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v", err)
+			}
+			if math.Abs(plan.StopLoss.CallbackRate-tt.wantRate) > 1e-9 {
+				t.Errorf("StopLoss.CallbackRate = %.4f, want %.4f", plan.StopLoss.CallbackRate, tt.wantRate)
+			}
+
+			position := &strategy.Position{Symbol: "BTC-USDT", Side: types.SideLong, Size: 0.04, EntryPrice: 45000.0}
+			for i, price := range prices {
+				action, err := strat.OnPriceUpdate(ctx, position, price)
+				if err != nil {
+					t.Fatalf("OnPriceUpdate(%.2f) error = %v", price, err)
+				}
+				if action.Type != tt.wantTypes[i] {
+					t.Errorf("update %d: Action.Type = %v, want %v", i, action.Type, tt.wantTypes[i])
+					continue
+				}
+				if action.Type == types.ActionTypeAdjustSL && math.Abs(action.NewPrice-tt.wantPrices[i]) > 0.01 {
+					t.Errorf("update %d: NewPrice = %.2f, want %.2f", i, action.NewPrice, tt.wantPrices[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCalculatePosition_ATRParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  strategy.StrategyParams
+		wantErr bool
+	}{
+		{name: "Integer ATR", params: strategy.StrategyParams{ATRParam: 150}, wantErr: false},
+		{name: "Invalid: missing ATR", params: nil, wantErr: true},
+		{name: "Invalid: zero ATR", params: strategy.StrategyParams{ATRParam: 0.0}, wantErr: true},
+		{name: "Invalid: non-numeric ATR", params: strategy.StrategyParams{ATRParam: "wide"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(2.0, 0.01, WithATRMultiplier(1.5))
+			_, err := strat.CalculatePosition(context.Background(), strategy.PositionParams{
+				Symbol:         "BTC-USDT",
+				Side:           types.SideLong,
+				EntryPrice:     45000.0,
+				StopLoss:       44500.0,
+				AccountBalance: 1000.0,
+				RiskPercent:    2.0,
+				MaxLeverage:    125,
+				Params:         tt.params,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CalculatePosition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	want := "Trailing stop strategy (RR: 2.0:1, Trail: 1.5x ATR)"
+	if desc := New(2.0, 0.01, WithATRMultiplier(1.5)).Description(); desc != want {
+		t.Errorf("Description() = %q, want %q", desc, want)
+	}
+}
+
+func TestOnPriceUpdate_ActivationRR(t *testing.T) {
+	tests := []struct {
+		name           string
+		side           strategy.Side
+		entry          float64
+		stopLoss       float64
+		wantActivation float64
+		prices         []float64
+		wantTypes      []strategy.ActionType
+		wantPrices     []float64
+	}{
+		{
+			name:           "LONG activates at 1R",
+			side:           types.SideLong,
+			entry:          45000.0,
+			stopLoss:       44500.0,
+			wantActivation: 45500.0,
+			prices:         []float64{45300.0, 45450.0, 45600.0, 45500.0, 46000.0},
+			wantTypes: []strategy.ActionType{
+				types.ActionTypeNone, // 1% trail would be 44847, but not yet activated
+				types.ActionTypeNone,
+				types.ActionTypeAdjustSL, // 45600 * 0.99
+				types.ActionTypeNone,
+				types.ActionTypeAdjustSL, // 46000 * 0.99
+			},
+			wantPrices: []float64{0, 0, 45144.0, 0, 45540.0},
+		},
+		{
+			name:           "SHORT activates at 1R",
+			side:           types.SideShort,
+			entry:          3000.0,
+			stopLoss:       3100.0,
+			wantActivation: 2900.0,
+			prices:         []float64{2950.0, 2910.0, 2890.0, 2950.0, 2850.0},
+			wantTypes: []strategy.ActionType{
+				types.ActionTypeNone, // 1% trail would be 2979.5, but not yet activated
+				types.ActionTypeNone,
+				types.ActionTypeAdjustSL, // 2890 * 1.01
+				types.ActionTypeNone,
+				types.ActionTypeAdjustSL, // 2850 * 1.01
+			},
+			wantPrices: []float64{0, 0, 2918.9, 0, 2878.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(2.0, 0.01, WithActivationRR(1))
+			ctx := context.Background()
+
+			plan, err := strat.CalculatePosition(ctx, strategy.PositionParams{
+				Symbol:         "BTC-USDT",
+				Side:           tt.side,
+				EntryPrice:     tt.entry,
+				StopLoss:       tt.stopLoss,
+				AccountBalance: 1000.0,
+				RiskPercent:    2.0,
+				MaxLeverage:    125,
+			})
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v", err)
+			}
+			if math.Abs(plan.StopLoss.ActivationPrice-tt.wantActivation) > 1e-9 {
+				t.Errorf("StopLoss.ActivationPrice = %.2f, want %.2f", plan.StopLoss.ActivationPrice, tt.wantActivation)
+			}
+
+			position := &strategy.Position{Symbol: "BTC-USDT", Side: tt.side, Size: plan.Size, EntryPrice: tt.entry}
+			for i, price := range tt.prices {
+				action, err := strat.OnPriceUpdate(ctx, position, price)
+				if err != nil {
+					t.Fatalf("OnPriceUpdate(%.2f) error = %v", price, err)
+				}
+				if action.Type != tt.wantTypes[i] {
+					t.Errorf("update %d: Action.Type = %v, want %v", i, action.Type, tt.wantTypes[i])
+					continue
+				}
+				if action.Type == types.ActionTypeAdjustSL && math.Abs(action.NewPrice-tt.wantPrices[i]) > 0.01 {
+					t.Errorf("update %d: NewPrice = %.2f, want %.2f", i, action.NewPrice, tt.wantPrices[i])
+				}
+			}
+		})
+	}
+}
+
+func TestActivationRR_Validation(t *testing.T) {
+	if err := New(2.0, 0.01, WithActivationRR(-1)).ValidateParams(nil); err == nil {
+		t.Error("ValidateParams() error = nil, want error for negative activation RR")
+	}
+
+	want := "Trailing stop strategy (RR: 2.0:1, Trail: 1.0% from 1.5R)"
+	if desc := New(2.0, 0.01, WithActivationRR(1.5)).Description(); desc != want {
+		t.Errorf("Description() = %q, want %q", desc, want)
+	}
+}
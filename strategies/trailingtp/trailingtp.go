@@ -0,0 +1,215 @@
+package trailingtp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/agatticelli/calculator-go"
+	"github.com/agatticelli/strategy-go"
+)
+
+// TrailingTPStrategy sizes like risk-ratio with a fixed stop, but lets
+// winners run: the take profit only starts trailing once price reaches
+// activationRR, and from then on follows the best price at trailRR behind it.
+//
+// Parameters:
+//   - activationRR: R-multiple from entry at which trailing starts
+//   - trailRR: trailing distance behind the best price, in R
+//
+// Example:
+//
+//	// Trail by 0.5R once price reaches 2R
+//	strat := New(2.0, 0.5)
+//	plan, err := strat.CalculatePosition(ctx, params)
+type TrailingTPStrategy struct {
+	strategy.StatefulStrategy[tpState]
+
+	calculator   *calculator.Calculator
+	activationRR float64
+	trailRR      float64
+}
+
+// tpState tracks a single symbol's trailing take profit
+type tpState struct {
+	side       strategy.Side
+	stopLoss   float64 // Fixed stop loss
+	risk       float64 // Stop distance from entry (1R)
+	activation float64 // Price at activationRR
+	active     bool    // Price has reached activation
+	bestPrice  float64 // Best price since activation
+	takeProfit float64 // Current trailing TP (0 before activation)
+}
+
+// New creates a new trailing take-profit strategy
+func New(activationRR, trailRR float64) *TrailingTPStrategy {
+	return &TrailingTPStrategy{
+		calculator:   calculator.New(strategy.DefaultMaxLeverage),
+		activationRR: activationRR,
+		trailRR:      trailRR,
+	}
+}
+
+// Name returns the strategy name
+func (s *TrailingTPStrategy) Name() string {
+	return "trailing-tp"
+}
+
+// Description returns a human-readable description
+func (s *TrailingTPStrategy) Description() string {
+	return fmt.Sprintf("Trailing take-profit strategy (trail %sR after %sR)",
+		strategy.FormatRatio(s.trailRR), strategy.FormatRatio(s.activationRR))
+}
+
+// ValidateParams validates strategy parameters
+func (s *TrailingTPStrategy) ValidateParams(params strategy.StrategyParams) error {
+	if s.activationRR <= 0 {
+		return fmt.Errorf("activation RR must be positive, got %.2f", s.activationRR)
+	}
+	// A trail at or beyond the activation distance could exit at a loss
+	if s.trailRR <= 0 || s.trailRR >= s.activationRR {
+		return fmt.Errorf("trail (%.2fR) must be in (0, %.2fR)", s.trailRR, s.activationRR)
+	}
+	return nil
+}
+
+// CalculatePosition calculates position size, leverage and the trailing TP
+func (s *TrailingTPStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	if err := s.ValidateParams(params.Params); err != nil {
+		return nil, fmt.Errorf("invalid strategy config: %w", err)
+	}
+
+	// Validate inputs
+	if err := strategy.ValidateSide(params.Side); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.calculator.ValidateInputs(params.Side, params.EntryPrice, params.StopLoss, params.RiskPercent, params.AccountBalance); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	size := s.calculator.CalculateSize(
+		params.AccountBalance,
+		params.RiskPercent,
+		params.EntryPrice,
+		params.StopLoss,
+		params.Side,
+	)
+
+	leverage := s.calculator.CalculateLeverage(
+		size,
+		params.EntryPrice,
+		params.AccountBalance,
+		strategy.EffectiveMaxLeverage(params.MaxLeverage, strategy.DefaultMaxLeverage),
+	)
+
+	risk := math.Abs(params.EntryPrice - params.StopLoss)
+	activation := s.calculator.CalculateRRTakeProfit(params.EntryPrice, params.StopLoss, s.activationRR, params.Side)
+
+	s.Set(params.Symbol, tpState{
+		side:       params.Side,
+		stopLoss:   params.StopLoss,
+		risk:       risk,
+		activation: activation,
+	})
+
+	return &strategy.PositionPlan{
+		Symbol:     params.Symbol,
+		Side:       params.Side,
+		Size:       size,
+		EntryPrice: params.EntryPrice,
+		Leverage:   leverage,
+		StopLoss: &strategy.StopLossLevel{
+			Price: params.StopLoss,
+			Type:  strategy.StopLossTypeFixed,
+		},
+		TakeProfits: []*strategy.TakeProfitLevel{
+			{
+				Price:           activation,
+				Percentage:      100,
+				Type:            strategy.TakeProfitTypeTrailing,
+				ActivationPrice: activation,
+				CallbackRate:    s.trailRR * risk / activation * 100,
+			},
+		},
+		RiskAmount:    strategy.RiskAmount(params.AccountBalance, params.RiskPercent),
+		RiskPercent:   params.RiskPercent,
+		NotionalValue: size * params.EntryPrice,
+		StrategyName:  s.Name(),
+		Timestamp:     time.Now().UTC(),
+	}, nil
+}
+
+// OnPositionOpened callback after position is opened
+func (s *TrailingTPStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	// The R distance comes from the plan, so tracking starts in CalculatePosition
+	return nil
+}
+
+// OnPriceUpdate does nothing until price reaches the activation level, then
+// moves the TP to trailRR behind the best price since activation, never
+// loosening it
+func (s *TrailingTPStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	action := &strategy.StrategyAction{Type: strategy.ActionTypeNone}
+	tracked, ok := s.Lookup(position.Symbol)
+	if !ok {
+		// Untracked position
+		return action, nil
+	}
+
+	s.Update(position.Symbol, func() tpState { return tracked }, func(st *tpState) {
+		long := st.side == strategy.SideLong
+		beyond := func(a, b float64) bool {
+			if long {
+				return a > b
+			}
+			return a < b
+		}
+
+		if !st.active {
+			if beyond(st.activation, currentPrice) {
+				return
+			}
+			st.active = true
+			st.bestPrice = currentPrice
+		} else if beyond(currentPrice, st.bestPrice) {
+			st.bestPrice = currentPrice
+		}
+
+		trail := s.trailRR * st.risk
+		newTP := st.bestPrice - trail
+		if !long {
+			newTP = st.bestPrice + trail
+		}
+		if st.takeProfit != 0 && !beyond(newTP, st.takeProfit) {
+			return
+		}
+
+		st.takeProfit = newTP
+		action = &strategy.StrategyAction{
+			Type:     strategy.ActionTypeAdjustTP,
+			NewPrice: newTP,
+		}
+	})
+	return action, nil
+}
+
+// ShouldClose leaves closing to the TP/SL orders, but drops the symbol's
+// state once price pulls back to the trailing TP or reaches the stop so a
+// reopened position starts untriggered
+func (s *TrailingTPStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	st, ok := s.Lookup(position.Symbol)
+	if !ok {
+		return false, ""
+	}
+	// The trailing TP sits behind price on the stop's side, so whichever of
+	// the two is tighter closes the position first
+	closed := currentPrice <= st.stopLoss || (st.takeProfit != 0 && currentPrice <= st.takeProfit)
+	if st.side == strategy.SideShort {
+		closed = currentPrice >= st.stopLoss || (st.takeProfit != 0 && currentPrice >= st.takeProfit)
+	}
+	if closed {
+		s.Clear(position.Symbol)
+	}
+	return false, ""
+}
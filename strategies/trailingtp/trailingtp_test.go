@@ -0,0 +1,214 @@
+package trailingtp
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/agatticelli/strategy-go"
+	"github.com/agatticelli/trading-common-types"
+)
+
+func TestName(t *testing.T) {
+	strat := New(2.0, 0.5)
+	if name := strat.Name(); name != "trailing-tp" {
+		t.Errorf("Name() = %q, want %q", name, "trailing-tp")
+	}
+	want := "Trailing take-profit strategy (trail 0.5R after 2.0R)"
+	if desc := strat.Description(); desc != want {
+		t.Errorf("Description() = %q, want %q", desc, want)
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		strat   *TrailingTPStrategy
+		wantErr bool
+	}{
+		{name: "Valid", strat: New(2.0, 0.5), wantErr: false},
+		{name: "Invalid: zero activation", strat: New(0, 0.5), wantErr: true},
+		{name: "Invalid: zero trail", strat: New(2.0, 0), wantErr: true},
+		{name: "Invalid: trail at activation", strat: New(2.0, 2.0), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.strat.ValidateParams(strategy.StrategyParams{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalculatePosition(t *testing.T) {
+	strat := New(2.0, 0.5)
+
+	plan, err := strat.CalculatePosition(context.Background(), strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           types.SideLong,
+		EntryPrice:     45000.0,
+		StopLoss:       44500.0,
+		AccountBalance: 1000.0,
+		RiskPercent:    2.0,
+		MaxLeverage:    125,
+	})
+	if err != nil {
+		t.Fatalf("CalculatePosition() error = %v, want nil", err)
+	}
+
+	if len(plan.TakeProfits) != 1 {
+		t.Fatalf("len(TakeProfits) = %d, want 1", len(plan.TakeProfits))
+	}
+	tp := plan.TakeProfits[0]
+	if tp.Type != types.TakeProfitTypeTrailing || tp.ActivationPrice != 46000.0 {
+		t.Errorf("TP = %+v, want trailing TP activating at 46000", tp)
+	}
+	// 0.5R = 250 on a 46000 activation
+	if math.Abs(tp.CallbackRate-250.0/46000.0*100) > 1e-9 {
+		t.Errorf("CallbackRate = %v, want %v", tp.CallbackRate, 250.0/46000.0*100)
+	}
+	if plan.StopLoss.Type != types.StopLossTypeFixed || plan.StopLoss.Price != 44500.0 {
+		t.Errorf("StopLoss = %+v, want fixed at 44500", plan.StopLoss)
+	}
+}
+
+func TestOnPriceUpdate(t *testing.T) {
+	tests := []struct {
+		name       string
+		side       strategy.Side
+		stopLoss   float64
+		prices     []float64
+		wantTypes  []strategy.ActionType
+		wantPrices []float64
+	}{
+		{
+			name:     "LONG trails only after 2R",
+			side:     types.SideLong,
+			stopLoss: 44500.0, // 1R = 500, 2R at 46000, trail 250
+			prices:   []float64{45500.0, 45999.0, 46000.0, 46400.0, 46200.0, 46800.0},
+			wantTypes: []strategy.ActionType{
+				types.ActionTypeNone,     // 1R
+				types.ActionTypeNone,     // just below 2R
+				types.ActionTypeAdjustTP, // activates: 46000 - 250
+				types.ActionTypeAdjustTP, // 46400 - 250
+				types.ActionTypeNone,     // pullback keeps 46150
+				types.ActionTypeAdjustTP, // 46800 - 250
+			},
+			wantPrices: []float64{0, 0, 45750.0, 46150.0, 0, 46550.0},
+		},
+		{
+			name:     "SHORT trails only after 2R",
+			side:     types.SideShort,
+			stopLoss: 45500.0, // 2R at 44000
+			prices:   []float64{44100.0, 43900.0, 44050.0, 43500.0},
+			wantTypes: []strategy.ActionType{
+				types.ActionTypeNone,
+				types.ActionTypeAdjustTP, // 43900 + 250
+				types.ActionTypeNone,     // bounce keeps 44150
+				types.ActionTypeAdjustTP, // 43500 + 250
+			},
+			wantPrices: []float64{0, 44150.0, 0, 43750.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(2.0, 0.5)
+			ctx := context.Background()
+
+			_, err := strat.CalculatePosition(ctx, strategy.PositionParams{
+				Symbol:         "BTC-USDT",
+				Side:           tt.side,
+				EntryPrice:     45000.0,
+				StopLoss:       tt.stopLoss,
+				AccountBalance: 1000.0,
+				RiskPercent:    2.0,
+				MaxLeverage:    125,
+			})
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v", err)
+			}
+
+			position := &strategy.Position{Symbol: "BTC-USDT", Side: tt.side, Size: 0.04, EntryPrice: 45000.0}
+			for i, price := range tt.prices {
+				action, err := strat.OnPriceUpdate(ctx, position, price)
+				if err != nil {
+					t.Fatalf("OnPriceUpdate(%.2f) error = %v", price, err)
+				}
+				if action.Type != tt.wantTypes[i] {
+					t.Errorf("update %d: Action.Type = %v, want %v", i, action.Type, tt.wantTypes[i])
+					continue
+				}
+				if action.Type == types.ActionTypeAdjustTP && math.Abs(action.NewPrice-tt.wantPrices[i]) > 1e-6 {
+					t.Errorf("update %d: NewPrice = %.2f, want %.2f", i, action.NewPrice, tt.wantPrices[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOnPriceUpdate_Untracked(t *testing.T) {
+	strat := New(2.0, 0.5)
+	position := &strategy.Position{Symbol: "ETH-USDT", Side: types.SideLong, EntryPrice: 3000.0}
+
+	action, err := strat.OnPriceUpdate(context.Background(), position, 5000.0)
+	if err != nil || action.Type != types.ActionTypeNone {
+		t.Errorf("OnPriceUpdate() = %+v, %v, want no action for untracked symbol", action, err)
+	}
+}
+
+func TestShouldClose_ClearsOnExit(t *testing.T) {
+	tests := []struct {
+		name   string
+		prices []float64 // Updates before the exit
+		exit   float64
+	}{
+		{name: "Trailing TP reached", prices: []float64{46400.0}, exit: 46150.0},
+		{name: "Stop reached before activation", prices: []float64{45500.0}, exit: 44500.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(2.0, 0.5)
+			ctx := context.Background()
+
+			_, err := strat.CalculatePosition(ctx, strategy.PositionParams{
+				Symbol:         "BTC-USDT",
+				Side:           types.SideLong,
+				EntryPrice:     45000.0,
+				StopLoss:       44500.0,
+				AccountBalance: 1000.0,
+				RiskPercent:    2.0,
+				MaxLeverage:    125,
+			})
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v", err)
+			}
+
+			position := &strategy.Position{Symbol: "BTC-USDT", Side: types.SideLong, Size: 0.04, EntryPrice: 45000.0}
+			for _, price := range tt.prices {
+				if _, err := strat.OnPriceUpdate(ctx, position, price); err != nil {
+					t.Fatalf("OnPriceUpdate(%.2f) error = %v", price, err)
+				}
+			}
+			if shouldClose, _ := strat.ShouldClose(ctx, position, tt.exit+1); shouldClose {
+				t.Error("ShouldClose() = true, want the TP/SL orders to close")
+			}
+			if _, ok := strat.Lookup("BTC-USDT"); !ok {
+				t.Fatal("state dropped before the exit")
+			}
+			strat.ShouldClose(ctx, position, tt.exit)
+			if _, ok := strat.Lookup("BTC-USDT"); ok {
+				t.Fatal("state still tracked after the exit was reached")
+			}
+
+			// A position reopened without a new plan must not trail the old TP
+			action, err := strat.OnPriceUpdate(ctx, position, 47000.0)
+			if err != nil || action.Type != types.ActionTypeNone {
+				t.Errorf("OnPriceUpdate() after reopen = %+v, %v, want no action", action, err)
+			}
+		})
+	}
+}
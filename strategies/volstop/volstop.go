@@ -0,0 +1,124 @@
+package volstop
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agatticelli/strategy-go"
+)
+
+// RealizedVolParam is the PositionParams.Params key holding the symbol's
+// recent realized volatility (float, same units as the reference volatility)
+const RealizedVolParam = "realizedVol"
+
+// VolStopStrategy wraps another strategy and replaces the caller's stop loss
+// with one placed baseStopPercent away from entry, scaled by how volatile the
+// market currently is relative to referenceVol. The wrapped strategy sizes
+// from the scaled stop, so a wider stop yields a smaller position and the
+// dollar risk per trade stays the same.
+type VolStopStrategy struct {
+	inner           strategy.Strategy
+	baseStopPercent float64 // Stop distance in % of entry at reference volatility
+	referenceVol    float64 // Volatility at which the multiplier is 1
+}
+
+// New wraps inner with a volatility-scaled stop
+func New(inner strategy.Strategy, baseStopPercent, referenceVol float64) *VolStopStrategy {
+	return &VolStopStrategy{
+		inner:           inner,
+		baseStopPercent: baseStopPercent,
+		referenceVol:    referenceVol,
+	}
+}
+
+// StopPrice returns the stop placed baseStopPercent*volMultiplier percent
+// away from entry, below it for LONG and above it for SHORT
+func StopPrice(side strategy.Side, entry, baseStopPercent, volMultiplier float64) float64 {
+	distance := baseStopPercent * volMultiplier / 100
+	if side == strategy.SideShort {
+		return entry * (1 + distance)
+	}
+	return entry * (1 - distance)
+}
+
+// Name returns the wrapped strategy name
+func (s *VolStopStrategy) Name() string {
+	return s.inner.Name()
+}
+
+// Description returns a human-readable description
+func (s *VolStopStrategy) Description() string {
+	return fmt.Sprintf("%s (vol-scaled %.2f%% stop)", s.inner.Description(), s.baseStopPercent)
+}
+
+// ValidateParams validates the realizedVol param and the wrapped strategy's params
+func (s *VolStopStrategy) ValidateParams(params strategy.StrategyParams) error {
+	if _, err := s.volMultiplier(params); err != nil {
+		return err
+	}
+	return s.inner.ValidateParams(params)
+}
+
+// Parameters returns the wrapped strategy's parameters plus realizedVol
+func (s *VolStopStrategy) Parameters() []strategy.ParamSpec {
+	specs := append([]strategy.ParamSpec(nil), strategy.Parameters(s.inner)...)
+	return append(specs, strategy.ParamSpec{
+		Name:         RealizedVolParam,
+		Type:         strategy.ParamTypeFloat,
+		Description:  "Recent realized volatility used to scale the stop distance",
+		Required:     true,
+		Min:          strategy.Bound(0),
+		ExclusiveMin: true,
+	})
+}
+
+// CalculatePosition overrides params.StopLoss with the volatility-scaled stop
+// and delegates sizing to the wrapped strategy
+func (s *VolStopStrategy) CalculatePosition(ctx context.Context, params strategy.PositionParams) (*strategy.PositionPlan, error) {
+	if err := strategy.ValidateSide(params.Side); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	multiplier, err := s.volMultiplier(params.Params)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	stopPercent := s.baseStopPercent * multiplier
+	if s.baseStopPercent <= 0 || stopPercent >= 100 {
+		return nil, fmt.Errorf("validation failed: scaled stop distance must be in (0, 100)%%, got %.2f%%", stopPercent)
+	}
+
+	params.StopLoss = StopPrice(params.Side, params.EntryPrice, s.baseStopPercent, multiplier)
+	return s.inner.CalculatePosition(ctx, params)
+}
+
+// volMultiplier returns realizedVol / referenceVol
+func (s *VolStopStrategy) volMultiplier(params strategy.StrategyParams) (float64, error) {
+	vol, ok, err := params.Float(RealizedVolParam)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, fmt.Errorf("%s param is required", RealizedVolParam)
+	}
+	if vol <= 0 || s.referenceVol <= 0 {
+		return 0, fmt.Errorf("volatility must be positive, got %v (reference %v)", vol, s.referenceVol)
+	}
+	return vol / s.referenceVol, nil
+}
+
+// OnPositionOpened forwards to the wrapped strategy
+func (s *VolStopStrategy) OnPositionOpened(ctx context.Context, position *strategy.Position) error {
+	return s.inner.OnPositionOpened(ctx, position)
+}
+
+// OnPriceUpdate forwards to the wrapped strategy
+func (s *VolStopStrategy) OnPriceUpdate(ctx context.Context, position *strategy.Position, currentPrice float64) (*strategy.StrategyAction, error) {
+	return s.inner.OnPriceUpdate(ctx, position, currentPrice)
+}
+
+// ShouldClose forwards to the wrapped strategy
+func (s *VolStopStrategy) ShouldClose(ctx context.Context, position *strategy.Position, currentPrice float64) (bool, string) {
+	return s.inner.ShouldClose(ctx, position, currentPrice)
+}
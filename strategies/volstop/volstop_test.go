@@ -0,0 +1,102 @@
+package volstop
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/agatticelli/strategy-go"
+	"github.com/agatticelli/strategy-go/strategies/riskratio"
+	"github.com/agatticelli/trading-common-types"
+)
+
+func testParams(side types.Side, realizedVol interface{}) strategy.PositionParams {
+	params := strategy.PositionParams{
+		Symbol:         "BTC-USDT",
+		Side:           side,
+		EntryPrice:     50000.0,
+		StopLoss:       49000.0, // Ignored, replaced by the scaled stop
+		AccountBalance: 1000.0,
+		RiskPercent:    1.0,
+		MaxLeverage:    125,
+	}
+	if realizedVol != nil {
+		params.Params = map[string]interface{}{RealizedVolParam: realizedVol}
+	}
+	return params
+}
+
+func TestCalculatePosition_LowVsHighVol(t *testing.T) {
+	tests := []struct {
+		name     string
+		side     types.Side
+		vol      float64
+		wantStop float64
+		wantSize float64
+	}{
+		// Reference vol 0.02, base stop 1%
+		{name: "LONG low vol", side: types.SideLong, vol: 0.01, wantStop: 49750, wantSize: 0.04},
+		{name: "LONG reference vol", side: types.SideLong, vol: 0.02, wantStop: 49500, wantSize: 0.02},
+		{name: "LONG high vol", side: types.SideLong, vol: 0.04, wantStop: 49000, wantSize: 0.01},
+		{name: "SHORT low vol", side: types.SideShort, vol: 0.01, wantStop: 50250, wantSize: 0.04},
+		{name: "SHORT high vol", side: types.SideShort, vol: 0.04, wantStop: 51000, wantSize: 0.01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(riskratio.New(2.0), 1.0, 0.02)
+
+			plan, err := strat.CalculatePosition(context.Background(), testParams(tt.side, tt.vol))
+			if err != nil {
+				t.Fatalf("CalculatePosition() error = %v", err)
+			}
+
+			if math.Abs(plan.StopLoss.Price-tt.wantStop) > 1e-6 {
+				t.Errorf("StopLoss = %.4f, want %.4f", plan.StopLoss.Price, tt.wantStop)
+			}
+			if math.Abs(plan.Size-tt.wantSize) > 1e-9 {
+				t.Errorf("Size = %.6f, want %.6f", plan.Size, tt.wantSize)
+			}
+
+			// Dollar risk is the same regardless of volatility
+			loss := plan.Size * math.Abs(plan.EntryPrice-plan.StopLoss.Price)
+			if math.Abs(loss-10.0) > 1e-6 {
+				t.Errorf("loss at stop = %.4f, want 10.00", loss)
+			}
+		})
+	}
+}
+
+func TestCalculatePosition_InvalidVol(t *testing.T) {
+	tests := []struct {
+		name string
+		vol  interface{}
+	}{
+		{name: "Missing", vol: nil},
+		{name: "Zero", vol: 0.0},
+		{name: "Negative", vol: -0.01},
+		{name: "Wrong type", vol: "high"},
+		{name: "Stop past zero", vol: 2.0}, // 1% * 100x = 100%
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strat := New(riskratio.New(2.0), 1.0, 0.02)
+
+			if _, err := strat.CalculatePosition(context.Background(), testParams(types.SideLong, tt.vol)); err == nil {
+				t.Error("CalculatePosition() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParameters(t *testing.T) {
+	specs := New(riskratio.New(2.0), 1.0, 0.02).Parameters()
+	want := len(riskratio.New(2.0).Parameters()) + 1
+	if len(specs) != want {
+		t.Fatalf("len(Parameters()) = %d, want %d", len(specs), want)
+	}
+	if last := specs[want-1]; last.Name != RealizedVolParam || !last.Required {
+		t.Errorf("last spec = %+v, want required %q spec", last, RealizedVolParam)
+	}
+}
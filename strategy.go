@@ -2,8 +2,15 @@ package strategy
 
 import (
 	"context"
+	"errors"
 )
 
+// ErrNoTrade is returned (wrapped) by CalculatePosition when a strategy
+// deliberately declines to trade, e.g. outside its session or at a position
+// cap. It is not a failure: callers should check errors.Is(err, ErrNoTrade)
+// and skip the setup, while any other error means bad input or config.
+var ErrNoTrade = errors.New("no trade")
+
 // Strategy defines the interface all trading strategies must implement
 type Strategy interface {
 	// Name returns the strategy name
@@ -15,7 +22,9 @@ type Strategy interface {
 	// ValidateParams validates strategy parameters before execution
 	ValidateParams(params StrategyParams) error
 
-	// CalculatePosition calculates position size, leverage, TP/SL levels
+	// CalculatePosition calculates position size, leverage, TP/SL levels.
+	// A strategy that declines the setup returns an error wrapping ErrNoTrade,
+	// never a nil plan with a nil error.
 	CalculatePosition(ctx context.Context, params PositionParams) (*PositionPlan, error)
 
 	// OnPositionOpened callback after position is opened
@@ -0,0 +1,78 @@
+package strategy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// quoteAssets are recognized quote currencies for splitting separator-less
+// symbols like BTCUSDT
+var quoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "USD", "EUR", "BTC", "ETH"}
+
+// baseAssets are well-known base currencies used to pick between splits
+// when more than one quote suffix matches, e.g. BNBUSD (BNB-USD, not BN-BUSD)
+var baseAssets = []string{"BTC", "ETH", "BNB", "SOL", "XRP", "ADA", "DOGE", "DOT", "TRX", "LTC", "LINK", "AVAX", "MATIC", "ATOM", "BCH", "XLM", "ETC", "FIL", "NEAR", "APT", "ARB", "OP", "SUI"}
+
+// NormalizeSymbol returns symbol in the canonical BASE-QUOTE form used
+// throughout this package, so "BTCUSDT", "btc/usdt" and "BTC_USDT" all key
+// the same per-symbol state as "BTC-USDT". Input is trimmed and uppercased,
+// "/", "_" and ":" become "-", and a symbol without a separator is split
+// before a known quote asset. When several quote suffixes match (BNBUSD ends
+// in both BUSD and USD), the split whose base is a known asset wins; if that
+// doesn't settle it the symbol is left unsplit rather than guessed. Symbols
+// it can't split are returned trimmed and uppercased.
+func NormalizeSymbol(symbol string) string {
+	s := strings.ToUpper(strings.TrimSpace(symbol))
+	s = strings.NewReplacer("/", "-", "_", "-", ":", "-").Replace(s)
+	if strings.Contains(s, "-") {
+		return s
+	}
+
+	var splits, known []string
+	for _, quote := range quoteAssets {
+		if base, ok := strings.CutSuffix(s, quote); ok && base != "" {
+			split := base + "-" + quote
+			splits = append(splits, split)
+			if isKnownAsset(base) {
+				known = append(known, split)
+			}
+		}
+	}
+	switch {
+	case len(splits) == 1:
+		return splits[0]
+	case len(known) == 1:
+		return known[0]
+	}
+	return s
+}
+
+// isKnownAsset reports whether asset is a listed base or quote asset
+func isKnownAsset(asset string) bool {
+	return slices.Contains(baseAssets, asset) || slices.Contains(quoteAssets, asset)
+}
+
+// ValidateSymbol checks symbol is already in canonical BASE-QUOTE form:
+// two non-empty runs of uppercase letters and digits joined by one "-".
+// Callers that accept free-form input should run NormalizeSymbol first.
+func ValidateSymbol(symbol string) error {
+	base, quote, ok := strings.Cut(symbol, "-")
+	if !ok || !isSymbolPart(base) || !isSymbolPart(quote) {
+		return fmt.Errorf("symbol %q is not in BASE-QUOTE form (e.g. BTC-USDT)", symbol)
+	}
+	return nil
+}
+
+// isSymbolPart reports whether s is a non-empty run of A-Z and 0-9
+func isSymbolPart(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
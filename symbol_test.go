@@ -0,0 +1,65 @@
+package strategy
+
+import "testing"
+
+func TestNormalizeSymbol(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "BTC-USDT", want: "BTC-USDT"},
+		{in: "BTCUSDT", want: "BTC-USDT"},
+		{in: "btc-usdt", want: "BTC-USDT"},
+		{in: "btc/usdt", want: "BTC-USDT"},
+		{in: "BTC_USDT", want: "BTC-USDT"},
+		{in: "  ethusdc ", want: "ETH-USDC"},
+		{in: "BTCUSD", want: "BTC-USD"},
+		{in: "ETHBTC", want: "ETH-BTC"},
+		{in: "BNBUSD", want: "BNB-USD"}, // Not BN-BUSD
+		{in: "DOTUSD", want: "DOT-USD"}, // Not DO-TUSD
+		{in: "ETHBUSD", want: "ETH-BUSD"},
+		{in: "ETHFDUSD", want: "ETH-FDUSD"},
+		{in: "XYBUSD", want: "XYBUSD"}, // XY-BUSD or XYB-USD, neither base known
+		{in: "1000PEPEUSDT", want: "1000PEPE-USDT"},
+		{in: "USDT", want: "USDT"}, // Quote alone has no base to split off
+		{in: "XYZABC", want: "XYZABC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := NormalizeSymbol(tt.in); got != tt.want {
+				t.Errorf("NormalizeSymbol(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSymbol(t *testing.T) {
+	tests := []struct {
+		symbol  string
+		wantErr bool
+	}{
+		{symbol: "BTC-USDT", wantErr: false},
+		{symbol: "1000PEPE-USDT", wantErr: false},
+		{symbol: "BTCUSDT", wantErr: true},
+		{symbol: "btc-usdt", wantErr: true},
+		{symbol: "BTC-", wantErr: true},
+		{symbol: "BTC-USDT-SWAP", wantErr: true},
+		{symbol: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			if err := ValidateSymbol(tt.symbol); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSymbol(%q) error = %v, wantErr %v", tt.symbol, err, tt.wantErr)
+			}
+		})
+	}
+
+	// Every normalizable form validates once normalized
+	for _, in := range []string{"BTCUSDT", "btc/usdt", "eth_usdc"} {
+		if err := ValidateSymbol(NormalizeSymbol(in)); err != nil {
+			t.Errorf("ValidateSymbol(NormalizeSymbol(%q)) error = %v", in, err)
+		}
+	}
+}
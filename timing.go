@@ -0,0 +1,17 @@
+package strategy
+
+import "math"
+
+// EstimateTimeToTarget returns a rough number of bars for price to travel
+// from entry to target, modelling price as a random walk whose per-bar move
+// is atrPerBar. A random walk covers n ATRs in about n² bars, so a target two
+// ATRs away takes ~4 bars. This is for setting expectations, not a forecast.
+// Returns 0 for non-positive prices or ATR, or a target at entry.
+func EstimateTimeToTarget(entry, target, atrPerBar float64) float64 {
+	if entry <= 0 || target <= 0 || atrPerBar <= 0 || math.IsInf(atrPerBar, 0) {
+		return 0
+	}
+
+	atrs := math.Abs(target-entry) / atrPerBar
+	return atrs * atrs
+}
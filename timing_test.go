@@ -0,0 +1,32 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateTimeToTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		entry  float64
+		target float64
+		atr    float64
+		want   float64
+	}{
+		{name: "LONG target two ATRs up", entry: 45000.0, target: 46000.0, atr: 500.0, want: 4.0},
+		{name: "SHORT target two ATRs down", entry: 45000.0, target: 44000.0, atr: 500.0, want: 4.0},
+		{name: "Half an ATR", entry: 100.0, target: 101.0, atr: 2.0, want: 0.25},
+		{name: "Target at entry", entry: 45000.0, target: 45000.0, atr: 500.0, want: 0},
+		{name: "Invalid: zero ATR", entry: 45000.0, target: 46000.0, atr: 0, want: 0},
+		{name: "Invalid: negative ATR", entry: 45000.0, target: 46000.0, atr: -500.0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateTimeToTarget(tt.entry, tt.target, tt.atr)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("EstimateTimeToTarget() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
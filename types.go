@@ -16,6 +16,8 @@ type (
 	ActionType     = types.ActionType
 
 	// Position and Order types
+	// These are aliases, not copies: a broker adapter built on
+	// trading-common-types already produces strategy.Position values
 	Position     = types.Position
 	OrderRequest = types.OrderRequest
 
@@ -0,0 +1,29 @@
+package strategy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/agatticelli/trading-common-types"
+)
+
+// Position is an alias, so broker positions from trading-common-types need
+// no converter. These assignments stop compiling if it ever becomes a copy.
+var (
+	_ Position        = types.Position{}
+	_ types.Position  = Position{}
+	_ *types.Position = (*Position)(nil)
+)
+
+func TestPositionIsBrokerPosition(t *testing.T) {
+	broker := types.Position{Symbol: "BTC-USDT", Side: types.SideLong, Size: 0.04, EntryPrice: 45000.0}
+
+	var p Position = broker
+	var back types.Position = p
+	if !reflect.DeepEqual(back, broker) {
+		t.Errorf("round trip = %+v, want %+v", back, broker)
+	}
+	if reflect.TypeOf(p) != reflect.TypeOf(broker) {
+		t.Errorf("Position is %v, want the same type as %v", reflect.TypeOf(p), reflect.TypeOf(broker))
+	}
+}
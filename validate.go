@@ -0,0 +1,237 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidSide is returned for a Side other than SideLong or SideShort
+var ErrInvalidSide = errors.New("invalid side")
+
+// percentTolerance absorbs float noise when summing TP percentages
+const percentTolerance = 1e-9
+
+// DefaultPriceEpsilon is the relative tolerance below which two prices are
+// treated as equal (1e-9 of entry, i.e. 0.000045 on a 45000 entry)
+const DefaultPriceEpsilon = 1e-9
+
+// pricesEqual reports whether a and b are within epsilon of each other,
+// relative to the larger magnitude
+func pricesEqual(a, b, epsilon float64) bool {
+	return math.Abs(a-b) <= epsilon*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// ValidateSide checks side is exactly SideLong or SideShort. Comparisons
+// elsewhere treat anything that isn't LONG as SHORT, so a malformed side
+// ("long", "") must be rejected before it sizes in the wrong direction.
+func ValidateSide(side Side) error {
+	if side != SideLong && side != SideShort {
+		return fmt.Errorf("%w %q, want %q or %q", ErrInvalidSide, side, SideLong, SideShort)
+	}
+	return nil
+}
+
+// ValidateStopLoss checks the stop is on the losing side of entry (below for
+// LONG, above for SHORT). Stops within epsilon (relative) of entry are always
+// rejected as equal to it, so floating-point noise can't flip the result.
+func ValidateStopLoss(side Side, entry, stopLoss, epsilon float64) error {
+	if pricesEqual(entry, stopLoss, epsilon) {
+		return fmt.Errorf("stop loss %v is effectively equal to entry %v", stopLoss, entry)
+	}
+	if side == SideLong && stopLoss > entry {
+		return fmt.Errorf("stop loss %v must be below entry %v for LONG", stopLoss, entry)
+	}
+	if side == SideShort && stopLoss < entry {
+		return fmt.Errorf("stop loss %v must be above entry %v for SHORT", stopLoss, entry)
+	}
+	return nil
+}
+
+// ValidateTakeProfit checks the take profit is on the profitable side of
+// entry (above for LONG, below for SHORT), with the same epsilon handling as
+// ValidateStopLoss
+func ValidateTakeProfit(side Side, entry, takeProfit, epsilon float64) error {
+	if pricesEqual(entry, takeProfit, epsilon) {
+		return fmt.Errorf("take profit %v is effectively equal to entry %v", takeProfit, entry)
+	}
+	if side == SideLong && takeProfit < entry {
+		return fmt.Errorf("take profit %v must be above entry %v for LONG", takeProfit, entry)
+	}
+	if side == SideShort && takeProfit > entry {
+		return fmt.Errorf("take profit %v must be below entry %v for SHORT", takeProfit, entry)
+	}
+	return nil
+}
+
+// ValidateLimitEntry checks a limit entry rests on the correct side of the
+// current price (at or below it for LONG, at or above it for SHORT). A LONG
+// limit above market would fill immediately as a market order.
+func ValidateLimitEntry(side Side, entry, currentPrice float64) error {
+	if currentPrice <= 0 {
+		return fmt.Errorf("current price must be positive, got %v", currentPrice)
+	}
+	if side == SideLong && entry > currentPrice {
+		return fmt.Errorf("LONG limit entry %v is above current price %v", entry, currentPrice)
+	}
+	if side == SideShort && entry < currentPrice {
+		return fmt.Errorf("SHORT limit entry %v is below current price %v", entry, currentPrice)
+	}
+	return nil
+}
+
+// TargetPriceParam is a fixed take-profit price (float) in
+// PositionParams.Params, for setups whose TP is a known level rather than
+// an RR multiple
+const TargetPriceParam = "targetPrice"
+
+// IsSetupStale reports whether currentPrice has already invalidated a setup
+// before its entry is placed: at or beyond the stop (the trade would open
+// as a loss) or at or past the take profit (no reward left). The TP is read
+// from params.Params[TargetPriceParam] and only checked when set. reason
+// explains a stale result and is empty otherwise.
+func IsSetupStale(params PositionParams, currentPrice float64) (stale bool, reason string) {
+	tp, ok, err := StrategyParams(params.Params).Float(TargetPriceParam)
+	hasTP := ok && err == nil && tp > 0
+
+	switch params.Side {
+	case SideLong:
+		if currentPrice <= params.StopLoss {
+			return true, fmt.Sprintf("price %v is at or below the stop %v", currentPrice, params.StopLoss)
+		}
+		if hasTP && currentPrice >= tp {
+			return true, fmt.Sprintf("price %v is at or above the take profit %v", currentPrice, tp)
+		}
+	case SideShort:
+		if currentPrice >= params.StopLoss {
+			return true, fmt.Sprintf("price %v is at or above the stop %v", currentPrice, params.StopLoss)
+		}
+		if hasTP && currentPrice <= tp {
+			return true, fmt.Sprintf("price %v is at or below the take profit %v", currentPrice, tp)
+		}
+	}
+	return false, ""
+}
+
+// ValidateMargin checks the margin needed to open notional at leverage
+// (notional / leverage) fits within the available balance. It catches plans
+// whose leverage was clamped to the max below what the size requires.
+func ValidateMargin(notional float64, leverage int, balance float64) error {
+	if leverage <= 0 {
+		return fmt.Errorf("leverage must be positive, got %d", leverage)
+	}
+	required := notional / float64(leverage)
+	if required > balance*(1+percentTolerance) {
+		return fmt.Errorf("margin required %.2f at %dx exceeds available balance %.2f; use a wider stop or smaller risk",
+			required, leverage, balance)
+	}
+	return nil
+}
+
+// ValidateTakeProfits checks that take-profit percentages cover the position.
+//
+// Without a runner the percentages must sum to 100. With allowRunner they may
+// sum to less than 100: the uncovered fraction is a runner with no fixed TP
+// that only the (typically trailing) stop loss will close, so the plan must
+// carry a stop. Each percentage must itself be in (0, 100].
+func ValidateTakeProfits(tps []*TakeProfitLevel, allowRunner bool) error {
+	if len(tps) == 0 {
+		if allowRunner {
+			return nil
+		}
+		return fmt.Errorf("at least one take profit is required")
+	}
+
+	total := 0.0
+	for i, tp := range tps {
+		if tp == nil {
+			return fmt.Errorf("take profit %d is nil", i+1)
+		}
+		if !(tp.Percentage > 0) || tp.Percentage > 100 {
+			return fmt.Errorf("take profit %d percentage must be in (0, 100], got %.2f", i+1, tp.Percentage)
+		}
+		total += tp.Percentage
+	}
+
+	if total > 100+percentTolerance {
+		return fmt.Errorf("TP percentages must not exceed 100, got %.2f", total)
+	}
+	if !allowRunner && total < 100-percentTolerance {
+		return fmt.Errorf("TP percentages must sum to 100, got %.2f", total)
+	}
+	return nil
+}
+
+// RunnerPercent returns the percentage of the position not covered by any
+// take profit, which rides until the stop loss closes it
+func RunnerPercent(plan *PositionPlan) float64 {
+	if plan == nil {
+		return 0
+	}
+	total := 0.0
+	for _, tp := range plan.TakeProfits {
+		if tp != nil {
+			total += tp.Percentage
+		}
+	}
+	runner := 100 - total
+	if runner < percentTolerance {
+		return 0
+	}
+	return runner
+}
+
+// finiteField is a named value checked by checkFinite
+type finiteField struct {
+	name  string
+	value float64
+}
+
+// checkFinite returns an error naming the first NaN or infinite field
+func checkFinite(prefix string, fields []finiteField) error {
+	for _, f := range fields {
+		if math.IsNaN(f.value) || math.IsInf(f.value, 0) {
+			return fmt.Errorf("%s%s must be a finite number, got %v", prefix, f.name, f.value)
+		}
+	}
+	return nil
+}
+
+// ValidateFiniteParams rejects NaN or infinite numeric inputs, which pass
+// ordinary range checks because every comparison with NaN is false
+func ValidateFiniteParams(params PositionParams) error {
+	return checkFinite("", []finiteField{
+		{"entry price", params.EntryPrice},
+		{"stop loss", params.StopLoss},
+		{"account balance", params.AccountBalance},
+		{"risk percent", params.RiskPercent},
+	})
+}
+
+// ValidatePlanFinite rejects plans with NaN or infinite prices or amounts,
+// e.g. from overflow on extreme inputs. It allocates nothing on valid plans,
+// since it runs on every CalculatePosition.
+func ValidatePlanFinite(plan *PositionPlan) error {
+	stop := 0.0
+	if plan.StopLoss != nil {
+		stop = plan.StopLoss.Price
+	}
+	if err := checkFinite("plan ", []finiteField{
+		{"size", plan.Size},
+		{"entry price", plan.EntryPrice},
+		{"risk amount", plan.RiskAmount},
+		{"risk percent", plan.RiskPercent},
+		{"notional value", plan.NotionalValue},
+		{"stop loss", stop},
+	}); err != nil {
+		return err
+	}
+
+	// TP names are only formatted once one is found to be invalid
+	for i, tp := range plan.TakeProfits {
+		if tp != nil && (math.IsNaN(tp.Price) || math.IsInf(tp.Price, 0)) {
+			return fmt.Errorf("plan take profit %d must be a finite number, got %v", i+1, tp.Price)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package strategy
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestValidateTakeProfits(t *testing.T) {
+	tests := []struct {
+		name        string
+		percentages []float64
+		allowRunner bool
+		wantErr     bool
+	}{
+		{name: "Single 100% TP", percentages: []float64{100}, wantErr: false},
+		{name: "Three levels summing to 100", percentages: []float64{30, 40, 30}, wantErr: false},
+		{name: "Float noise still sums to 100", percentages: []float64{100.0 / 3, 100.0 / 3, 100.0 / 3}, wantErr: false},
+		{name: "50+30 without runner", percentages: []float64{50, 30}, wantErr: true},
+		{name: "50+30 with 20% runner", percentages: []float64{50, 30}, allowRunner: true, wantErr: false},
+		{name: "Over 100 with runner", percentages: []float64{60, 50}, allowRunner: true, wantErr: true},
+		{name: "No TPs without runner", percentages: nil, wantErr: true},
+		{name: "No TPs, all runner", percentages: nil, allowRunner: true, wantErr: false},
+		{name: "Invalid: 0% level", percentages: []float64{0, 100}, wantErr: true},
+		{name: "Invalid: 0% level with runner", percentages: []float64{0}, allowRunner: true, wantErr: true},
+		{name: "Invalid: negative level offset by another", percentages: []float64{-10, 110}, wantErr: true},
+		{name: "Invalid: 150% level", percentages: []float64{150}, allowRunner: true, wantErr: true},
+		{name: "Invalid: NaN level", percentages: []float64{math.NaN()}, allowRunner: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tps []*TakeProfitLevel
+			for _, pct := range tt.percentages {
+				tps = append(tps, &TakeProfitLevel{Price: 46000.0, Percentage: pct, Type: TakeProfitTypeLimit})
+			}
+
+			err := ValidateTakeProfits(tps, tt.allowRunner)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTakeProfits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTakeProfits_NilLevel(t *testing.T) {
+	tps := []*TakeProfitLevel{
+		{Price: 46000.0, Percentage: 50, Type: TakeProfitTypeLimit},
+		nil,
+	}
+	err := ValidateTakeProfits(tps, true)
+	if err == nil || err.Error() != "take profit 2 is nil" {
+		t.Errorf("ValidateTakeProfits() error = %v, want the 1-based %q", err, "take profit 2 is nil")
+	}
+}
+
+func TestRunnerPercent(t *testing.T) {
+	plan := testPlan()
+	plan.StopLoss.Type = StopLossTypeTrailing
+	plan.TakeProfits = []*TakeProfitLevel{
+		{Price: 45500.0, Percentage: 50, Type: TakeProfitTypeLimit},
+		{Price: 46000.0, Percentage: 30, Type: TakeProfitTypeLimit},
+	}
+
+	if err := ValidateTakeProfits(plan.TakeProfits, true); err != nil {
+		t.Fatalf("ValidateTakeProfits() error = %v", err)
+	}
+	if got := RunnerPercent(plan); math.Abs(got-20) > 1e-9 {
+		t.Errorf("RunnerPercent() = %.2f, want 20.00", got)
+	}
+
+	if got := RunnerPercent(testPlan()); got != 0 {
+		t.Errorf("RunnerPercent() for fully covered plan = %.2f, want 0", got)
+	}
+}
+
+func TestValidateSide(t *testing.T) {
+	tests := []struct {
+		name    string
+		side    Side
+		wantErr bool
+	}{
+		{name: "LONG", side: SideLong, wantErr: false},
+		{name: "SHORT", side: SideShort, wantErr: false},
+		{name: "Empty", side: "", wantErr: true},
+		{name: "Lowercase long", side: "long", wantErr: true},
+		{name: "Lowercase short", side: "short", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSide(tt.side)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateSide() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrInvalidSide) {
+				t.Errorf("ValidateSide() error = %v, want ErrInvalidSide", err)
+			}
+		})
+	}
+}
+
+func TestValidateStopLoss(t *testing.T) {
+	const entry = 45000.0
+
+	tests := []struct {
+		name     string
+		side     Side
+		stopLoss float64
+		epsilon  float64
+		wantErr  bool
+	}{
+		{name: "LONG valid stop", side: SideLong, stopLoss: 44500.0, epsilon: DefaultPriceEpsilon, wantErr: false},
+		{name: "LONG stop above entry", side: SideLong, stopLoss: 45500.0, epsilon: DefaultPriceEpsilon, wantErr: true},
+		{name: "LONG stop equals entry", side: SideLong, stopLoss: entry, epsilon: DefaultPriceEpsilon, wantErr: true},
+		{name: "LONG stop a nano-tick below entry", side: SideLong, stopLoss: entry - 1e-11, epsilon: DefaultPriceEpsilon, wantErr: true},
+		{name: "LONG stop a nano-tick above entry", side: SideLong, stopLoss: entry + 1e-11, epsilon: DefaultPriceEpsilon, wantErr: true},
+		{name: "LONG stop one cent below entry", side: SideLong, stopLoss: entry - 0.01, epsilon: DefaultPriceEpsilon, wantErr: false},
+		{name: "SHORT valid stop", side: SideShort, stopLoss: 45500.0, epsilon: DefaultPriceEpsilon, wantErr: false},
+		{name: "SHORT stop a nano-tick above entry", side: SideShort, stopLoss: entry + 1e-11, epsilon: DefaultPriceEpsilon, wantErr: true},
+		{name: "Zero epsilon keeps strict comparison", side: SideLong, stopLoss: entry - 1e-11, epsilon: 0, wantErr: false},
+		{name: "Wider epsilon rejects close stops", side: SideLong, stopLoss: entry - 0.01, epsilon: 1e-6, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStopLoss(tt.side, entry, tt.stopLoss, tt.epsilon)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStopLoss() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateStopLoss_FloatNoise(t *testing.T) {
+	// 0.1 + 0.2 != 0.3 in float64; both must be treated as the same price
+	if err := ValidateStopLoss(SideLong, 0.3, 0.1+0.2, DefaultPriceEpsilon); err == nil {
+		t.Error("ValidateStopLoss() error = nil, want effectively-equal error")
+	}
+	if err := ValidateStopLoss(SideShort, 0.1+0.2, 0.3, DefaultPriceEpsilon); err == nil {
+		t.Error("ValidateStopLoss() error = nil, want effectively-equal error")
+	}
+}
+
+func TestValidateTakeProfit(t *testing.T) {
+	const entry = 45000.0
+
+	tests := []struct {
+		name       string
+		side       Side
+		takeProfit float64
+		wantErr    bool
+	}{
+		{name: "LONG TP above entry", side: SideLong, takeProfit: 46000.0, wantErr: false},
+		{name: "LONG TP below entry", side: SideLong, takeProfit: 44000.0, wantErr: true},
+		{name: "LONG TP equals entry", side: SideLong, takeProfit: entry, wantErr: true},
+		{name: "LONG TP a nano-tick above entry", side: SideLong, takeProfit: entry + 1e-11, wantErr: true},
+		{name: "SHORT TP below entry", side: SideShort, takeProfit: 44000.0, wantErr: false},
+		{name: "SHORT TP above entry", side: SideShort, takeProfit: 46000.0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTakeProfit(tt.side, entry, tt.takeProfit, DefaultPriceEpsilon)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTakeProfit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLimitEntry(t *testing.T) {
+	const current = 45000.0
+
+	tests := []struct {
+		name    string
+		side    Side
+		entry   float64
+		current float64
+		wantErr bool
+	}{
+		{name: "LONG limit below market", side: SideLong, entry: 44000.0, current: current, wantErr: false},
+		{name: "LONG limit at market", side: SideLong, entry: current, current: current, wantErr: false},
+		{name: "LONG limit above market", side: SideLong, entry: 46000.0, current: current, wantErr: true},
+		{name: "SHORT limit above market", side: SideShort, entry: 46000.0, current: current, wantErr: false},
+		{name: "SHORT limit below market", side: SideShort, entry: 44000.0, current: current, wantErr: true},
+		{name: "Invalid current price", side: SideLong, entry: 44000.0, current: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLimitEntry(tt.side, tt.entry, tt.current)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLimitEntry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsSetupStale(t *testing.T) {
+	long := PositionParams{
+		Symbol:     "BTC-USDT",
+		Side:       SideLong,
+		EntryPrice: 45000.0,
+		StopLoss:   44500.0,
+		Params:     map[string]interface{}{TargetPriceParam: 46000.0},
+	}
+	short := PositionParams{
+		Symbol:     "ETH-USDT",
+		Side:       SideShort,
+		EntryPrice: 3000.0,
+		StopLoss:   3100.0,
+		Params:     map[string]interface{}{TargetPriceParam: 2800.0},
+	}
+	noTP := long
+	noTP.Params = nil
+
+	tests := []struct {
+		name       string
+		params     PositionParams
+		current    float64
+		wantStale  bool
+		wantReason string
+	}{
+		{name: "LONG between stop and TP", params: long, current: 44800.0, wantStale: false},
+		{name: "LONG below the stop", params: long, current: 44400.0, wantStale: true, wantReason: "below the stop"},
+		{name: "LONG at the stop", params: long, current: 44500.0, wantStale: true, wantReason: "below the stop"},
+		{name: "LONG above the TP", params: long, current: 46100.0, wantStale: true, wantReason: "above the take profit"},
+		{name: "LONG without TP only checks the stop", params: noTP, current: 46100.0, wantStale: false},
+		{name: "SHORT above the stop", params: short, current: 3150.0, wantStale: true, wantReason: "above the stop"},
+		{name: "SHORT below the TP", params: short, current: 2750.0, wantStale: true, wantReason: "below the take profit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stale, reason := IsSetupStale(tt.params, tt.current)
+			if stale != tt.wantStale {
+				t.Fatalf("IsSetupStale() = %v (%q), want %v", stale, reason, tt.wantStale)
+			}
+			if !strings.Contains(reason, tt.wantReason) || (!stale && reason != "") {
+				t.Errorf("IsSetupStale() reason = %q, want it to mention %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestValidateMargin(t *testing.T) {
+	tests := []struct {
+		name     string
+		notional float64
+		leverage int
+		balance  float64
+		wantErr  bool
+	}{
+		{name: "Margin within balance", notional: 9000, leverage: 10, balance: 1000, wantErr: false},
+		{name: "Margin exactly balance", notional: 9000, leverage: 9, balance: 1000, wantErr: false},
+		{name: "Clamped leverage leaves margin short", notional: 9000, leverage: 5, balance: 1000, wantErr: true},
+		{name: "Zero leverage", notional: 9000, leverage: 0, balance: 1000, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMargin(tt.notional, tt.leverage, tt.balance)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMargin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFiniteParams(t *testing.T) {
+	valid := PositionParams{EntryPrice: 45000.0, StopLoss: 44500.0, AccountBalance: 1000.0, RiskPercent: 2.0}
+	if err := ValidateFiniteParams(valid); err != nil {
+		t.Errorf("ValidateFiniteParams() error = %v, want nil", err)
+	}
+
+	for _, bad := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		params := valid
+		params.RiskPercent = bad
+		if err := ValidateFiniteParams(params); err == nil {
+			t.Errorf("ValidateFiniteParams() with risk %v error = nil, want error", bad)
+		}
+	}
+}
+
+func TestValidatePlanFinite(t *testing.T) {
+	if err := ValidatePlanFinite(testPlan()); err != nil {
+		t.Errorf("ValidatePlanFinite() error = %v, want nil", err)
+	}
+
+	plan := testPlan()
+	plan.TakeProfits[0].Price = math.Inf(1)
+	if err := ValidatePlanFinite(plan); err == nil {
+		t.Error("ValidatePlanFinite() with infinite TP error = nil, want error")
+	}
+
+	plan = testPlan()
+	plan.NotionalValue = math.NaN()
+	if err := ValidatePlanFinite(plan); err == nil {
+		t.Error("ValidatePlanFinite() with NaN notional error = nil, want error")
+	}
+}
@@ -0,0 +1,40 @@
+package strategy
+
+import "fmt"
+
+// WarningCode identifies an adjustment a strategy made while planning
+type WarningCode string
+
+const (
+	WarningLeverageClamped WarningCode = "leverage_clamped" // Required leverage exceeded the max
+	WarningLeverageSnapped WarningCode = "leverage_snapped" // Leverage moved to an allowed bracket
+	WarningNotionalCapped  WarningCode = "notional_capped"  // Size shrunk to fit a notional ceiling
+	WarningExposureCapped  WarningCode = "exposure_capped"  // Size shrunk so existing + new risk fits the cap
+	WarningMarginShrunk    WarningCode = "margin_shrunk"    // Size shrunk to the margin the max leverage allows
+)
+
+// Warning describes an adjustment made to a plan, for surfacing in a UI or log
+type Warning struct {
+	Code    WarningCode
+	Message string
+}
+
+// NewWarning creates a warning with a formatted message
+func NewWarning(code WarningCode, format string, args ...interface{}) Warning {
+	return Warning{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// String returns the warning message prefixed with its code
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Code, w.Message)
+}
+
+// HasWarning reports whether warnings contains one with code
+func HasWarning(warnings []Warning, code WarningCode) bool {
+	for _, w := range warnings {
+		if w.Code == code {
+			return true
+		}
+	}
+	return false
+}
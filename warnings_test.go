@@ -0,0 +1,18 @@
+package strategy
+
+import "testing"
+
+func TestWarning(t *testing.T) {
+	w := NewWarning(WarningLeverageClamped, "required leverage %dx clamped to max %dx", 90, 20)
+	if want := "leverage_clamped: required leverage 90x clamped to max 20x"; w.String() != want {
+		t.Errorf("String() = %q, want %q", w.String(), want)
+	}
+
+	warnings := []Warning{w}
+	if !HasWarning(warnings, WarningLeverageClamped) {
+		t.Error("HasWarning() = false, want true")
+	}
+	if HasWarning(warnings, WarningNotionalCapped) {
+		t.Error("HasWarning() = true for absent code, want false")
+	}
+}